@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rasalas/yeet/internal/ai"
+	"github.com/rasalas/yeet/internal/git"
+	"github.com/rasalas/yeet/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	amendMessage string
+	rewriteForce bool
+)
+
+func init() {
+	amendCmd.Flags().StringVarP(&amendMessage, "message", "m", "", "Use this message instead of generating one")
+	amendCmd.Flags().BoolVarP(&rewriteForce, "force", "f", false, "Amend even if HEAD has been pushed")
+	rewordCmd.Flags().BoolVarP(&rewriteForce, "force", "f", false, "Reword even if the commit has been pushed")
+	rootCmd.AddCommand(amendCmd)
+	rootCmd.AddCommand(rewordCmd)
+}
+
+var amendCmd = &cobra.Command{
+	Use:   "amend",
+	Short: "Fold the staged changes into the last commit and regenerate its message",
+	Long: "Amend HEAD with the staged changes (if any) and generate a new message from the combined diff. " +
+		"Refuses to rewrite a pushed commit unless --force is given.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return RunAsCommit("amend", args)
+		}
+		return runAmend(cmd, args)
+	},
+
+	SilenceUsage: true,
+}
+
+var rewordCmd = &cobra.Command{
+	Use:   "reword <commit>",
+	Short: "Regenerate the message of a past commit",
+	Long: "Generate a new message for a commit on the current branch from its diff and rewrite the branch " +
+		"with it, without an interactive rebase. Refuses to rewrite a pushed commit unless --force is given.",
+	Args: cobra.ExactArgs(1),
+	RunE: runReword,
+
+	SilenceUsage: true,
+}
+
+func runAmend(cmd *cobra.Command, args []string) error {
+	head, err := git.ResolveCommit("HEAD")
+	if err != nil {
+		return fmt.Errorf("no commit to amend")
+	}
+	pushedTo, err := checkRewritable(head, "amend")
+	if err != nil {
+		return err
+	}
+
+	// The amended commit's diff is the index against HEAD's parent.
+	base := git.EmptyTree
+	if parent, err := git.ResolveCommit("HEAD~1"); err == nil {
+		base = parent
+	}
+	stat, err := git.DiffStatCachedFrom(base)
+	if err != nil {
+		return fmt.Errorf("failed to get diff stat: %w", err)
+	}
+	fmt.Println()
+	if !git.HasStagedChanges() {
+		fmt.Printf("  %sNo staged changes — regenerating the message only.%s\n\n", term.Dim, term.Reset)
+	}
+	printStat(stat)
+
+	message, usage, streamed := amendMessage, (*ai.Usage)(nil), false
+	if message == "" {
+		message, usage, streamed, _, err = generateMessage(func() (ai.CommitContext, error) {
+			diff, err := git.DiffCachedFrom(base)
+			if err != nil {
+				return ai.CommitContext{}, fmt.Errorf("failed to get diff: %w", err)
+			}
+			return rewriteContext(head, diff), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	message, _, ok, err := confirmMessage(message, streamed, "amend")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Printf("  %sCancelled.%s\n", term.Dim, term.Reset)
+		return nil
+	}
+	out, err := git.Amend(message)
+	if err != nil {
+		return fmt.Errorf("amend failed: %s", out)
+	}
+	fmt.Printf("  %s✓%s %s\n", term.Green, term.Reset, firstLine(out))
+	printForcePushHint(pushedTo)
+	printPRUsage(usage)
+	return nil
+}
+
+func runReword(cmd *cobra.Command, args []string) error {
+	sha, err := git.ResolveCommit(args[0])
+	if err != nil {
+		return err
+	}
+	if !git.IsAncestor(sha, "HEAD") {
+		return fmt.Errorf("%s is not on the current branch", args[0])
+	}
+	pushedTo, err := checkRewritable(sha, "reword")
+	if err != nil {
+		return err
+	}
+
+	old, _ := git.CommitMessage(sha)
+	stat, err := git.CommitStat(sha)
+	if err != nil {
+		return fmt.Errorf("failed to get diff stat: %w", err)
+	}
+	fmt.Printf("\n  %sRewording%s %s %s%s%s\n\n", term.Bold, term.Reset, sha[:7], term.Dim, firstLine(old), term.Reset)
+	printStat(stat)
+
+	message, usage, streamed, _, err := generateMessage(func() (ai.CommitContext, error) {
+		diff, err := git.CommitDiff(sha)
+		if err != nil {
+			return ai.CommitContext{}, fmt.Errorf("failed to get diff: %w", err)
+		}
+		return rewriteContext(sha, diff), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	message, _, ok, err := confirmMessage(message, streamed, "reword")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Printf("  %sCancelled.%s\n", term.Dim, term.Reset)
+		return nil
+	}
+	if err := git.Reword(sha, message); err != nil {
+		return fmt.Errorf("reword failed: %w", err)
+	}
+	fmt.Printf("  %s✓%s Reworded %s: %s\n", term.Green, term.Reset, sha[:7], firstLine(message))
+	printForcePushHint(pushedTo)
+	printPRUsage(usage)
+	return nil
+}
+
+// checkRewritable refuses to rewrite a commit that is on a remote branch,
+// unless --force is given. It returns the remote branches the commit is on.
+func checkRewritable(sha, verb string) ([]string, error) {
+	pushedTo, err := git.RemoteBranchesContaining(sha)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check whether %s is pushed: %w", sha[:7], err)
+	}
+	if len(pushedTo) > 0 && !rewriteForce {
+		return nil, fmt.Errorf("%s is already pushed to %s — use --force to %s it anyway and force-push afterwards",
+			sha[:7], strings.Join(pushedTo, ", "), verb)
+	}
+	return pushedTo, nil
+}
+
+// printForcePushHint reminds that rewritten pushed commits need a force push.
+func printForcePushHint(pushedTo []string) {
+	if len(pushedTo) > 0 {
+		fmt.Printf("  %s!%s History of %s was rewritten — push with: git push --force-with-lease\n", term.Yellow, term.Reset, strings.Join(pushedTo, ", "))
+	}
+}
+
+// rewriteContext is the generation context for a new message of an
+// existing commit. The commit's own old message is left out of the recent
+// commits, so the model doesn't just repeat it.
+func rewriteContext(sha, diff string) ai.CommitContext {
+	branch, _ := git.CurrentBranch()
+	recent, _ := git.LogOneline()
+	var others []string
+	for _, line := range strings.Split(recent, "\n") {
+		hash, _, _ := strings.Cut(line, " ")
+		if hash != "" && !strings.HasPrefix(sha, hash) {
+			others = append(others, line)
+		}
+	}
+	return ai.CommitContext{
+		Diff:          diff,
+		Branch:        branch,
+		RecentCommits: strings.Join(others, "\n"),
+	}
+}
+
+// printStat prints a diff stat, indented and colorized.
+func printStat(stat string) {
+	for _, line := range strings.Split(stat, "\n") {
+		fmt.Println("  " + term.ColorizeDiffStat(line))
+	}
+	fmt.Println()
+}
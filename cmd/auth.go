@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/rasalas/yeet/internal/ai"
 	"github.com/rasalas/yeet/internal/config"
 	"github.com/rasalas/yeet/internal/keyring"
 	"github.com/rasalas/yeet/internal/term"
@@ -24,14 +28,14 @@ var authCmd = &cobra.Command{
 }
 
 var authSetCmd = &cobra.Command{
-	Use:   "set <provider>",
+	Use:   "set <provider> [--name <slot>]",
 	Short: "Store an API key in the OS keyring",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runAuthSet,
 }
 
 var authDeleteCmd = &cobra.Command{
-	Use:   "delete <provider>",
+	Use:   "delete <provider> [--name <slot>]",
 	Short: "Remove an API key from the OS keyring",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runAuthDelete,
@@ -39,7 +43,7 @@ var authDeleteCmd = &cobra.Command{
 
 var authImportCmd = &cobra.Command{
 	Use:   "import [provider]",
-	Short: "Import keys from env/opencode into OS keyring",
+	Short: "Import keys from env vars and other tools (opencode, llm, aider, Continue, shell rc) into the keyring",
 	Args:  cobra.MaximumNArgs(1),
 	RunE:  runAuthImport,
 }
@@ -50,37 +54,112 @@ var authResetCmd = &cobra.Command{
 	RunE:  runAuthReset,
 }
 
+var authTestCmd = &cobra.Command{
+	Use:   "test [provider]",
+	Short: "Check that stored API keys work",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runAuthTest,
+
+	SilenceUsage: true,
+}
+
+var authForgeCmd = &cobra.Command{
+	Use:   "forge <host>",
+	Short: "Store a forge API token (e.g. for github.com) in the keyring",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAuthForge,
+
+	SilenceUsage: true,
+}
+
+var authMigrateCmd = &cobra.Command{
+	Use:   "migrate <system|file>",
+	Short: "Move stored keys to another keyring backend",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAuthMigrate,
+
+	SilenceUsage: true,
+}
+
+var (
+	authImportDryRun  bool
+	authImportProject bool
+	authSlotName      string
+)
+
 func init() {
+	authImportCmd.Flags().BoolVar(&authImportDryRun, "dry-run", false, "List the keys that would be imported and where they come from")
+	authImportCmd.Flags().BoolVar(&authImportProject, "project", false, "Also import keys from .env and .aider.conf.yml in the current directory")
+	authSetCmd.Flags().StringVar(&authSlotName, "name", "", "Store the key in a named slot, e.g. work or personal")
+	authDeleteCmd.Flags().StringVar(&authSlotName, "name", "", "Remove a named slot instead of the default key")
 	authCmd.AddCommand(authSetCmd)
 	authCmd.AddCommand(authDeleteCmd)
 	authCmd.AddCommand(authImportCmd)
 	authCmd.AddCommand(authResetCmd)
+	authCmd.AddCommand(authMigrateCmd)
+	authCmd.AddCommand(authTestCmd)
+	authCmd.AddCommand(authForgeCmd)
 	rootCmd.AddCommand(authCmd)
 }
 
 func runAuthStatus(cmd *cobra.Command, args []string) error {
 	cfg, _ := config.Load()
 	providers := cfg.AllProviders()
-	status := keyring.Status(providers, cfg.CustomEnvs())
+	status := keyring.Status(providers, cfg.KeyLookups())
 
 	fmt.Printf("\n  %sAPI Keys%s\n\n", term.Bold, term.Reset)
 	for _, p := range providers {
 		info := status[p]
 		if info.Found {
 			source := string(info.Source)
+			if info.Slot != "" && info.Slot != keyring.DefaultSlot {
+				source += " (" + info.Slot + ")"
+			}
 			line := fmt.Sprintf("  %s\u2713%s  %-16s%s%s%s", term.Green, term.Reset, p, term.Dim, source, term.Reset)
-			if info.Source != keyring.SourceKeyring {
+			if info.Source != keyring.SourceKeyring && info.Source != keyring.SourceCommand {
 				line += fmt.Sprintf("  %s\u2190 yeet auth import %s%s", term.Dim, p, term.Reset)
 			}
 			fmt.Println(line)
 		} else {
 			fmt.Printf("  %s\u2717%s  %s\n", term.Red, term.Reset, p)
 		}
+		if info.CmdErr != nil {
+			fmt.Printf("     %s%v%s\n", term.Red, info.CmdErr, term.Reset)
+		}
+		printSlots(p, info.Slot, cfg.Keys[p])
 	}
 	fmt.Println()
 	return nil
 }
 
+// printSlots lists a provider's stored key slots when it has named ones,
+// marking the slot in use (or the selected one, if it is missing).
+func printSlots(provider, used, selected string) {
+	slots := keyring.Slots(provider)
+	if selected == "" && (len(slots) == 0 || len(slots) == 1 && slots[0].Name == keyring.DefaultSlot) {
+		return
+	}
+	if used == "" {
+		used = selected
+	}
+	stored := false
+	for _, s := range slots {
+		stored = stored || s.Name == selected
+		mark := " "
+		if s.Name == used {
+			mark = "\u25b8"
+		}
+		lastUsed := "never used"
+		if !s.LastUsed.IsZero() {
+			lastUsed = "last used " + s.LastUsed.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("     %s %-12s%s%s%s\n", mark, s.Name, term.Dim, lastUsed, term.Reset)
+	}
+	if selected != "" && !stored {
+		fmt.Printf("     \u25b8 %-12s%snot stored  \u2190 yeet auth set %s --name %s%s\n", selected, term.Red, provider, selected, term.Reset)
+	}
+}
+
 func runAuthSet(cmd *cobra.Command, args []string) error {
 	provider := strings.ToLower(args[0])
 	providers := allProviders()
@@ -88,12 +167,22 @@ func runAuthSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("unknown provider: %s (valid: %s)", provider, strings.Join(providers, ", "))
 	}
 
-	return readAndSaveKey(provider)
+	if authSlotName != "" {
+		if err := keyring.ValidSlotName(authSlotName); err != nil {
+			return err
+		}
+	}
+	return readAndSaveKey(provider, authSlotName)
 }
 
-// readAndSaveKey prompts for an API key, trims it, and stores it in the keyring.
-func readAndSaveKey(provider string) error {
-	fmt.Printf("  Enter API key for %s: ", provider)
+// readAndSaveKey prompts for an API key, trims it, and stores it in the
+// keyring, in the named slot if one is given.
+func readAndSaveKey(provider, slot string) error {
+	label := provider
+	if slot != "" {
+		label += " (" + slot + ")"
+	}
+	fmt.Printf("  Enter API key for %s: ", label)
 	key, err := goterm.ReadPassword(int(os.Stdin.Fd()))
 	fmt.Println()
 	if err != nil {
@@ -105,14 +194,122 @@ func readAndSaveKey(provider string) error {
 		return fmt.Errorf("empty key, nothing saved")
 	}
 
-	if err := keyring.Set(provider, apiKey); err != nil {
+	if err := keyring.SetSlot(provider, slot, apiKey); err != nil {
 		return fmt.Errorf("failed to save key: %w", err)
 	}
 
-	fmt.Printf("  %s\u2713%s Key saved for %s.\n", term.Green, term.Reset, provider)
+	fmt.Printf("  %s\u2713%s Key saved for %s.\n", term.Green, term.Reset, label)
+
+	// Check the key just stored, even if the config selects another slot.
+	cfg, _ := config.Load()
+	keys := make(map[string]string, len(cfg.Keys)+1)
+	for k, v := range cfg.Keys {
+		keys[k] = v
+	}
+	keys[provider] = slot
+	cfg.Keys = keys
+	fmt.Printf("  %sChecking key…%s\r", term.Dim, term.Reset)
+	printKeyCheck(provider, checkKey(cfg, provider))
+	return nil
+}
+
+// runAuthForge stores a token for `yeet pr` under the forge's host name.
+func runAuthForge(cmd *cobra.Command, args []string) error {
+	host := strings.ToLower(strings.TrimSuffix(args[0], "/"))
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+
+	fmt.Printf("  Enter API token for %s: ", host)
+	token, err := goterm.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return fmt.Errorf("failed to read token: %w", err)
+	}
+	value := strings.TrimSpace(string(token))
+	if value == "" {
+		return fmt.Errorf("empty token, nothing saved")
+	}
+	if err := keyring.Set(host, value); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	fmt.Printf("  %s\u2713%s Token saved for %s.\n", term.Green, term.Reset, host)
 	return nil
 }
 
+func runAuthTest(cmd *cobra.Command, args []string) error {
+	cfg, _ := config.Load()
+
+	var targets []string
+	if len(args) == 1 {
+		p := strings.ToLower(args[0])
+		providers := cfg.AllProviders()
+		if !isValidProvider(p, providers) {
+			return fmt.Errorf("unknown provider: %s (valid: %s)", p, strings.Join(providers, ", "))
+		}
+		targets = []string{p}
+	} else {
+		// Without an argument, test every provider that has a key.
+		status := keyring.Status(cfg.AllProviders(), cfg.KeyLookups())
+		for _, p := range cfg.AllProviders() {
+			if status[p].Found {
+				targets = append(targets, p)
+			}
+		}
+		if len(targets) == 0 {
+			fmt.Printf("  %sNo API keys configured. Run %syeet auth set <provider>%s first.%s\n", term.Dim, term.Reset+term.Bold, term.Reset+term.Dim, term.Reset)
+			return nil
+		}
+	}
+
+	results := make([]ai.KeyCheck, len(targets))
+	var wg sync.WaitGroup
+	for i, p := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = checkKey(cfg, p)
+		}()
+	}
+	wg.Wait()
+
+	fmt.Println()
+	failed := 0
+	for i, p := range targets {
+		printKeyCheck(p, results[i])
+		if results[i].Status != ai.KeyValid {
+			failed++
+		}
+	}
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d key(s) failed the check", failed, len(targets))
+	}
+	return nil
+}
+
+// checkKey runs ai.CheckKey with a short timeout.
+func checkKey(cfg config.Config, provider string) ai.KeyCheck {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return ai.CheckKey(ctx, provider, cfg)
+}
+
+func printKeyCheck(provider string, c ai.KeyCheck) {
+	mark, color := "\u2717", term.Red
+	switch c.Status {
+	case ai.KeyValid:
+		mark, color = "\u2713", term.Green
+	case ai.KeyNoAccess, ai.KeyNetwork:
+		mark, color = "!", term.Yellow
+	}
+	line := fmt.Sprintf("  %s%s%s  %-16s%s", color, mark, term.Reset, provider, c.Status)
+	if c.Detail != "" {
+		line += fmt.Sprintf("  %s%s%s", term.Dim, c.Detail, term.Reset)
+	}
+	fmt.Println(line)
+}
+
 func runAuthDelete(cmd *cobra.Command, args []string) error {
 	provider := strings.ToLower(args[0])
 	providers := allProviders()
@@ -120,17 +317,24 @@ func runAuthDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("unknown provider: %s (valid: %s)", provider, strings.Join(providers, ", "))
 	}
 
-	if err := keyring.Delete(provider); err != nil {
+	label := provider
+	if authSlotName != "" {
+		if err := keyring.ValidSlotName(authSlotName); err != nil {
+			return err
+		}
+		label += " (" + authSlotName + ")"
+	}
+	if err := keyring.DeleteSlot(provider, authSlotName); err != nil {
 		return fmt.Errorf("failed to delete key: %w", err)
 	}
 
-	fmt.Printf("  %s\u2713%s API key for %s removed from keyring.\n", term.Green, term.Reset, provider)
+	fmt.Printf("  %s\u2713%s API key for %s removed from keyring.\n", term.Green, term.Reset, label)
 	return nil
 }
 
 func runAuthImport(cmd *cobra.Command, args []string) error {
 	cfg, _ := config.Load()
-	envs := cfg.CustomEnvs()
+	lookups := cfg.KeyLookups()
 
 	var targets []string
 	if len(args) == 1 {
@@ -144,9 +348,26 @@ func runAuthImport(cmd *cobra.Command, args []string) error {
 		targets = cfg.AllProviders()
 	}
 
-	imported := 0
+	// Keys in the current directory's files are never used for lookups, so
+	// they are only imported when asked for.
+	project := keyring.ProjectKeys()
+	imported, skipped := 0, 0
 	for _, p := range targets {
-		key, source := keyring.Resolve(p, envs[p])
+		// Imports always go to the default slot.
+		l := lookups[p]
+		l.Slot = ""
+		origin := keyring.Locate(p, l)
+		if origin.Key == "" {
+			if k, ok := keyring.MatchImported(project, p, l); ok {
+				if !authImportProject {
+					fmt.Printf("  %s\u00b7%s %-16s%s  %s%s  not imported without --project%s\n", term.Dim, term.Reset, p, maskKey(k.Key), term.Dim, k.Path, term.Reset)
+					skipped++
+					continue
+				}
+				origin = keyring.Origin{Key: k.Key, Source: keyring.SourceAider, Where: k.Path}
+			}
+		}
+		key, source := origin.Key, origin.Source
 		if key == "" {
 			if len(args) == 1 {
 				fmt.Printf("  %s\u2717%s %s: no key found to import\n", term.Red, term.Reset, p)
@@ -159,15 +380,32 @@ func runAuthImport(cmd *cobra.Command, args []string) error {
 			}
 			continue
 		}
+		if source == keyring.SourceCommand {
+			if len(args) == 1 {
+				fmt.Printf("  %s\u00b7%s %s: provided by key_cmd, not copied\n", term.Dim, term.Reset, p)
+			}
+			continue
+		}
+		if authImportDryRun {
+			fmt.Printf("  %s\u00b7%s %-16s%s  %s%s  %s%s\n", term.Dim, term.Reset, p, maskKey(key), term.Dim, source, origin.Where, term.Reset)
+			imported++
+			continue
+		}
 		if err := keyring.Set(p, key); err != nil {
 			fmt.Printf("  %s\u2717%s %s: failed to import: %v\n", term.Red, term.Reset, p, err)
 			continue
 		}
-		fmt.Printf("  %s\u2713%s %s: imported from %s to keyring\n", term.Green, term.Reset, p, source)
+		fmt.Printf("  %s\u2713%s %s: imported from %s %s(%s)%s to keyring\n", term.Green, term.Reset, p, source, term.Dim, origin.Where, term.Reset)
 		imported++
 	}
 
-	if len(args) == 0 && imported == 0 {
+	if authImportDryRun && imported > 0 {
+		fmt.Printf("\n  %sRun without --dry-run to import %d key(s).%s\n", term.Dim, imported, term.Reset)
+	}
+	if skipped > 0 {
+		fmt.Printf("\n  %s%d key(s) in the current directory's files skipped — pass --project to import them.%s\n", term.Dim, skipped, term.Reset)
+	}
+	if len(args) == 0 && imported == 0 && skipped == 0 {
 		fmt.Printf("  %sNothing to import.%s\n", term.Dim, term.Reset)
 	}
 
@@ -179,8 +417,12 @@ func runAuthReset(cmd *cobra.Command, args []string) error {
 	providers := cfg.AllProviders()
 	deleted := 0
 	for _, p := range providers {
-		if err := keyring.Delete(p); err == nil {
-			fmt.Printf("  %s\u2713%s %s removed\n", term.Green, term.Reset, p)
+		for _, slot := range keyring.DeleteAll(p) {
+			if slot == keyring.DefaultSlot {
+				fmt.Printf("  %s\u2713%s %s removed\n", term.Green, term.Reset, p)
+			} else {
+				fmt.Printf("  %s\u2713%s %s (%s) removed\n", term.Green, term.Reset, p, slot)
+			}
 			deleted++
 		}
 	}
@@ -190,6 +432,52 @@ func runAuthReset(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runAuthMigrate(cmd *cobra.Command, args []string) error {
+	to, err := keyring.BackendByName(strings.ToLower(args[0]))
+	if err != nil {
+		return err
+	}
+	if to.Name() == keyring.BackendSystem && !keyring.SystemAvailable() {
+		return fmt.Errorf("OS keyring is not available on this machine")
+	}
+	from, err := keyring.Active()
+	if err != nil {
+		return err
+	}
+	if from.Name() == to.Name() {
+		fmt.Printf("  %sKeys are already stored in the %s backend (%s).%s\n", term.Dim, to.Name(), to.Location(), term.Reset)
+		return nil
+	}
+
+	names := allProviders()
+	for _, name := range keyring.Stored() {
+		if !isValidProvider(name, names) {
+			names = append(names, name)
+		}
+	}
+	moved, err := keyring.Migrate(from, to, keyring.Accounts(names))
+	for _, p := range moved {
+		fmt.Printf("  %s\u2713%s %s moved to %s\n", term.Green, term.Reset, p, to.Name())
+	}
+	if err != nil {
+		return fmt.Errorf("migration stopped: %w", err)
+	}
+	if len(moved) == 0 {
+		fmt.Printf("  %sNo keys in the %s backend.%s\n", term.Dim, from.Name(), term.Reset)
+		return nil
+	}
+	fmt.Printf("  %sKeys are now stored in %s.%s\n", term.Dim, to.Location(), term.Reset)
+	return nil
+}
+
+// maskKey shortens a key for display, keeping only its first and last characters.
+func maskKey(key string) string {
+	if len(key) <= 12 {
+		return strings.Repeat("*", len(key))
+	}
+	return key[:4] + "…" + key[len(key)-4:]
+}
+
 func allProviders() []string {
 	cfg, _ := config.Load()
 	return cfg.AllProviders()
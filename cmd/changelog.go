@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rasalas/yeet/internal/ai"
+	"github.com/rasalas/yeet/internal/changelog"
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/forge"
+	"github.com/rasalas/yeet/internal/git"
+	"github.com/rasalas/yeet/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	changelogVersion string
+	changelogWrite   bool
+	changelogFile    string
+	changelogNoAI    bool
+)
+
+func init() {
+	changelogCmd.Flags().StringVar(&changelogVersion, "version", "", "Version heading (default: <to> when it is a version tag, else Unreleased)")
+	changelogCmd.Flags().BoolVarP(&changelogWrite, "write", "w", false, "Prepend the notes to the changelog file after a preview")
+	changelogCmd.Flags().StringVar(&changelogFile, "file", "CHANGELOG.md", "Changelog file, relative to the repository root")
+	changelogCmd.Flags().BoolVar(&changelogNoAI, "no-ai", false, "List the commit subjects as they are instead of having the AI write the notes")
+	rootCmd.AddCommand(changelogCmd)
+}
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog <from>..<to>",
+	Short: "Generate release notes between two refs",
+	Long: "Collect the commits between two refs (<from> alone means <from>..HEAD), title them with their PRs " +
+		"where the forge knows them, group them by conventional commit type and have the AI write " +
+		"Keep a Changelog notes. The Markdown goes to stdout, or with --write into CHANGELOG.md.",
+	Args: cobra.ExactArgs(1),
+	RunE: runChangelog,
+
+	SilenceUsage: true,
+}
+
+func runChangelog(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	from, to, _ := strings.Cut(args[0], "..")
+	to = strings.TrimPrefix(to, ".") // a...b means the same for a log
+	if from == "" {
+		return fmt.Errorf("missing <from> in %q", args[0])
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	version := changelogVersion
+	if version == "" {
+		version = versionFromRef(to)
+	}
+	// Without --write the Markdown is the output, so it isn't mixed with progress.
+	quiet := !changelogWrite
+
+	entries, err := changelogEntries(cfg, from, to, !quiet)
+	if err != nil {
+		return err
+	}
+	sections, groups := changelog.Group(entries)
+	if len(sections) == 0 {
+		if !quiet {
+			fmt.Printf("\n  %sNo user-facing changes between %s and %s.%s\n\n", term.Dim, from, to, term.Reset)
+		}
+		return nil
+	}
+
+	notes, usage, err := releaseNotes(cfg, sections, groups, !quiet)
+	if err != nil {
+		return err
+	}
+	// A release that is already tagged is dated by its tag, not by today.
+	date := time.Now().Format("2006-01-02")
+	if tagged, err := git.TagDate(to); err == nil {
+		date = tagged
+	}
+	release := changelog.Render(version, date, notes.sections, notes.items)
+	if quiet {
+		fmt.Print(release)
+		return nil
+	}
+
+	root, err := git.RepoRoot()
+	if err != nil {
+		return fmt.Errorf("failed to find repository root: %w", err)
+	}
+	path := changelogFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	release, ok, err := confirmRelease(release, "write "+changelogFile)
+	if err != nil || !ok {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(changelog.Prepend(string(existing), release)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", changelogFile, err)
+	}
+	fmt.Printf("  %s✓%s [%s] added to %s\n", term.Green, term.Reset, version, changelogFile)
+	printPRUsage(usage)
+	return nil
+}
+
+// versionRe matches tags that name a version, like v1.2.0 or 2.0.0-rc.1.
+var versionRe = regexp.MustCompile(`^v?\d+\.\d+`)
+
+// versionFromRef returns the changelog heading for the end of a range: the
+// version of a version tag, else Unreleased.
+func versionFromRef(ref string) string {
+	if versionRe.MatchString(ref) {
+		return strings.TrimPrefix(ref, "v")
+	}
+	return changelog.Unreleased
+}
+
+// changelogEntries reads the commits between two refs — the whole history
+// of to when from is empty — and titles them with their PRs when the forge
+// can look them up.
+func changelogEntries(cfg config.Config, from, to string, verbose bool) ([]changelog.Entry, error) {
+	log, err := git.LogBetween(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits %s..%s: %s", from, to, log)
+	}
+	entries := changelog.Parse(log)
+	if verbose {
+		since := from
+		if since == "" {
+			since = "the first commit"
+		}
+		fmt.Printf("\n  %s%d commit(s) between %s and %s%s\n", term.Dim, len(entries), since, to, term.Reset)
+	}
+	titlePRs(cfg, entries, verbose)
+	return entries, nil
+}
+
+// titlePRs replaces the subjects of commits that reference a PR with the
+// PR's title. Without a forge, or when a PR can't be found, the commit
+// subject stays.
+func titlePRs(cfg config.Config, entries []changelog.Entry, verbose bool) {
+	n := 0
+	for _, e := range entries {
+		if e.PR > 0 {
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+	target, push := prRemotes(cfg)
+	f, err := detectForge(cfg, target, push)
+	if err != nil {
+		return
+	}
+	getter, ok := f.(forge.Getter)
+	if !ok {
+		return
+	}
+
+	var s term.Spinner
+	if verbose {
+		s.Start(fmt.Sprintf("Looking up %d %s PR(s)...", n, f.Name()))
+	}
+	titles := map[int]string{}
+	failed := 0
+	for i, e := range entries {
+		if e.PR == 0 {
+			continue
+		}
+		title, seen := titles[e.PR]
+		if !seen {
+			if pr, err := getter.GetPR(e.PR); err == nil && pr != nil {
+				title = pr.Title
+			} else {
+				failed++
+			}
+			titles[e.PR] = title
+		}
+		if title != "" {
+			entries[i] = e.WithTitle(title)
+		}
+	}
+	if verbose {
+		s.Stop()
+		if failed > 0 {
+			fmt.Printf("  %s!%s %d PR(s) not found on %s — using the commit subjects\n", term.Yellow, term.Reset, failed, f.Name())
+		}
+	}
+}
+
+// notes are release notes by section, in release order.
+type notes struct {
+	sections []string
+	items    map[string][]string
+}
+
+// releaseNotes has the AI rewrite the grouped commits into user-facing
+// notes, or with --no-ai lists them as they are.
+func releaseNotes(cfg config.Config, sections []string, groups map[string][]changelog.Entry, spin bool) (notes, *ai.Usage, error) {
+	outline := changelog.Outline(sections, groups)
+	if changelogNoAI {
+		var n notes
+		n.sections, n.items = changelog.ParseNotes(outline)
+		return n, nil, nil
+	}
+
+	ctx := ai.CommitContext{
+		RecentCommits: outline,
+		SystemPrompt:  ai.ChangelogPrompt,
+		MaxTokens:     2048,
+	}
+	label := ""
+	if spin {
+		label = "Writing release notes..."
+	}
+	reply, usage, err := generateText(cfg, ctx, label)
+	if err != nil {
+		return notes{}, nil, fmt.Errorf("AI release notes failed: %w", err)
+	}
+	var n notes
+	n.sections, n.items = changelog.ParseNotes(reply)
+	if len(n.sections) == 0 {
+		return notes{}, usage, fmt.Errorf("AI returned no release notes")
+	}
+	return n, usage, nil
+}
+
+// confirmRelease previews rendered release notes as a card and lets the
+// user accept (with action as the hint), edit or cancel them. With -y the
+// notes are accepted as they are.
+func confirmRelease(release, action string) (string, bool, error) {
+	for {
+		displayRelease(release)
+		if yesFlag {
+			return release, true, nil
+		}
+		term.PrintHintActions([]term.HintAction{
+			{Key: "enter", Desc: action},
+			{Key: "E", Desc: "editor"},
+			{Key: "q", Desc: "cancel"},
+		}, term.TerminalWidth())
+
+		a, err := term.WaitForAction()
+		if err != nil {
+			return "", false, err
+		}
+		switch a {
+		case term.ActionCancel:
+			fmt.Printf("\n  %sCancelled.%s\n", term.Dim, term.Reset)
+			return "", false, nil
+		case term.ActionEdit, term.ActionEditExternal:
+			edited, err := term.EditExternal(release)
+			if err != nil {
+				fmt.Printf("\n  Editor failed: %v\n", err)
+			} else {
+				release = strings.TrimSpace(edited) + "\n"
+			}
+			continue
+		}
+		fmt.Println()
+		return release, true, nil
+	}
+}
+
+// displayRelease shows rendered release notes as a card titled with their
+// version heading.
+func displayRelease(release string) {
+	heading, body, _ := strings.Cut(strings.TrimSpace(release), "\n")
+	fmt.Println()
+	term.DisplayCard(strings.TrimPrefix(heading, "## "), strings.TrimSpace(body))
+}
@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/rasalas/yeet/internal/config"
 	"github.com/rasalas/yeet/internal/term"
+	"github.com/rasalas/yeet/internal/textdiff"
 	"github.com/rasalas/yeet/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -46,7 +49,205 @@ var configEditCmd = &cobra.Command{
 	},
 }
 
+var (
+	configListJSON      bool
+	configMigrateDryRun bool
+)
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a config value (e.g. providers.together.url)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+
+	SilenceUsage: true,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a config value in config.toml",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+
+	SilenceUsage: true,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a config value or table from config.toml",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUnset,
+
+	SilenceUsage: true,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list [prefix]",
+	Short: "List effective config values (after profile and YEET_* overrides)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runConfigList,
+
+	SilenceUsage: true,
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade config.toml to the current schema version",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigMigrate,
+
+	SilenceUsage: true,
+}
+
 func init() {
+	configListCmd.Flags().BoolVar(&configListJSON, "json", false, "Print as JSON")
+	configMigrateCmd.Flags().BoolVar(&configMigrateDryRun, "dry-run", false, "Show the changes without writing them")
+
 	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configMigrateCmd)
 	rootCmd.AddCommand(configCmd)
 }
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	value, err := cfg.Get(args[0])
+	if err != nil {
+		return err
+	}
+	if table, ok := value.(map[string]any); ok {
+		for _, k := range config.SortedKeys(table) {
+			fmt.Printf("%s = %s\n", k, config.FormatValue(table[k]))
+		}
+		return nil
+	}
+	fmt.Println(config.FormatValue(value))
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+	return updateConfigFile(key, func(cfg *config.Config) error {
+		return cfg.Set(key, value)
+	}, fmt.Sprintf("%s = %s", key, value))
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	return updateConfigFile(key, func(cfg *config.Config) error {
+		return cfg.Unset(key)
+	}, fmt.Sprintf("%s unset", key))
+}
+
+// updateConfigFile applies change to config.toml as written (no profile or env
+// overrides), rejects it if Validate reports new errors, and saves.
+func updateConfigFile(key string, change func(*config.Config) error, done string) error {
+	cfg, err := config.LoadFile()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	before := make(map[string]bool)
+	for _, p := range cfg.Errors() {
+		before[p] = true
+	}
+
+	if err := change(&cfg); err != nil {
+		return err
+	}
+
+	var introduced []string
+	for _, p := range cfg.Errors() {
+		if !before[p] {
+			introduced = append(introduced, p)
+		}
+	}
+	if len(introduced) > 0 {
+		return fmt.Errorf("rejected %s: %s", key, strings.Join(introduced, "; "))
+	}
+
+	if err := config.Save(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Printf("  %s\u2713%s %s\n", term.Green, term.Reset, done)
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	values := cfg.Flatten()
+	if len(args) == 1 {
+		prefix := args[0]
+		for k := range values {
+			if k != prefix && !strings.HasPrefix(k, prefix+".") {
+				delete(values, k)
+			}
+		}
+	}
+
+	if configListJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(values)
+	}
+
+	for _, k := range config.SortedKeys(values) {
+		fmt.Printf("%s = %s\n", k, config.FormatValue(values[k]))
+	}
+	return nil
+}
+
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	m, err := config.PlanMigration()
+	if err != nil {
+		return fmt.Errorf("failed to plan migration: %w", err)
+	}
+	if m.After == m.Before {
+		fmt.Printf("  %s\u2713%s config.toml is up to date (version %d)\n", term.Green, term.Reset, m.To)
+		return nil
+	}
+
+	fmt.Printf("\n  %sMigrating%s %s %s(version %d \u2192 %d)%s\n\n", term.Bold, term.Reset, m.Path, term.Dim, m.From, m.To, term.Reset)
+	for _, step := range m.Steps {
+		fmt.Printf("  %s\u00b7%s %s\n", term.Dim, term.Reset, step)
+	}
+	fmt.Println()
+	printDiff(m.Before, m.After)
+
+	if configMigrateDryRun {
+		fmt.Printf("\n  %sDry run \u2014 nothing written.%s\n", term.Dim, term.Reset)
+		return nil
+	}
+	if err := m.Apply(); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	fmt.Printf("\n  %s\u2713%s Migrated to version %d %s(backup: %s.bak)%s\n", term.Green, term.Reset, m.To, term.Dim, m.Path, term.Reset)
+	return nil
+}
+
+// printDiff prints a colored unified diff of two file versions.
+func printDiff(before, after string) {
+	for _, h := range textdiff.Hunks(before, after, 3) {
+		fmt.Printf("  %s%s%s\n", term.Dim, h.Header(), term.Reset)
+		for _, l := range h.Lines {
+			switch l.Kind {
+			case textdiff.Delete:
+				fmt.Printf("  %s-%s%s\n", term.Red, l.Text, term.Reset)
+			case textdiff.Insert:
+				fmt.Printf("  %s+%s%s\n", term.Green, l.Text, term.Reset)
+			default:
+				fmt.Printf("   %s\n", l.Text)
+			}
+		}
+	}
+}
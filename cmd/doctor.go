@@ -2,6 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/rasalas/yeet/internal/ai"
 	"github.com/rasalas/yeet/internal/config"
@@ -46,14 +50,40 @@ func runDoctor() error {
 	fmt.Println()
 	fmt.Printf("  %sProvider%s  %s\n", term.Bold, term.Reset, provider)
 	fmt.Printf("  %sModel%s     %s\n", term.Bold, term.Reset, model)
+	if names := cfg.ProfileNames(); len(names) > 0 || cfg.ActiveProfile != "" {
+		active := cfg.ActiveProfile
+		if active == "" {
+			active = "(none)"
+		} else {
+			active += fmt.Sprintf(" %s(%s)%s", term.Dim, cfg.ProfileSource, term.Reset)
+		}
+		fmt.Printf("  %sProfile%s   %s\n", term.Bold, term.Reset, active)
+		if len(names) > 0 {
+			fmt.Printf("  %sProfiles%s  %s%s%s\n", term.Bold, term.Reset, term.Dim, strings.Join(names, ", "), term.Reset)
+		}
+	}
 
 	// Config path
 	if path, err := config.Path(); err == nil {
 		fmt.Printf("  %sConfig%s    %s%s%s\n", term.Bold, term.Reset, term.Dim, path, term.Reset)
 	}
 
+	// Keyring backend
+	if b, err := keyring.Active(); err == nil {
+		note := b.Location()
+		if b.Name() == keyring.BackendFile && !keyring.SystemAvailable() {
+			note += ", OS keyring unavailable"
+		}
+		fmt.Printf("  %sKeyring%s   %s %s(%s)%s\n", term.Bold, term.Reset, b.Name(), term.Dim, note, term.Reset)
+		if err := keyring.Check(b); err != nil {
+			fmt.Printf("            %s%v%s\n", term.Red, err, term.Reset)
+		}
+	} else {
+		fmt.Printf("  %sKeyring%s   %s%v%s\n", term.Bold, term.Reset, term.Red, err, term.Reset)
+	}
+
 	// Validation
-	problems := cfg.Validate()
+	problems := append(cfg.Validate(), setupProblems(cfg)...)
 	if len(problems) > 0 {
 		fmt.Printf("\n  %sWarnings%s\n\n", term.Bold, term.Reset)
 		for _, p := range problems {
@@ -63,8 +93,8 @@ func runDoctor() error {
 
 	// Key status
 	providers := cfg.AllProviders()
-	envs := cfg.CustomEnvs()
-	status := keyring.Status(providers, envs)
+	lookups := cfg.KeyLookups()
+	status := keyring.Status(providers, lookups)
 
 	fmt.Printf("\n  %sKeys%s\n\n", term.Bold, term.Reset)
 	for _, p := range providers {
@@ -76,10 +106,17 @@ func runDoctor() error {
 				fmt.Printf("  %s\u2713%s  %-16s%s%s%s\n", term.Green, term.Reset, p, term.Dim, info.Source, term.Reset)
 			} else {
 				hint := fmt.Sprintf("yeet auth set %s", p)
-				if envName, ok := envs[p]; ok {
-					hint = fmt.Sprintf("%s or %s", envName, hint)
+				if slot := lookups[p].Slot; slot != "" {
+					hint = fmt.Sprintf("key %q not found  \u2190 %s --name %s", slot, hint, slot)
+				} else if envName := lookups[p].Env; envName != "" {
+					hint = fmt.Sprintf("not found  \u2190 %s or %s", envName, hint)
+				} else {
+					hint = "not found  \u2190 " + hint
 				}
-				fmt.Printf("  %s\u2717%s  %-16s%snot found  \u2190 %s%s\n", term.Red, term.Reset, p, term.Dim, hint, term.Reset)
+				fmt.Printf("  %s\u2717%s  %-16s%s%s%s\n", term.Red, term.Reset, p, term.Dim, hint, term.Reset)
+			}
+			if info.CmdErr != nil {
+				fmt.Printf("     %s%v%s\n", term.Red, info.CmdErr, term.Reset)
 			}
 		} else {
 			fmt.Printf("  %s\u00b7%s  %-16s%sno auth needed%s\n", term.Dim, term.Reset, p, term.Dim, term.Reset)
@@ -105,3 +142,45 @@ func runDoctor() error {
 
 	return nil
 }
+
+// setupProblems checks what the config refers to on this machine: the TLS
+// files of providers and the key slots selected in [keys].
+func setupProblems(cfg config.Config) []string {
+	var problems []string
+	names := make([]string, 0, len(cfg.Providers))
+	for name := range cfg.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rp, ok := cfg.ResolveProviderFull(name)
+		if !ok {
+			continue
+		}
+		for _, f := range []struct{ key, path string }{{"ca_file", rp.CAFile}, {"client_cert", rp.ClientCert}, {"client_key", rp.ClientKey}} {
+			if f.path == "" {
+				continue
+			}
+			if _, err := os.Stat(f.path); err != nil {
+				problems = append(problems, fmt.Sprintf("provider %q: %s %s not readable", name, f.key, f.path))
+			}
+		}
+	}
+
+	providers := make([]string, 0, len(cfg.Keys))
+	for name := range cfg.Keys {
+		providers = append(providers, name)
+	}
+	sort.Strings(providers)
+	for _, name := range providers {
+		slot := cfg.Keys[name]
+		if keyring.ValidSlotName(slot) != nil {
+			continue // reported by Validate
+		}
+		stored := slices.ContainsFunc(keyring.Slots(name), func(s keyring.SlotInfo) bool { return s.Name == slot })
+		if !stored {
+			problems = append(problems, fmt.Sprintf("keys.%s: no key named %q — run: yeet auth set %s --name %s", name, slot, name, slot))
+		}
+	}
+	return problems
+}
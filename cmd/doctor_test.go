@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/keyring"
+)
+
+func TestSetupProblems(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("YEET_KEYRING_BACKEND", keyring.BackendFile)
+
+	cfg := config.DefaultConfig()
+	cfg.Providers = map[string]config.ProviderConfig{
+		"groq": {CAFile: filepath.Join(t.TempDir(), "ca.pem")},
+	}
+	cfg.Keys = map[string]string{"anthropic": "work"}
+
+	problems := setupProblems(cfg)
+	if len(problems) != 2 || !strings.Contains(problems[0], "ca_file") || !strings.Contains(problems[1], `no key named "work"`) {
+		t.Errorf("setupProblems = %q", problems)
+	}
+}
@@ -53,5 +53,6 @@ func saveCommitRunCapture(c commitRunCapture, usage *ai.Usage, finalMessage, use
 		CostUSD:       costUSD,
 		LatencyMS:     c.LatencyMS,
 		LocalOnly:     localOnly,
+		KeySlot:       usage.KeySlot,
 	})
 }
@@ -93,9 +93,7 @@ func runLog(cmd *cobra.Command, args []string) error {
 		cfg = config.DefaultConfig()
 	}
 
-	for model, p := range cfg.Pricing {
-		ai.SetPricing(model, p.Input, p.Output)
-	}
+	setPricing(cfg)
 
 	provider, err := ai.NewProvider(cfg)
 	if err != nil {
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/rasalas/yeet/internal/ai"
@@ -12,24 +14,71 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	prRemote           string
+	prBase             string
+	prDraft            bool
+	prLabels           []string
+	prReviewers        []string
+	prAssignees        []string
+	prSuggestReviewers int
+	prWatch            bool
+	prAutoMerge        bool
+	prMergeStrategy    string
+)
+
 func init() {
+	prCmd.PersistentFlags().StringVar(&prRemote, "remote", "", "Remote to open PRs against, e.g. upstream (default: pr.remote, else an upstream fork parent, else the push remote)")
+	prCmd.Flags().StringVar(&prBase, "base", "", "Target branch (default: the nearest branch below with an open PR, else the default branch)")
+	prCmd.Flags().BoolVar(&prDraft, "draft", false, "Open the PR as a draft")
+	prCmd.Flags().StringSliceVar(&prLabels, "label", nil, "Add a label (repeatable, adds to pr.labels)")
+	prCmd.Flags().StringSliceVar(&prReviewers, "reviewer", nil, "Request a review from a user or org/team (repeatable, adds to pr.reviewers)")
+	prCmd.Flags().StringSliceVar(&prAssignees, "assignee", nil, "Assign a user (repeatable, adds to pr.assignees)")
+	prCmd.Flags().IntVar(&prSuggestReviewers, "suggest-reviewers", 0, "Suggest N reviewers from the git history of the changed files")
+	prCmd.Flags().Lookup("suggest-reviewers").NoOptDefVal = "2"
+	prCmd.Flags().BoolVar(&prWatch, "watch", false, "Watch the PR's checks until they finish")
+	prCmd.Flags().DurationVar(&checkTimeout, "watch-timeout", checkTimeout, "Stop watching checks that are still pending after this long")
+	prCmd.Flags().BoolVar(&prAutoMerge, "auto-merge", false, "Enable auto-merge, or merge once the checks pass where the forge has none")
+	prCmd.Flags().StringVar(&prMergeStrategy, "merge-strategy", "", "Merge strategy for --auto-merge: merge, squash or rebase (default: pr.merge_strategy, else merge)")
+	prCmd.AddCommand(prUpdateCmd)
 	rootCmd.AddCommand(prCmd)
 }
 
 var prCmd = &cobra.Command{
 	Use:          "pr",
 	Short:        "Create a pull request with an AI-generated description",
-	Long:         "Detect the forge (GitHub/GitLab), collect branch commits, generate a PR title and body via AI, and create the PR/MR.",
+	Long:         "Detect the forge (GitHub/GitLab), collect branch commits, generate a PR title and body via AI (following the repository's PR template if it has one), and create the PR/MR. Stacked branches target the nearest branch below them that has an open PR.",
 	SilenceUsage: true,
 	RunE:         runPR,
 }
 
+var prUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Regenerate the description of the branch's open pull request",
+	Long: "Regenerate the AI-written part of the open PR's description from the full branch diff. " +
+		"Text outside the yeet markers is kept; the change is shown as a diff before it is pushed.",
+	Args: cobra.NoArgs,
+	RunE: runPRUpdate,
+
+	SilenceUsage: true,
+}
+
 func runPR(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	strategy, err := mergeStrategy(cfg)
+	if err != nil {
+		return err
+	}
+
 	// 1. Detect forge
-	f, err := forge.Detect()
+	t, err := detectPRTarget(cfg)
 	if err != nil {
 		return err
 	}
+	f := t.Forge
 
 	// 2. Check we're not on the default branch
 	branch, err := git.CurrentBranch()
@@ -37,35 +86,52 @@ func runPR(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get current branch: %w", err)
 	}
 
-	base, err := git.DefaultBranch()
-	if err != nil {
-		return fmt.Errorf("failed to detect default branch: %w", err)
-	}
-
+	base := t.Trunk
 	if branch == base {
 		return fmt.Errorf("already on %s — switch to a feature branch first", base)
 	}
 
 	// 3. Push if no upstream
 	if !git.HasUpstream() {
-		fmt.Printf("\n  %sPushing %s to origin...%s\n", term.Dim, branch, term.Reset)
-		out, err := git.PushSetUpstream()
+		fmt.Printf("\n  %sPushing %s to %s...%s\n", term.Dim, branch, t.Push, term.Reset)
+		out, err := git.PushSetUpstream(t.Push)
 		if err != nil {
 			return fmt.Errorf("push failed: %s", out)
 		}
-		fmt.Printf("  %s✓%s pushed to origin/%s\n", term.Green, term.Reset, branch)
+		fmt.Printf("  %s✓%s pushed to %s/%s\n", term.Green, term.Reset, t.Push, branch)
 	}
 
 	// 4. Check for existing PR/MR
-	if url, exists := f.ExistingPR(branch); exists {
-		if url != "" {
-			fmt.Printf("\n  A %s PR already exists: %s\n\n", f.Name(), url)
+	existing, err := f.ExistingPR(branch)
+	if err != nil {
+		return fmt.Errorf("failed to look up existing %s PR: %w", f.Name(), err)
+	}
+	if existing != nil {
+		if existing.URL != "" {
+			fmt.Printf("\n  A %s PR already exists: %s\n", f.Name(), existing.URL)
 		} else {
-			fmt.Printf("\n  A %s PR already exists for branch %s.\n\n", f.Name(), branch)
+			fmt.Printf("\n  A %s PR already exists for branch %s.\n", f.Name(), branch)
+		}
+		if prWatch || prAutoMerge {
+			return followPR(f, existing, prWatch, prAutoMerge, strategy)
 		}
+		fmt.Printf("  %sRun %syeet pr update%s to refresh its description.%s\n\n",
+			term.Dim, term.Reset+term.Bold, term.Reset+term.Dim, term.Reset)
 		return nil
 	}
 
+	// Stacked branches target the nearest branch below them that has a PR.
+	if prBase != "" {
+		base = prBase
+	} else if parent, parentPR := stackParent(f, branch, base); parent != "" {
+		base = parent
+		label := parent
+		if parentPR.Number > 0 {
+			label = fmt.Sprintf("%s (#%d)", parent, parentPR.Number)
+		}
+		fmt.Printf("\n  %sStacked on %s%s\n", term.Dim, label, term.Reset)
+	}
+
 	// 5. Check for uncommitted changes
 	status, _ := git.StatusShort()
 	if status != "" {
@@ -75,18 +141,19 @@ func runPR(cmd *cobra.Command, args []string) error {
 	}
 
 	// 6. Collect commits + diff
-	commits, err := git.LogRange(base)
+	baseRef := t.baseRef(base)
+	commits, err := git.LogRange(baseRef)
 	if err != nil || commits == "" {
-		fmt.Printf("\n  %sNo commits between %s and %s — nothing to open a PR for.%s\n\n", term.Dim, base, branch, term.Reset)
+		fmt.Printf("\n  %sNo commits between %s and %s — nothing to open a PR for.%s\n\n", term.Dim, baseRef, branch, term.Reset)
 		return nil
 	}
 
-	diff, err := git.DiffRange(base)
+	diff, err := git.DiffRange(baseRef)
 	if err != nil {
 		return fmt.Errorf("failed to get diff: %w", err)
 	}
 
-	stat, err := git.DiffStatRange(base)
+	stat, err := git.DiffStatRange(baseRef)
 	if err != nil {
 		return fmt.Errorf("failed to get diff stat: %w", err)
 	}
@@ -98,65 +165,43 @@ func runPR(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
-	// 6. AI generation
-	cfg, err := config.Load()
-	if err != nil {
-		cfg = config.DefaultConfig()
+	// Labels, reviewers and assignees: config defaults plus flags.
+	meta := forge.NewPR{
+		Draft:     cfg.PR.Draft || prDraft,
+		Labels:    appendUnique(cfg.PR.Labels, prLabels...),
+		Reviewers: appendUnique(cfg.PR.Reviewers, prReviewers...),
+		Assignees: appendUnique(cfg.PR.Assignees, prAssignees...),
 	}
-
-	for model, p := range cfg.Pricing {
-		ai.SetPricing(model, p.Input, p.Output)
+	suggest := cfg.PR.SuggestReviewers
+	if cmd.Flags().Changed("suggest-reviewers") {
+		suggest = prSuggestReviewers
 	}
-
-	provider, err := ai.NewProvider(cfg)
-	if err != nil {
-		return fmt.Errorf("no AI provider configured: %w", err)
+	if suggest > 0 {
+		meta.Reviewers = appendUnique(meta.Reviewers, suggestReviewers(f, baseRef, suggest, meta.Reviewers)...)
 	}
-
-	ctx := ai.CommitContext{
-		Diff:          diff,
-		Branch:        branch,
-		RecentCommits: commits,
-		SystemPrompt:  ai.PRPrompt,
-		MaxTokens:     1024,
+	if line := prMetadataLine(meta); line != "" {
+		fmt.Printf("  %s%s%s\n\n", term.Dim, line, term.Reset)
 	}
 
-	var title, body string
-	var usage *ai.Usage
-	streamed := false
-	streamedPreviewLines := 0
-
-	if sp, ok := provider.(ai.StreamingProvider); ok {
-		msg, u, previewLines, genErr := generateStreamingPR(sp, ctx)
-		if genErr != nil {
-			return fmt.Errorf("AI generation failed: %w", genErr)
-		}
-		usage = &u
-		title, body = parsePR(msg)
-		streamed = true
-		streamedPreviewLines = previewLines
-	} else {
-		var s term.Spinner
-		s.Start("Generating PR description...")
-
-		msg, u, genErr := provider.GenerateCommitMessage(ctx)
-		s.Stop()
+	// 6. AI generation
+	provider, ctx, err := prGenerationContext(cfg, f, branch, commits, diff)
+	if err != nil {
+		return err
+	}
 
-		if genErr != nil {
-			return fmt.Errorf("AI generation failed: %w", genErr)
-		}
-		usage = &u
-		title, body = parsePR(msg)
+	title, body, usage, streamedPreviewLines, err := generatePRText(provider, ctx)
+	if err != nil {
+		return err
 	}
 
 	// 7. Preview — skip with -y
 	if yesFlag {
-		if streamed && streamedPreviewLines > 0 {
+		if streamedPreviewLines > 0 {
 			term.ClearRenderedBlock(streamedPreviewLines)
 		}
 		displayPRPreview(title, body)
 	} else {
-		if streamed && streamedPreviewLines > 0 {
+		if streamedPreviewLines > 0 {
 			term.ClearRenderedBlock(streamedPreviewLines)
 		}
 		linesToClear := 3
@@ -202,14 +247,274 @@ func runPR(cmd *cobra.Command, args []string) error {
 	}
 
 	// 8. Create PR
-	url, err := f.CreatePR(title, body, base)
-	if err != nil {
+	meta.Title, meta.Body, meta.Head, meta.Base = title, forge.MarkBody(body), branch, base
+	pr, err := f.CreatePR(meta)
+	var metaErr *forge.MetadataError
+	if err != nil && (pr == nil || !errors.As(err, &metaErr)) {
 		return fmt.Errorf("failed to create %s PR: %w", f.Name(), err)
 	}
 
-	fmt.Printf("  %s✓%s %s PR created: %s\n", term.Green, term.Reset, f.Name(), url)
+	fmt.Printf("  %s✓%s %s PR created: %s\n", term.Green, term.Reset, f.Name(), pr.URL)
+	if metaErr != nil {
+		for _, e := range metaErr.Errs {
+			fmt.Printf("  %s!%s %v\n", term.Yellow, term.Reset, e)
+		}
+	}
 
 	// 9. Usage/cost
+	printPRUsage(usage)
+
+	// 10. Auto-merge and CI
+	if prWatch || prAutoMerge {
+		return followPR(f, pr, prWatch, prAutoMerge, strategy)
+	}
+	return nil
+}
+
+func runPRUpdate(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	t, err := detectPRTarget(cfg)
+	if err != nil {
+		return err
+	}
+	f := t.Forge
+	updater, ok := f.(forge.Updater)
+	if !ok {
+		return fmt.Errorf("editing %s PRs is not supported", f.Name())
+	}
+
+	branch, err := git.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+	existing, err := f.ExistingPR(branch)
+	if err != nil {
+		return fmt.Errorf("failed to look up existing %s PR: %w", f.Name(), err)
+	}
+	if existing == nil || existing.Number == 0 {
+		return fmt.Errorf("no open %s PR for %s — run: yeet pr", f.Name(), branch)
+	}
+
+	base := existing.Base
+	if base == "" {
+		base = t.Trunk
+	}
+
+	baseRef := t.baseRef(base)
+	commits, err := git.LogRange(baseRef)
+	if err != nil || commits == "" {
+		fmt.Printf("\n  %sNo commits between %s and %s — nothing to describe.%s\n\n", term.Dim, baseRef, branch, term.Reset)
+		return nil
+	}
+	diff, err := git.DiffRange(baseRef)
+	if err != nil {
+		return fmt.Errorf("failed to get diff: %w", err)
+	}
+	stat, err := git.DiffStatRange(baseRef)
+	if err != nil {
+		return fmt.Errorf("failed to get diff stat: %w", err)
+	}
+
+	fmt.Printf("\n  %sUpdating%s %s\n\n", term.Bold, term.Reset, existing.URL)
+	for _, line := range strings.Split(stat, "\n") {
+		fmt.Println("  " + term.ColorizeDiffStat(line))
+	}
+	fmt.Println()
+
+	provider, ctx, err := prGenerationContext(cfg, f, branch, commits, diff)
+	if err != nil {
+		return err
+	}
+	_, generated, usage, previewLines, err := generatePRText(provider, ctx)
+	if err != nil {
+		return err
+	}
+	if previewLines > 0 {
+		term.ClearRenderedBlock(previewLines)
+	}
+	if strings.TrimSpace(generated) == "" {
+		return fmt.Errorf("AI returned no PR description")
+	}
+
+	current := strings.ReplaceAll(existing.Body, "\r\n", "\n")
+	body, marked := forge.SpliceBody(current, generated)
+	if !marked && strings.TrimSpace(current) != "" {
+		fmt.Printf("  %s!%s The description has no yeet markers — all of it will be replaced.\n\n", term.Yellow, term.Reset)
+	}
+
+	if body != current {
+		printDiff(current, body)
+		fmt.Println()
+	}
+	for !yesFlag && body != current {
+		term.PrintHintActions([]term.HintAction{
+			{Key: "enter", Desc: "update"},
+			{Key: "E", Desc: "editor"},
+			{Key: "q", Desc: "cancel"},
+		}, term.TerminalWidth())
+
+		action, err := term.WaitForAction()
+		if err != nil {
+			return err
+		}
+		switch action {
+		case term.ActionCancel:
+			fmt.Printf("\n  %sCancelled.%s\n", term.Dim, term.Reset)
+			return nil
+		case term.ActionEdit, term.ActionEditExternal:
+			edited, err := term.EditExternal(body)
+			if err != nil {
+				fmt.Printf("\n  Editor failed: %v\n", err)
+			} else {
+				body = strings.TrimSpace(edited)
+			}
+			fmt.Println()
+			printDiff(current, body)
+			fmt.Println()
+			continue
+		}
+		fmt.Println()
+		break
+	}
+
+	if body == current {
+		fmt.Printf("  %s✓%s %s PR description is already up to date\n", term.Green, term.Reset, f.Name())
+	} else {
+		if err := updater.UpdatePR(existing.Number, body); err != nil {
+			return fmt.Errorf("failed to update %s PR: %w", f.Name(), err)
+		}
+		fmt.Printf("  %s✓%s %s PR description updated: %s\n", term.Green, term.Reset, f.Name(), existing.URL)
+	}
+	printPRUsage(usage)
+	return nil
+}
+
+// prTarget is where PR commands open and look up PRs.
+type prTarget struct {
+	Forge  forge.Forge
+	Remote string // remote PRs are opened against
+	Push   string // remote branches are pushed to
+	Trunk  string // default branch of Remote
+}
+
+// detectPRTarget resolves the remotes and detects the forge and default
+// branch of the one PRs are opened against.
+func detectPRTarget(cfg config.Config) (prTarget, error) {
+	target, push := prRemotes(cfg)
+	f, err := detectForge(cfg, target, push)
+	if err != nil {
+		return prTarget{}, err
+	}
+	trunk, err := git.RemoteDefaultBranch(target)
+	if err != nil {
+		return prTarget{}, fmt.Errorf("failed to detect default branch of %s: %w", target, err)
+	}
+	if target != push {
+		fmt.Printf("\n  %sOpening PRs against %s (%s/%s), pushing to %s%s\n", term.Dim, target, target, trunk, push, term.Reset)
+	}
+	return prTarget{Forge: f, Remote: target, Push: push, Trunk: trunk}, nil
+}
+
+// detectForge detects the forge of the target remote like forge.Detect and
+// suggests a [forges] entry when its type could only be guessed.
+func detectForge(cfg config.Config, target, push string) (forge.Forge, error) {
+	f, err := forge.Detect(cfg, target, push)
+	if remote, rerr := forge.RemoteNamed(target); rerr == nil && forge.Guessed(remote.Host) {
+		fmt.Printf("  %s!%s Couldn't tell which forge %s runs — assuming GitHub. Set it in the config to skip the probe:\n", term.Yellow, term.Reset, remote.Host)
+		fmt.Printf("    %s[forges.\"%s\"]\n    type = \"gitlab\"  # or github, gitea, forgejo, bitbucket%s\n", term.Dim, remote.Host, term.Reset)
+	}
+	return f, err
+}
+
+// prRemotes returns the remote PRs are opened against and the one branches
+// are pushed to. Without --remote or pr.remote, a remote named "upstream"
+// pointing at another repository than the push remote marks a fork.
+func prRemotes(cfg config.Config) (target, push string) {
+	push = cfg.PushRemoteName()
+	switch {
+	case prRemote != "":
+		return prRemote, push
+	case cfg.PR.Remote != "":
+		return cfg.PR.Remote, push
+	}
+	if up, err := forge.RemoteNamed("upstream"); err == nil && push != "upstream" {
+		if own, err := forge.RemoteNamed(push); err != nil || own != up {
+			return "upstream", push
+		}
+	}
+	return push, push
+}
+
+// baseRef returns the git ref to compare a PR base branch against. When PRs
+// go to another remote than branches are pushed to, the local default
+// branch usually tracks the fork, so the target's remote-tracking branch is
+// fetched and used instead.
+func (t prTarget) baseRef(base string) string {
+	if t.Remote == t.Push || base != t.Trunk {
+		return base
+	}
+	if out, err := git.Fetch(t.Remote, base); err != nil {
+		fmt.Printf("  %s!%s could not fetch %s/%s: %s\n", term.Yellow, term.Reset, t.Remote, base, out)
+	}
+	return t.Remote + "/" + base
+}
+
+// prGenerationContext builds the provider and request for a PR description,
+// filling in the repository's PR template when the forge has one.
+func prGenerationContext(cfg config.Config, f forge.Forge, branch, commits, diff string) (ai.Provider, ai.CommitContext, error) {
+	setPricing(cfg)
+
+	provider, err := ai.NewProvider(cfg)
+	if err != nil {
+		return nil, ai.CommitContext{}, fmt.Errorf("no AI provider configured: %w", err)
+	}
+
+	var template string
+	if root, err := git.RepoRoot(); err == nil {
+		var path string
+		if path, template = forge.FindTemplate(f, root); path != "" {
+			fmt.Printf("  %sFilling in %s%s\n\n", term.Dim, path, term.Reset)
+		}
+	}
+
+	return provider, ai.CommitContext{
+		Diff:          diff,
+		Branch:        branch,
+		RecentCommits: commits,
+		SystemPrompt:  ai.PRPromptFor(template),
+		MaxTokens:     1024,
+	}, nil
+}
+
+// generatePRText asks the provider for a PR title and body, streaming a
+// preview card when it can. previewLines is the card's height, or 0 when
+// nothing was streamed.
+func generatePRText(provider ai.Provider, ctx ai.CommitContext) (title, body string, usage *ai.Usage, previewLines int, err error) {
+	if sp, ok := provider.(ai.StreamingProvider); ok {
+		msg, u, lines, genErr := generateStreamingPR(sp, ctx)
+		if genErr != nil {
+			return "", "", nil, 0, fmt.Errorf("AI generation failed: %w", genErr)
+		}
+		title, body = parsePR(msg)
+		return title, body, &u, lines, nil
+	}
+
+	var s term.Spinner
+	s.Start("Generating PR description...")
+	msg, u, genErr := provider.GenerateCommitMessage(ctx)
+	s.Stop()
+	if genErr != nil {
+		return "", "", nil, 0, fmt.Errorf("AI generation failed: %w", genErr)
+	}
+	title, body = parsePR(msg)
+	return title, body, &u, 0, nil
+}
+
+func printPRUsage(usage *ai.Usage) {
 	if usage != nil && usage.InputTokens > 0 {
 		costLine := fmt.Sprintf("%s · %s", usage.FormatTokens(), usage.Model)
 		if cost, ok := usage.Cost(); ok {
@@ -217,8 +522,77 @@ func runPR(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Printf("\n  %s%s%s\n\n", term.Dim, costLine, term.Reset)
 	}
+}
 
-	return nil
+// suggestReviewers proposes up to n reviewers among the most frequent
+// authors of the changed files, skipping names already requested. Authors
+// the forge cannot map to an account are printed as hints instead.
+func suggestReviewers(f forge.Forge, base string, n int, skip []string) []string {
+	files, err := git.ChangedFilesRange(base)
+	if err != nil || len(files) == 0 {
+		return nil
+	}
+	authors, err := git.FileAuthors(base, files)
+	if err != nil || len(authors) == 0 {
+		return nil
+	}
+
+	// Each lookup may be an API call; don't walk the whole history.
+	authors = authors[:min(len(authors), 3*n)]
+
+	resolver, ok := f.(forge.UserResolver)
+	var picked []string
+	for _, a := range authors {
+		if len(picked) == n {
+			break
+		}
+		var login string
+		if ok {
+			login, _ = resolver.UserForEmail(a.Email)
+		}
+		if login == "" {
+			fmt.Printf("  %sPossible reviewer: %s <%s> (%d commits) — no %s account found%s\n",
+				term.Dim, a.Name, a.Email, a.Commits, f.Name(), term.Reset)
+			continue
+		}
+		if slices.Contains(skip, login) || slices.Contains(picked, login) {
+			continue
+		}
+		picked = append(picked, login)
+	}
+	if len(picked) > 0 {
+		fmt.Printf("  %s✓%s suggested reviewers: %s\n", term.Green, term.Reset, strings.Join(picked, ", "))
+	}
+	return picked
+}
+
+// prMetadataLine summarizes draft state, labels, reviewers and assignees,
+// or returns "" when there are none.
+func prMetadataLine(pr forge.NewPR) string {
+	var parts []string
+	if pr.Draft {
+		parts = append(parts, "draft")
+	}
+	for _, field := range []struct {
+		name   string
+		values []string
+	}{{"labels", pr.Labels}, {"reviewers", pr.Reviewers}, {"assignees", pr.Assignees}} {
+		if len(field.values) > 0 {
+			parts = append(parts, field.name+": "+strings.Join(field.values, ", "))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// appendUnique appends the values not yet in list, without modifying list.
+func appendUnique(list []string, values ...string) []string {
+	out := slices.Clone(list)
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" && !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
 }
 
 // parsePR splits the AI output into title (first line) and body (rest).
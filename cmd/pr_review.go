@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/forge"
+	"github.com/rasalas/yeet/internal/git"
+	"github.com/rasalas/yeet/internal/review"
+	"github.com/rasalas/yeet/internal/term"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	prCmd.AddCommand(prReviewCmd)
+}
+
+var prReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Add AI review comments to the branch's open pull request",
+	Long: "Review the open PR's diff with AI, let you accept, edit or skip each comment, and add the " +
+		"accepted ones to the PR as a pending review that you submit on the forge.",
+	Args: cobra.NoArgs,
+	RunE: runPRReview,
+
+	SilenceUsage: true,
+}
+
+func runPRReview(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	t, err := detectPRTarget(cfg)
+	if err != nil {
+		return err
+	}
+	f := t.Forge
+	reviewer, ok := f.(forge.Reviewer)
+	if !ok {
+		return fmt.Errorf("reviewing %s PRs is not supported", f.Name())
+	}
+
+	branch, err := git.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+	existing, err := f.ExistingPR(branch)
+	if err != nil {
+		return fmt.Errorf("failed to look up existing %s PR: %w", f.Name(), err)
+	}
+	if existing == nil || existing.Number == 0 {
+		return fmt.Errorf("no open %s PR for %s — run: yeet pr", f.Name(), branch)
+	}
+
+	if err := checkPRHead(t, branch); err != nil {
+		return err
+	}
+
+	base := existing.Base
+	if base == "" {
+		base = t.Trunk
+	}
+	baseRef := t.baseRef(base)
+	diff, err := git.DiffRange(baseRef)
+	if err != nil {
+		return fmt.Errorf("failed to get diff: %w", err)
+	}
+	if strings.TrimSpace(diff) == "" {
+		fmt.Printf("\n  %sNo changes between %s and %s — nothing to review.%s\n\n", term.Dim, baseRef, branch, term.Reset)
+		return nil
+	}
+	stat, _ := git.DiffStatRange(baseRef)
+
+	fmt.Printf("\n  %sReviewing%s %s\n\n", term.Bold, term.Reset, existing.URL)
+	for _, line := range strings.Split(stat, "\n") {
+		fmt.Println("  " + term.ColorizeDiffStat(line))
+	}
+	fmt.Println()
+
+	findings, usage, err := reviewDiff(cfg, diff, branch, true)
+	if err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		fmt.Printf("  %s✓%s No issues found\n", term.Green, term.Reset)
+		printPRUsage(usage)
+		return nil
+	}
+
+	comments, outside, err := pickReviewComments(findings, review.ParseDiff(diff))
+	if err != nil {
+		return err
+	}
+	body := reviewSummary(outside)
+	if len(comments) == 0 && body == "" {
+		fmt.Printf("\n  %sNo comments accepted — nothing to add.%s\n", term.Dim, term.Reset)
+		printPRUsage(usage)
+		return nil
+	}
+
+	if !yesFlag {
+		fmt.Printf("\n  Add %d comment(s) to #%d as a pending review? %s[y/n]%s ", len(comments)+len(outside), existing.Number, term.Dim, term.Reset)
+		ok, err := term.WaitForYesNo()
+		fmt.Println()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Printf("  %sCancelled.%s\n", term.Dim, term.Reset)
+			return nil
+		}
+	}
+
+	url, err := reviewer.SubmitReview(existing.Number, body, comments)
+	if err != nil {
+		return fmt.Errorf("failed to add %s review: %w", f.Name(), err)
+	}
+	if url == "" {
+		url = existing.URL
+	}
+	fmt.Printf("\n  %s✓%s Pending review added — submit it on %s: %s\n", term.Green, term.Reset, f.Name(), url)
+	printPRUsage(usage)
+	return nil
+}
+
+// pickReviewComments walks the findings and lets the user accept, edit or
+// skip each (all are accepted with -y). Accepted findings on lines of the
+// diff become line comments; the others are returned for the summary.
+func pickReviewComments(findings []review.Finding, lines review.DiffLines) ([]forge.ReviewComment, []review.Finding, error) {
+	var comments []forge.ReviewComment
+	var outside []review.Finding
+	for i, f := range findings {
+		anchored := f.Line > 0 && lines.Contains(f.File, f.Line)
+		accepted := yesFlag
+		for !yesFlag {
+			card := fmt.Sprintf("%s · %s", f.Severity, f.Message)
+			if !anchored {
+				card += "\n\n(not on a changed line — goes into the review summary)"
+			}
+			fmt.Printf("  %sComment %d/%d%s\n", term.Dim, i+1, len(findings), term.Reset)
+			cardLines := term.DisplayCard(findingLocation(f), card)
+			hintLines := term.PrintHintActions([]term.HintAction{
+				{Key: "enter", Desc: "accept"},
+				{Key: "n", Desc: "skip"},
+				{Key: "e", Desc: "edit"},
+				{Key: "E", Desc: "editor"},
+				{Key: "q", Desc: "skip the rest"},
+			}, term.TerminalWidth())
+
+			key, err := term.WaitForKey("ynqeE")
+			if err != nil {
+				return nil, nil, err
+			}
+			term.ClearRenderedBlock(1, cardLines, hintLines)
+			switch key {
+			case 'e':
+				edited, err := term.EditLine(f.Message)
+				if err != nil {
+					return nil, nil, err
+				}
+				f.Message = editedComment(f.Message, edited)
+				continue
+			case 'E':
+				edited, err := term.EditExternal(f.Message)
+				if err != nil {
+					fmt.Printf("\n  Editor failed: %v\n", err)
+				} else {
+					f.Message = editedComment(f.Message, edited)
+				}
+				continue
+			case 'q':
+				return comments, outside, nil
+			}
+			accepted = key == '\r' || key == 'y'
+			break
+		}
+
+		mark := fmt.Sprintf("%s–%s", term.Dim, term.Reset)
+		if accepted {
+			mark = fmt.Sprintf("%s✓%s", term.Green, term.Reset)
+			if anchored {
+				comments = append(comments, forge.ReviewComment{
+					Path:    f.File,
+					Line:    f.Line,
+					OldLine: lines.OldLine(f.File, f.Line),
+					Body:    reviewCommentBody(f),
+				})
+			} else {
+				outside = append(outside, f)
+			}
+		}
+		fmt.Printf("  %s %s %s%s%s\n", mark, findingLocation(f), term.Dim, firstLine(f.Message), term.Reset)
+	}
+	return comments, outside, nil
+}
+
+// editedComment returns the edited text of a review comment. An empty
+// edit keeps the previous text, as an empty comment can't be posted; n
+// skips the comment instead.
+func editedComment(previous, edited string) string {
+	if edited = strings.TrimSpace(edited); edited == "" {
+		fmt.Printf("  %sEmpty comment — kept the previous text (n skips it)%s\n", term.Dim, term.Reset)
+		return previous
+	}
+	return edited
+}
+
+// reviewCommentBody formats a finding as a comment, severity first.
+func reviewCommentBody(f review.Finding) string {
+	return fmt.Sprintf("**%s%s:** %s", strings.ToUpper(f.Severity[:1]), f.Severity[1:], f.Message)
+}
+
+// reviewSummary lists the findings that can't be anchored to a diff line.
+func reviewSummary(outside []review.Finding) string {
+	if len(outside) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Comments outside the changed lines:\n")
+	for _, f := range outside {
+		fmt.Fprintf(&b, "\n- `%s` — %s", findingLocation(f), reviewCommentBody(f))
+	}
+	return b.String()
+}
+
+// findingLocation returns "file:line", or the file for file-level findings.
+func findingLocation(f review.Finding) string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// checkPRHead makes sure the local branch is what the PR shows. Comments are
+// anchored to lines of the local diff, and forges reject (or misplace) them
+// when the PR's head differs.
+func checkPRHead(t prTarget, branch string) error {
+	remoteRef := t.Push + "/" + branch
+	if out, err := git.Fetch(t.Push, branch); err != nil {
+		return fmt.Errorf("failed to fetch %s: %s", remoteRef, out)
+	}
+	pushed, err := git.ResolveCommit(remoteRef)
+	if err != nil {
+		return fmt.Errorf("%s is not pushed to %s — push it first", branch, t.Push)
+	}
+	head, err := git.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	if head == pushed {
+		return nil
+	}
+	if git.IsAncestor(pushed, head) {
+		return fmt.Errorf("%s has commits that are not pushed to %s — push them first, so the comments land on the PR's lines", branch, remoteRef)
+	}
+	return fmt.Errorf("%s differs from %s — pull or push first, so the comments land on the PR's lines", branch, remoteRef)
+}
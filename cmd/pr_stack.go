@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/forge"
+	"github.com/rasalas/yeet/internal/git"
+	"github.com/rasalas/yeet/internal/term"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	prCmd.AddCommand(prStackCmd)
+}
+
+var prStackCmd = &cobra.Command{
+	Use:   "stack",
+	Short: "Show the chain of PRs the current branch is stacked on",
+	Long: "List the PRs from the default branch up to the current branch. PRs whose base branch " +
+		"was merged are moved down to the nearest open PR below them (or the default branch).",
+	Args: cobra.NoArgs,
+	RunE: runPRStack,
+
+	SilenceUsage: true,
+}
+
+// stackEntry is one branch of a PR stack.
+type stackEntry struct {
+	Branch   string
+	PR       *forge.PR // nil when the branch has no open PR
+	Base     string    // the PR's base branch, or the detected parent
+	Retarget string    // new base when Base was merged
+}
+
+func runPRStack(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	t, err := detectPRTarget(cfg)
+	if err != nil {
+		return err
+	}
+	f, trunk := t.Forge, t.Trunk
+
+	branch, err := git.CurrentBranch()
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if branch == trunk {
+		return fmt.Errorf("on %s — switch to a branch of the stack first", trunk)
+	}
+
+	stack, err := prStack(f, branch, trunk, t.Remote)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n  %s\n", trunk)
+	for i, e := range stack {
+		line := fmt.Sprintf("  %s└ %s", strings.Repeat("  ", i), e.Branch)
+		if e.PR != nil {
+			line += fmt.Sprintf("  %s#%d%s", term.Bold, e.PR.Number, term.Reset)
+			if e.PR.Draft {
+				line += fmt.Sprintf(" %sdraft%s", term.Dim, term.Reset)
+			}
+			if e.PR.URL != "" {
+				line += fmt.Sprintf("  %s%s%s", term.Dim, e.PR.URL, term.Reset)
+			}
+		} else {
+			line += fmt.Sprintf("  %sno open PR%s", term.Dim, term.Reset)
+		}
+		if e.Branch == branch {
+			line += fmt.Sprintf("  %s◂ current%s", term.Green, term.Reset)
+		}
+		fmt.Println(line)
+		if e.Retarget != "" {
+			fmt.Printf("  %s  %s%s was merged — retarget to %s%s\n", strings.Repeat("  ", i), term.Yellow, e.Base, e.Retarget, term.Reset)
+		}
+	}
+	fmt.Println()
+
+	var moves []stackEntry
+	for _, e := range stack {
+		if e.Retarget != "" {
+			moves = append(moves, e)
+		}
+	}
+	if len(moves) == 0 {
+		return nil
+	}
+	r, ok := f.(forge.Retargeter)
+	if !ok {
+		return fmt.Errorf("retargeting %s PRs is not supported", f.Name())
+	}
+
+	if !yesFlag {
+		fmt.Printf("  Retarget %d PR(s)? %s[y/n]%s ", len(moves), term.Dim, term.Reset)
+		ok, err := term.WaitForYesNo()
+		fmt.Println()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Printf("  %sCancelled.%s\n", term.Dim, term.Reset)
+			return nil
+		}
+	}
+	for _, e := range moves {
+		if err := r.RetargetPR(e.PR.Number, e.Retarget); err != nil {
+			return fmt.Errorf("failed to retarget #%d: %w", e.PR.Number, err)
+		}
+		fmt.Printf("  %s✓%s #%d now targets %s\n", term.Green, term.Reset, e.PR.Number, e.Retarget)
+	}
+	e := moves[0]
+	fmt.Printf("\n  %sTo drop the merged commits from the branches, rebase them, e.g.:%s\n", term.Dim, term.Reset)
+	fmt.Printf("  %sgit rebase --onto %s %s %s%s\n\n", term.Dim, e.Retarget, e.Base, e.Branch, term.Reset)
+	return nil
+}
+
+// prStack walks from branch down to trunk and returns the stack bottom
+// first. PRs whose base was merged get a Retarget: the nearest branch below
+// with an open PR, or trunk. remote is the remote trunk belongs to.
+func prStack(f forge.Forge, branch, trunk, remote string) ([]stackEntry, error) {
+	var stack []stackEntry
+	seen := map[string]bool{}
+	for b := branch; b != trunk && !seen[b]; {
+		seen[b] = true
+		pr, err := f.ExistingPR(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s PR for %s: %w", f.Name(), b, err)
+		}
+		e := stackEntry{Branch: b, PR: pr, Base: trunk}
+		if pr != nil && pr.Base != "" {
+			e.Base = pr.Base
+		} else if parent, _ := stackParent(f, b, trunk); parent != "" {
+			e.Base = parent
+		}
+		stack = append(stack, e)
+		b = e.Base
+	}
+	slices.Reverse(stack)
+
+	local, _ := git.LocalBranches()
+	below := trunk
+	for i := range stack {
+		e := &stack[i]
+		if e.PR == nil {
+			continue
+		}
+		if e.Base != below && !hasOpenPR(stack, e.Base) && branchMerged(e.Base, trunk, remote, local) {
+			e.Retarget = below
+		}
+		below = e.Branch
+	}
+	return stack, nil
+}
+
+// stackParent returns the nearest local branch below branch that has an
+// open PR, or "" when branch sits directly on trunk. Branches that are
+// already part of trunk don't count.
+func stackParent(f forge.Forge, branch, trunk string) (string, *forge.PR) {
+	branches, err := git.LocalBranches()
+	if err != nil {
+		return "", nil
+	}
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, b := range branches {
+		if b == branch || b == trunk || !git.IsAncestor(b, branch) || git.IsAncestor(b, trunk) {
+			continue
+		}
+		n, err := git.CommitCount(b, branch)
+		if err != nil || n == 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{b, n})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+	for _, c := range candidates {
+		if pr, err := f.ExistingPR(c.name); err == nil && pr != nil {
+			return c.name, pr
+		}
+	}
+	return "", nil
+}
+
+func hasOpenPR(stack []stackEntry, branch string) bool {
+	for _, e := range stack {
+		if e.Branch == branch {
+			return e.PR != nil
+		}
+	}
+	return false
+}
+
+// branchMerged reports whether a base branch is done with: merged into
+// trunk, or deleted locally (as after a squash merge).
+func branchMerged(branch, trunk, remote string, local []string) bool {
+	if !slices.Contains(local, branch) {
+		return true
+	}
+	return git.IsAncestor(branch, trunk) || git.IsAncestor(branch, remote+"/"+trunk)
+}
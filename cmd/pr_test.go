@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/rasalas/yeet/internal/forge"
+	"github.com/rasalas/yeet/internal/git"
+	"github.com/rasalas/yeet/internal/review"
 )
 
 func TestParsePR(t *testing.T) {
@@ -86,3 +92,219 @@ func TestParsePRRoundTrip(t *testing.T) {
 		t.Errorf("round-trip failed:\n  got:  %q\n  want: %q", reconstructed, original)
 	}
 }
+
+// stackMockGit models branches as a chain of parents; each branch is one
+// commit ahead of its parent.
+type stackMockGit struct {
+	runYeetMockGit
+	parents map[string]string
+	local   []string
+}
+
+func (m *stackMockGit) LocalBranches() ([]string, error) { return m.local, nil }
+func (m *stackMockGit) IsAncestor(ancestor, ref string) bool {
+	_, err := m.CommitCount(ancestor, ref)
+	return err == nil
+}
+func (m *stackMockGit) CommitCount(base, ref string) (int, error) {
+	for n := 0; ref != ""; n++ {
+		if ref == base {
+			return n, nil
+		}
+		ref = m.parents[ref]
+	}
+	return 0, fmt.Errorf("%s is not an ancestor", base)
+}
+
+type stackForge struct {
+	prs map[string]*forge.PR
+}
+
+func (stackForge) Name() string                             { return "Test" }
+func (f stackForge) ExistingPR(b string) (*forge.PR, error) { return f.prs[b], nil }
+func (stackForge) CreatePR(forge.NewPR) (*forge.PR, error)  { return nil, nil }
+
+func TestStackParent(t *testing.T) {
+	orig := git.Default
+	defer func() { git.Default = orig }()
+	git.Default = &stackMockGit{
+		parents: map[string]string{"a": "main", "b": "a", "c": "b", "old": "main"},
+		local:   []string{"main", "a", "b", "c", "old"},
+	}
+	f := stackForge{prs: map[string]*forge.PR{
+		"a":   {Number: 1, Base: "main"},
+		"old": {Number: 9, Base: "main"},
+	}}
+
+	// b has no PR, so c stacks on a.
+	if parent, pr := stackParent(f, "c", "main"); parent != "a" || pr.Number != 1 {
+		t.Errorf("stackParent(c) = %q, %+v; want a", parent, pr)
+	}
+	if parent, _ := stackParent(f, "a", "main"); parent != "" {
+		t.Errorf("stackParent(a) = %q; want trunk", parent)
+	}
+}
+
+func TestPRStackRetargetsAfterMerge(t *testing.T) {
+	orig := git.Default
+	defer func() { git.Default = orig }()
+	// a was merged and deleted; b's PR still targets it.
+	git.Default = &stackMockGit{
+		parents: map[string]string{"b": "main", "c": "b"},
+		local:   []string{"main", "b", "c"},
+	}
+	f := stackForge{prs: map[string]*forge.PR{
+		"b": {Number: 2, Base: "a"},
+		"c": {Number: 3, Base: "b"},
+	}}
+
+	stack, err := prStack(f, "c", "main", "origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range stack {
+		got = append(got, e.Branch+">"+e.Base+">"+e.Retarget)
+	}
+	want := []string{"a>main>", "b>a>main", "c>b>"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("prStack = %v, want %v", got, want)
+	}
+}
+
+// ciForge reports a sequence of check results and records merges; it has
+// no auto-merge, so followPR must merge once the checks pass.
+type ciForge struct {
+	stackForge
+	polls  [][]forge.Check
+	merged string
+}
+
+func (f *ciForge) Checks(int) ([]forge.Check, error) {
+	checks := f.polls[0]
+	if len(f.polls) > 1 {
+		f.polls = f.polls[1:]
+	}
+	return checks, nil
+}
+
+func (f *ciForge) MergePR(_ int, strategy string) error {
+	f.merged = strategy
+	return nil
+}
+
+func TestFollowPRMergesWhenGreen(t *testing.T) {
+	orig := checkPollInterval
+	defer func() { checkPollInterval = orig }()
+	checkPollInterval = 0
+
+	pending := []forge.Check{{Name: "test", State: forge.CheckPending}}
+	passed := []forge.Check{{Name: "test", State: forge.CheckSuccess}}
+	failed := []forge.Check{{Name: "test", State: forge.CheckFailure, URL: "https://ci/1"}}
+
+	f := &ciForge{polls: [][]forge.Check{nil, pending, passed}}
+	if err := followPR(f, &forge.PR{Number: 7}, false, true, forge.MergeSquash); err != nil {
+		t.Fatal(err)
+	}
+	if f.merged != forge.MergeSquash {
+		t.Errorf("merged with %q, want squash", f.merged)
+	}
+
+	f = &ciForge{polls: [][]forge.Check{pending, failed}}
+	if err := followPR(f, &forge.PR{Number: 7}, true, true, forge.MergeCommit); err == nil || !strings.Contains(err.Error(), "1 check(s) failed") {
+		t.Errorf("followPR error = %v, want failed checks", err)
+	}
+	if f.merged != "" {
+		t.Error("merged despite failing checks")
+	}
+}
+
+func TestFollowPRDoesNotMergeUnchecked(t *testing.T) {
+	origInterval, origTimeout := checkPollInterval, checkTimeout
+	defer func() { checkPollInterval, checkTimeout = origInterval, origTimeout }()
+	checkPollInterval = 0
+
+	// No check ever shows up: nothing says the PR builds.
+	f := &ciForge{polls: [][]forge.Check{nil}}
+	if err := followPR(f, &forge.PR{Number: 7}, false, true, forge.MergeSquash); err == nil || !strings.Contains(err.Error(), "not merging") {
+		t.Errorf("followPR error = %v, want a refusal to merge", err)
+	}
+	if f.merged != "" {
+		t.Error("merged a PR without checks")
+	}
+	// Watching without merging is fine.
+	if err := followPR(&ciForge{polls: [][]forge.Check{nil}}, &forge.PR{Number: 7}, true, false, ""); err != nil {
+		t.Errorf("followPR(--watch) = %v", err)
+	}
+
+	// A check that stays pending runs into the timeout.
+	checkTimeout = 0
+	f = &ciForge{polls: [][]forge.Check{{{Name: "test", State: forge.CheckPending}}}}
+	if err := followPR(f, &forge.PR{Number: 7}, true, true, forge.MergeCommit); err == nil || !strings.Contains(err.Error(), "still pending") {
+		t.Errorf("followPR error = %v, want a timeout", err)
+	}
+	if f.merged != "" {
+		t.Error("merged with pending checks")
+	}
+}
+
+func TestCheckPRHead(t *testing.T) {
+	orig := git.Default
+	defer func() { git.Default = orig }()
+	target := prTarget{Remote: "origin", Push: "fork", Trunk: "main"}
+
+	for _, tt := range []struct {
+		name    string
+		mock    *runYeetMockGit
+		wantErr string
+	}{
+		{"pushed", &runYeetMockGit{refs: map[string]string{"HEAD": "a1", "fork/feat": "a1"}}, ""},
+		{"not pushed", &runYeetMockGit{refs: map[string]string{"HEAD": "a1"}}, "not pushed to fork"},
+		{"ahead", &runYeetMockGit{refs: map[string]string{"HEAD": "b2", "fork/feat": "a1"}, ancestor: true}, "not pushed to fork/feat"},
+		{"diverged", &runYeetMockGit{refs: map[string]string{"HEAD": "b2", "fork/feat": "c3"}}, "differs from fork/feat"},
+	} {
+		git.Default = tt.mock
+		err := checkPRHead(target, "feat")
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: checkPRHead = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPickReviewCommentsWithYes(t *testing.T) {
+	orig := yesFlag
+	defer func() { yesFlag = orig }()
+	yesFlag = true
+
+	lines := review.ParseDiff("diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1,2 +1,3 @@\n package a\n+var x = 1\n func f() {}")
+	findings := []review.Finding{
+		{File: "a.go", Line: 1, Severity: review.Low, Message: "context"},
+		{File: "a.go", Line: 2, Severity: review.High, Message: "added"},
+		{File: "a.go", Line: 40, Severity: review.Medium, Message: "far away"},
+		{File: "go.mod", Severity: review.Low, Message: "whole file"},
+	}
+	comments, outside, err := pickReviewComments(findings, lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []forge.ReviewComment{
+		{Path: "a.go", Line: 1, OldLine: 1, Body: "**Low:** context"},
+		{Path: "a.go", Line: 2, Body: "**High:** added"},
+	}
+	if !reflect.DeepEqual(comments, want) {
+		t.Errorf("comments = %+v, want %+v", comments, want)
+	}
+	wantSummary := "Comments outside the changed lines:\n\n- `a.go:40` — **Medium:** far away\n- `go.mod` — **Low:** whole file"
+	if got := reviewSummary(outside); got != wantSummary {
+		t.Errorf("summary = %q, want %q", got, wantSummary)
+	}
+}
+
+func TestEditedCommentKeepsTextOnEmptyEdit(t *testing.T) {
+	if got := editedComment("nil check", "  \n"); got != "nil check" {
+		t.Errorf("editedComment(empty) = %q, want the previous text", got)
+	}
+	if got := editedComment("nil check", " missing nil check\n"); got != "missing nil check" {
+		t.Errorf("editedComment = %q", got)
+	}
+}
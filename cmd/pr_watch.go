@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/forge"
+	"github.com/rasalas/yeet/internal/term"
+)
+
+var (
+	// checkPollInterval is how often --watch polls the forge.
+	checkPollInterval = 10 * time.Second
+	// checkStartPolls is how many polls --watch waits for the first check
+	// to show up; CI needs a moment to register jobs after a push.
+	checkStartPolls = 3
+	// checkTimeout is how long --watch waits for pending checks to finish
+	// (--watch-timeout).
+	checkTimeout = time.Hour
+)
+
+// mergeStrategy returns the --merge-strategy flag, else pr.merge_strategy,
+// else "merge".
+func mergeStrategy(cfg config.Config) (string, error) {
+	strategy := prMergeStrategy
+	if strategy == "" {
+		strategy = cfg.PR.MergeStrategy
+	}
+	if strategy == "" {
+		return forge.MergeCommit, nil
+	}
+	if !slices.Contains(config.MergeStrategies, strategy) {
+		return "", fmt.Errorf("unknown merge strategy %q (use %s)", strategy, strings.Join(config.MergeStrategies, ", "))
+	}
+	return strategy, nil
+}
+
+// followPR handles --auto-merge and --watch for a PR. Auto-merge is left
+// to the forge when it supports it; otherwise yeet watches the checks and
+// merges once they pass.
+func followPR(f forge.Forge, pr *forge.PR, watch, autoMerge bool, strategy string) error {
+	if pr.Number == 0 {
+		return fmt.Errorf("cannot follow the %s PR: its number is unknown", f.Name())
+	}
+
+	mergeWhenGreen := false
+	if autoMerge {
+		_, canMerge := f.(forge.Merger)
+		if am, ok := f.(forge.AutoMerger); ok {
+			err := am.EnableAutoMerge(pr.Number, strategy)
+			switch {
+			case err == nil:
+				fmt.Printf("  %s✓%s auto-merge enabled %s(%s)%s\n", term.Green, term.Reset, term.Dim, strategy, term.Reset)
+			case canMerge:
+				// e.g. GitHub refuses auto-merge for PRs without pending requirements.
+				fmt.Printf("  %s!%s %v — merging once checks pass\n", term.Yellow, term.Reset, err)
+				mergeWhenGreen = true
+			default:
+				return fmt.Errorf("failed to enable auto-merge: %w", err)
+			}
+		} else if canMerge {
+			fmt.Printf("  %s%s has no auto-merge — merging once checks pass%s\n", term.Dim, f.Name(), term.Reset)
+			mergeWhenGreen = true
+		} else {
+			return fmt.Errorf("merging %s PRs is not supported", f.Name())
+		}
+	}
+	if !watch && !mergeWhenGreen {
+		return nil
+	}
+
+	checker, ok := f.(forge.Checker)
+	if !ok {
+		return fmt.Errorf("watching %s checks is not supported", f.Name())
+	}
+	fmt.Println()
+	checks, err := watchChecks(checker, pr.Number)
+	if err != nil {
+		return err
+	}
+	if failed := forge.FailedChecks(checks); len(failed) > 0 {
+		fmt.Println()
+		for _, c := range failed {
+			fmt.Printf("  %s✗%s %s", term.Red, term.Reset, c.Name)
+			if c.URL != "" {
+				fmt.Printf("  %s%s%s", term.Dim, c.URL, term.Reset)
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+		return fmt.Errorf("%d check(s) failed", len(failed))
+	}
+	if len(checks) == 0 {
+		if mergeWhenGreen {
+			// Without checks nothing says the PR builds, so don't merge it blind.
+			return fmt.Errorf("no checks reported for the %s PR — not merging it untested; merge it on %s yourself", f.Name(), f.Name())
+		}
+		fmt.Printf("  %sNo checks reported.%s\n", term.Dim, term.Reset)
+	}
+
+	if mergeWhenGreen {
+		if err := f.(forge.Merger).MergePR(pr.Number, strategy); err != nil {
+			return fmt.Errorf("failed to merge %s PR: %w", f.Name(), err)
+		}
+		fmt.Printf("\n  %s✓%s %s PR merged %s(%s)%s\n", term.Green, term.Reset, f.Name(), term.Dim, strategy, term.Reset)
+	}
+	return nil
+}
+
+// watchChecks polls the PR's checks, redrawing their status in place,
+// until none is pending. It returns no checks if none show up in time, and
+// an error if some are still pending after checkTimeout.
+func watchChecks(c forge.Checker, number int) ([]forge.Check, error) {
+	start := time.Now()
+	lines := 0
+	for poll := 1; ; poll++ {
+		checks, err := c.Checks(number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch checks: %w", err)
+		}
+		slices.SortStableFunc(checks, func(a, b forge.Check) int { return strings.Compare(a.Name, b.Name) })
+
+		if lines > 0 {
+			term.ClearRenderedBlock(lines)
+		}
+		lines = renderChecks(checks, time.Since(start))
+
+		if len(checks) > 0 && forge.ChecksDone(checks) {
+			return checks, nil
+		}
+		if len(checks) == 0 && poll >= checkStartPolls {
+			term.ClearRenderedBlock(lines)
+			return nil, nil
+		}
+		if elapsed := time.Since(start); elapsed >= checkTimeout {
+			return nil, fmt.Errorf("checks still pending after %s — stopped watching", elapsed.Round(time.Second))
+		}
+		time.Sleep(checkPollInterval)
+	}
+}
+
+// renderChecks prints the check list and returns the number of lines.
+func renderChecks(checks []forge.Check, elapsed time.Duration) int {
+	done := 0
+	for _, c := range checks {
+		if c.State != forge.CheckPending {
+			done++
+		}
+	}
+	elapsedText := elapsed.Round(time.Second).String()
+	if len(checks) == 0 {
+		fmt.Printf("  %sWaiting for checks... %s%s\n", term.Dim, elapsedText, term.Reset)
+		return 1
+	}
+	fmt.Printf("  %sChecks%s %d/%d done %s· %s%s\n", term.Bold, term.Reset, done, len(checks), term.Dim, elapsedText, term.Reset)
+	for _, c := range checks {
+		switch c.State {
+		case forge.CheckSuccess:
+			fmt.Printf("  %s✓%s %s\n", term.Green, term.Reset, c.Name)
+		case forge.CheckFailure:
+			fmt.Printf("  %s✗%s %s\n", term.Red, term.Reset, c.Name)
+		case forge.CheckSkipped:
+			fmt.Printf("  %s– %s%s\n", term.Dim, c.Name, term.Reset)
+		default:
+			fmt.Printf("  %s●%s %s\n", term.Yellow, term.Reset, c.Name)
+		}
+	}
+	return len(checks) + 1
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 
 	"github.com/rasalas/yeet/internal/ai"
+	"github.com/rasalas/yeet/internal/config"
 	"github.com/rasalas/yeet/internal/term"
 	"github.com/spf13/cobra"
 )
@@ -18,10 +19,7 @@ var promptCmd = &cobra.Command{
 			return RunAsCommit("prompt", args)
 		}
 
-		// Ensure the file exists with default content
-		ai.LoadPrompt()
-
-		path, err := ai.PromptPath()
+		path, err := activePromptPath()
 		if err != nil {
 			return err
 		}
@@ -58,11 +56,26 @@ var promptShowCmd = &cobra.Command{
 	Short: "Show the current prompt",
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println()
-		fmt.Println(ai.LoadPrompt())
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = config.DefaultConfig()
+		}
+		fmt.Println(ai.LoadPromptFile(cfg.PromptPath()))
 		fmt.Println()
 	},
 }
 
+// activePromptPath returns the prompt file of the active profile when it sets one,
+// otherwise prompt.txt (created with the default prompt if missing).
+func activePromptPath() (string, error) {
+	if cfg, err := config.Load(); err == nil && cfg.PromptPath() != "" {
+		return cfg.PromptPath(), nil
+	}
+	// Ensure the file exists with default content
+	ai.LoadPrompt()
+	return ai.PromptPath()
+}
+
 func init() {
 	promptCmd.AddCommand(promptResetCmd)
 	promptCmd.AddCommand(promptShowCmd)
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rasalas/yeet/internal/ai"
+	"github.com/rasalas/yeet/internal/changelog"
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/forge"
+	"github.com/rasalas/yeet/internal/git"
+	"github.com/rasalas/yeet/internal/semver"
+	"github.com/rasalas/yeet/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseDryRun       bool
+	releasePre          string
+	releaseBump         string
+	releaseNoPush       bool
+	releaseForgeRelease bool
+)
+
+func init() {
+	releaseCmd.Flags().BoolVar(&releaseDryRun, "dry-run", false, "Show the next version and its release notes without tagging")
+	releaseCmd.Flags().StringVar(&releasePre, "pre", "", "Make a pre-release with this id, e.g. rc for v1.3.0-rc.1")
+	releaseCmd.Flags().StringVar(&releaseBump, "bump", "", "Bump major, minor or patch instead of what the commits call for")
+	releaseCmd.Flags().BoolVar(&releaseNoPush, "no-push", false, "Create the tag but don't push it")
+	releaseCmd.Flags().BoolVar(&releaseForgeRelease, "forge-release", false, "Also create a release on the forge (see release.forge_release)")
+	releaseCmd.Flags().BoolVar(&changelogNoAI, "no-ai", false, "Use the commit subjects as release notes instead of having the AI write them")
+	rootCmd.AddCommand(releaseCmd)
+}
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Tag the next semantic version with AI-written release notes",
+	Long: "Compute the next version from the conventional commits since the last version tag (breaking → major, " +
+		"feat → minor, fix → patch), create an annotated tag with release notes as its message, push it " +
+		"and optionally publish a release on the forge.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return RunAsCommit("release", args)
+		}
+		return runRelease(cmd, args)
+	},
+
+	SilenceUsage: true,
+}
+
+func runRelease(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	if releaseBump != "" && !slices.Contains(semver.Levels, releaseBump) {
+		return fmt.Errorf("unknown bump %q (use %s)", releaseBump, strings.Join(semver.Levels, ", "))
+	}
+
+	plan, skip, err := planRelease(cfg)
+	if err != nil {
+		return err
+	}
+	if skip != "" {
+		fmt.Printf("\n  %s%s%s\n\n", term.Dim, skip, term.Reset)
+		return nil
+	}
+	remote := cfg.PushRemoteName()
+	if !releaseNoPush {
+		if err := checkHeadPushed(remote); err != nil {
+			return err
+		}
+	}
+	if branch, err := git.CurrentBranch(); err == nil {
+		if trunk, err := git.DefaultBranch(); err == nil && branch != trunk {
+			fmt.Printf("  %s!%s Releasing from %s, not %s\n", term.Yellow, term.Reset, branch, trunk)
+		}
+	}
+	from := plan.last
+	if from == "" {
+		from = "no release yet"
+	}
+	fmt.Printf("\n  %s → %s%s%s  %s(%s)%s\n", from, term.Bold, plan.next, term.Reset, term.Dim, bumpReason(plan.level, plan.current, releasePre), term.Reset)
+
+	if !yesFlag && !releaseDryRun {
+		if plan.next, err = confirmVersion(plan.next); err != nil || plan.next == (semver.Version{}) {
+			return err
+		}
+	}
+
+	var n notes
+	var usage *ai.Usage
+	if sections, groups := changelog.Group(plan.entries); len(sections) > 0 {
+		if n, usage, err = releaseNotes(cfg, sections, groups, true); err != nil {
+			return err
+		}
+	}
+	release := changelog.Render(plan.next.Number(), time.Now().Format("2006-01-02"), n.sections, n.items)
+
+	publish := (releaseForgeRelease || cfg.Release.ForgeRelease) && !releaseNoPush
+	if releaseDryRun {
+		displayRelease(release)
+		steps := "tag " + plan.next.String()
+		if !releaseNoPush {
+			steps += ", push it to " + remote
+		}
+		if publish {
+			steps += " and create a forge release"
+		}
+		fmt.Printf("  %sDry run — would %s.%s\n", term.Dim, steps, term.Reset)
+		printPRUsage(usage)
+		return nil
+	}
+
+	release, ok, err := confirmRelease(release, "tag "+plan.next.String())
+	if err != nil || !ok {
+		return err
+	}
+	_, body, _ := strings.Cut(strings.TrimSpace(release), "\n")
+	body = strings.TrimSpace(body)
+
+	tag := plan.next.String()
+	if err := git.CreateTag(tag, strings.TrimSpace(tag+"\n\n"+body)+"\n"); err != nil {
+		return fmt.Errorf("failed to create tag %s: %w", tag, err)
+	}
+	fmt.Printf("  %s✓%s Tagged %s\n", term.Green, term.Reset, tag)
+
+	if releaseNoPush {
+		fmt.Printf("  %sPush it with: git push %s %s%s\n", term.Dim, remote, tag, term.Reset)
+		printPRUsage(usage)
+		return nil
+	}
+	if out, err := git.PushTag(remote, tag); err != nil {
+		return fmt.Errorf("failed to push %s to %s: %s", tag, remote, out)
+	}
+	fmt.Printf("  %s✓%s Pushed %s to %s\n", term.Green, term.Reset, tag, remote)
+
+	if publish {
+		if err := publishRelease(cfg, remote, forge.Release{Tag: tag, Name: tag, Body: body, Prerelease: plan.next.Pre != ""}); err != nil {
+			return err
+		}
+	}
+	printPRUsage(usage)
+	return nil
+}
+
+// releasePlan is the next version and the commits its notes are made of.
+type releasePlan struct {
+	last          string // latest version tag; "" before the first release
+	current, next semver.Version
+	level         string
+	entries       []changelog.Entry
+}
+
+// planRelease computes the next version from the commits since the last
+// version tag. The notes of a pre-release and of its release cover
+// everything since the last release, not just since the previous
+// pre-release, and a pre-release can be promoted without new commits.
+// When there is nothing to release, skip says why.
+func planRelease(cfg config.Config) (plan releasePlan, skip string, err error) {
+	plan.current = semver.Version{Prefix: "v"}
+	if plan.last, err = git.LatestTag(); err != nil {
+		plan.last = ""
+	} else if plan.current, err = semver.Parse(plan.last); err != nil {
+		return plan, "", fmt.Errorf("latest tag: %w", err)
+	}
+
+	from := plan.last
+	if plan.current.Pre != "" {
+		if releasePre != "" {
+			if log, _ := git.LogBetween(plan.last, "HEAD"); log == "" {
+				return plan, fmt.Sprintf("No commits since %s — nothing to release.", plan.last), nil
+			}
+		}
+		from, _ = git.LatestReleaseTag()
+	}
+	if plan.entries, err = changelogEntries(cfg, from, "HEAD", true); err != nil {
+		return plan, "", err
+	}
+	if len(plan.entries) == 0 && (plan.current.Pre == "" || releasePre != "") {
+		return plan, fmt.Sprintf("No commits since %s — nothing to release.", plan.last), nil
+	}
+
+	plan.level = releaseBump
+	if plan.level == "" {
+		plan.level = changelog.Level(plan.entries)
+	}
+	if plan.level == "" && plan.current.Pre == "" {
+		return plan, fmt.Sprintf("Only internal changes since %s — nothing to release. Use --bump patch to release anyway.", plan.last), nil
+	}
+	plan.next = plan.current.Bump(plan.level, releasePre, releaseBump != "")
+	return plan, "", nil
+}
+
+// checkHeadPushed refuses to release a HEAD that is not on the remote, as
+// pushing the tag would publish the unpushed commits with it.
+func checkHeadPushed(remote string) error {
+	branches, err := git.RemoteBranchesContaining("HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to check whether HEAD is pushed: %w", err)
+	}
+	for _, b := range branches {
+		if strings.HasPrefix(b, remote+"/") {
+			return nil
+		}
+	}
+	return fmt.Errorf("HEAD is not pushed to %s — push it first, or tag it locally with --no-push", remote)
+}
+
+// bumpReason explains where the next version comes from.
+func bumpReason(level string, current semver.Version, pre string) string {
+	switch {
+	case current.Pre != "" && pre == "":
+		return "release of " + current.String()
+	case current.Pre != "":
+		return "next pre-release"
+	case releaseBump != "":
+		return level + ", from --bump"
+	case level == semver.Major && current.Major == 0:
+		return "minor: breaking changes before 1.0.0"
+	case level == semver.Major:
+		return "major: breaking changes"
+	case level == semver.Minor:
+		return "minor: new features"
+	}
+	return "patch: fixes"
+}
+
+// confirmVersion asks before tagging the proposed version and lets the
+// user type another one. It returns the zero Version when cancelled.
+func confirmVersion(next semver.Version) (semver.Version, error) {
+	for {
+		hintLines := term.PrintHintActions([]term.HintAction{
+			{Key: "enter", Desc: "release " + next.String()},
+			{Key: "e", Desc: "edit version"},
+			{Key: "q", Desc: "cancel"},
+		}, term.TerminalWidth())
+
+		action, err := term.WaitForAction()
+		if err != nil {
+			return semver.Version{}, err
+		}
+		switch action {
+		case term.ActionCancel:
+			fmt.Printf("\n  %sCancelled.%s\n", term.Dim, term.Reset)
+			return semver.Version{}, nil
+		case term.ActionEdit, term.ActionEditExternal:
+			term.ClearRenderedBlock(hintLines)
+			edited, err := term.EditLine(next.String())
+			if err != nil {
+				return semver.Version{}, err
+			}
+			fmt.Println()
+			v, err := semver.Parse(edited)
+			if err != nil {
+				fmt.Printf("  %s!%s %v\n", term.Yellow, term.Reset, err)
+				continue
+			}
+			next = v
+			continue
+		}
+		return next, nil
+	}
+}
+
+// publishRelease creates a release for a pushed tag on the remote's forge.
+func publishRelease(cfg config.Config, remote string, r forge.Release) error {
+	f, err := detectForge(cfg, remote, remote)
+	if err != nil {
+		return err
+	}
+	releaser, ok := f.(forge.Releaser)
+	if !ok {
+		fmt.Printf("  %s!%s %s releases are not supported — the tag is pushed\n", term.Yellow, term.Reset, f.Name())
+		return nil
+	}
+	url, err := releaser.CreateRelease(r)
+	if err != nil {
+		return fmt.Errorf("failed to create %s release: %w", f.Name(), err)
+	}
+	fmt.Printf("  %s✓%s %s release created: %s\n", term.Green, term.Reset, f.Name(), url)
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/git"
+)
+
+func TestPlanReleasePromotesPreRelease(t *testing.T) {
+	orig := git.Default
+	defer func() { git.Default = orig }()
+	defer func(pre string) { releasePre = pre }(releasePre)
+	cfg := config.DefaultConfig()
+
+	// v1.3.0-rc.1 is on HEAD: nothing since the pre-release, but its
+	// release still gets the notes of everything since v1.2.0.
+	git.Default = &runYeetMockGit{
+		latestTag:  "v1.3.0-rc.1",
+		releaseTag: "v1.2.0",
+		logs:       map[string]string{"v1.2.0..HEAD": "b2 feat: add export\na1 fix: crash on start"},
+	}
+	releasePre = ""
+	plan, skip, err := planRelease(cfg)
+	if err != nil || skip != "" {
+		t.Fatalf("planRelease = %q, %v", skip, err)
+	}
+	if plan.next.String() != "v1.3.0" || len(plan.entries) != 2 {
+		t.Errorf("next = %s with %d entries, want v1.3.0 with 2", plan.next, len(plan.entries))
+	}
+
+	// Another pre-release needs new commits.
+	releasePre = "rc"
+	if _, skip, err := planRelease(cfg); err != nil || skip == "" {
+		t.Errorf("planRelease(--pre rc) = %q, %v, want nothing to release", skip, err)
+	}
+}
+
+func TestCheckHeadPushed(t *testing.T) {
+	orig := git.Default
+	defer func() { git.Default = orig }()
+
+	git.Default = &runYeetMockGit{}
+	if err := checkHeadPushed("origin"); err == nil {
+		t.Error("checkHeadPushed succeeded for an unpushed HEAD")
+	}
+	git.Default = &runYeetMockGit{pushedTo: []string{"fork/main"}}
+	if err := checkHeadPushed("origin"); err == nil {
+		t.Error("checkHeadPushed succeeded for a HEAD only on another remote")
+	}
+	git.Default = &runYeetMockGit{pushedTo: []string{"fork/main", "origin/main"}}
+	if err := checkHeadPushed("origin"); err != nil {
+		t.Errorf("checkHeadPushed = %v for a pushed HEAD", err)
+	}
+}
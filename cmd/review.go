@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rasalas/yeet/internal/ai"
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/git"
+	"github.com/rasalas/yeet/internal/review"
+	"github.com/rasalas/yeet/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reviewBranch bool
+	reviewBase   string
+	reviewFormat string
+	reviewOutput string
+	reviewFailOn string
+)
+
+func init() {
+	reviewCmd.Flags().BoolVar(&reviewBranch, "branch", false, "Review the branch's commits since the default branch instead of the staged changes")
+	reviewCmd.Flags().StringVar(&reviewBase, "base", "", "Review the branch's commits since this ref (implies --branch)")
+	reviewCmd.Flags().StringVar(&reviewFormat, "format", "text", "Output format: text, json or sarif")
+	reviewCmd.Flags().StringVarP(&reviewOutput, "output", "o", "", "Write the findings to a file (json/sarif) instead of stdout")
+	reviewCmd.Flags().StringVar(&reviewFailOn, "fail-on", "", "Exit non-zero on findings this severe or worse: high, medium, low or never (default: review.block_on)")
+	rootCmd.AddCommand(reviewCmd)
+}
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review the staged changes with AI",
+	Long: "Send the staged diff — or with --branch/--base the branch's changes, as yeet pr sees them — to the AI " +
+		"for review and list its findings by file. Findings can be exported as JSON or SARIF for editors.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return RunAsCommit("review", args)
+		}
+		return runReview(cmd, args)
+	},
+
+	SilenceUsage: true,
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	if !slices.Contains([]string{"text", "json", "sarif"}, reviewFormat) {
+		return fmt.Errorf("unknown format %q (use text, json or sarif)", reviewFormat)
+	}
+	failOn := cfg.Review.BlockLevel()
+	if reviewFailOn != "" {
+		if !slices.Contains(config.ReviewBlockLevels, reviewFailOn) {
+			return fmt.Errorf("unknown severity %q (use %s)", reviewFailOn, strings.Join(config.ReviewBlockLevels, ", "))
+		}
+		failOn = reviewFailOn
+	}
+	// Machine-readable output on stdout must not be mixed with progress.
+	quiet := reviewFormat != "text" && reviewOutput == ""
+
+	var diff, stat string
+	if reviewBranch || reviewBase != "" {
+		base := reviewBase
+		if base == "" {
+			if base, err = git.DefaultBranch(); err != nil {
+				return fmt.Errorf("failed to detect default branch: %w", err)
+			}
+		}
+		if diff, err = git.DiffRange(base); err != nil {
+			return fmt.Errorf("failed to get diff: %w", err)
+		}
+		stat, _ = git.DiffStatRange(base)
+	} else {
+		if diff, err = git.DiffCached(); err != nil {
+			return fmt.Errorf("failed to get diff: %w", err)
+		}
+		stat, _ = git.DiffStat()
+	}
+	if strings.TrimSpace(diff) == "" {
+		if quiet {
+			return writeFindings(nil)
+		}
+		fmt.Printf("\n  %sNothing to review — stage changes or use --branch.%s\n\n", term.Dim, term.Reset)
+		return nil
+	}
+
+	if !quiet {
+		fmt.Println()
+		for _, line := range strings.Split(stat, "\n") {
+			fmt.Println("  " + term.ColorizeDiffStat(line))
+		}
+		fmt.Println()
+	}
+
+	branch, _ := git.CurrentBranch()
+	findings, usage, err := reviewDiff(cfg, diff, branch, !quiet)
+	if err != nil {
+		return err
+	}
+
+	if reviewFormat == "text" {
+		printFindings(findings)
+	} else if err := writeFindings(findings); err != nil {
+		return err
+	}
+	if !quiet {
+		printPRUsage(usage)
+	}
+
+	if failOn != "never" {
+		if n := review.Count(findings, failOn); n > 0 {
+			return fmt.Errorf("%d finding(s) of %s severity or worse", n, failOn)
+		}
+	}
+	return nil
+}
+
+// writeFindings writes the findings as JSON or SARIF to --output or stdout.
+func writeFindings(findings []review.Finding) error {
+	encode := review.JSON
+	if reviewFormat == "sarif" {
+		encode = review.SARIF
+	}
+	data, err := encode(findings)
+	if err != nil {
+		return err
+	}
+	if reviewOutput == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := os.WriteFile(reviewOutput, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", reviewOutput, err)
+	}
+	fmt.Printf("  %s✓%s %d finding(s) written to %s\n", term.Green, term.Reset, len(findings), reviewOutput)
+	return nil
+}
+
+// reviewDiff asks the AI to review a diff. The diff is annotated with line
+// numbers so findings point at the right lines.
+func reviewDiff(cfg config.Config, diff, branch string, spin bool) ([]review.Finding, *ai.Usage, error) {
+	ctx := ai.CommitContext{
+		Diff:         review.Annotate(diff),
+		Branch:       branch,
+		SystemPrompt: ai.ReviewPrompt,
+		MaxTokens:    2048,
+	}
+	label := ""
+	if spin {
+		label = "Reviewing..."
+	}
+	reply, usage, err := generateText(cfg, ctx, label)
+	if err != nil {
+		return nil, nil, fmt.Errorf("AI review failed: %w", err)
+	}
+	findings, err := review.Parse(reply)
+	if err != nil {
+		return nil, usage, err
+	}
+	return findings, usage, nil
+}
+
+// printFindings renders one card per file.
+func printFindings(findings []review.Finding) {
+	if len(findings) == 0 {
+		fmt.Printf("  %s✓%s No issues found\n", term.Green, term.Reset)
+		return
+	}
+	files, groups := review.ByFile(findings)
+	for _, file := range files {
+		var lines []string
+		for _, f := range groups[file] {
+			where := "file"
+			if f.Line > 0 {
+				where = fmt.Sprintf("L%d", f.Line)
+			}
+			lines = append(lines, fmt.Sprintf("%-6s %-5s %s", f.Severity, where, f.Message))
+		}
+		term.DisplayCard(file, strings.Join(lines, "\n"))
+	}
+	var counts []string
+	for _, sev := range review.Severities {
+		n := 0
+		for _, f := range findings {
+			if f.Severity == sev {
+				n++
+			}
+		}
+		if n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, sev))
+		}
+	}
+	fmt.Printf("  %s\n", strings.Join(counts, " · "))
+}
+
+// reviewBeforeCommit reviews the staged changes in the commit flow. It
+// returns false when findings at review.block_on or worse should stop the
+// commit and the user declines to go on; with -y it fails instead. A
+// review that can't run doesn't block.
+func reviewBeforeCommit(cfg config.Config) (bool, error) {
+	diff, err := git.DiffCached()
+	if err != nil {
+		return false, fmt.Errorf("failed to get diff: %w", err)
+	}
+	branch, _ := git.CurrentBranch()
+	findings, usage, err := reviewDiff(cfg, diff, branch, true)
+	if err != nil {
+		fmt.Printf("  %s!%s review skipped: %v\n\n", term.Yellow, term.Reset, err)
+		return true, nil
+	}
+	printFindings(findings)
+	printPRUsage(usage)
+	if usage == nil || usage.InputTokens == 0 {
+		fmt.Println()
+	}
+
+	level := cfg.Review.BlockLevel()
+	if level == "never" {
+		return true, nil
+	}
+	n := review.Count(findings, level)
+	if n == 0 {
+		return true, nil
+	}
+	if yesFlag {
+		return false, fmt.Errorf("review found %d finding(s) of %s severity or worse", n, level)
+	}
+	fmt.Printf("  %s!%s %d finding(s) of %s severity or worse. Commit anyway? %s[y/n]%s ", term.Yellow, term.Reset, n, level, term.Dim, term.Reset)
+	ok, err := term.WaitForYesNo()
+	fmt.Println()
+	return ok, err
+}
@@ -17,12 +17,15 @@ var (
 	messageFlag string
 	yesFlag     bool
 	localFlag   bool
+	reviewFlag  bool
 )
 
 func init() {
 	rootCmd.Flags().StringVarP(&messageFlag, "message", "m", "", "Commit message (use when message collides with a subcommand name)")
 	rootCmd.PersistentFlags().BoolVarP(&yesFlag, "yes", "y", false, "Skip confirmation prompts and accept defaults")
 	rootCmd.Flags().BoolVarP(&localFlag, "local", "l", false, "Commit locally without pushing")
+	rootCmd.Flags().BoolVar(&reviewFlag, "review", false, "Review the staged changes with AI before committing (see review.before_commit)")
+	rootCmd.PersistentFlags().StringVar(&config.ProfileOverride, "profile", "", "Config profile to use (overrides YEET_PROFILE and auto-matching)")
 }
 
 var rootCmd = &cobra.Command{
@@ -31,6 +34,28 @@ var rootCmd = &cobra.Command{
 	Long:  "Stage all changes, generate or use a commit message, and push — all in one step.",
 	Args:  cobra.ArbitraryArgs,
 	RunE:  runYeet,
+
+	PersistentPreRunE: checkProfileFlag,
+}
+
+// checkProfileFlag rejects --profile names that don't exist in config.toml,
+// so a typo doesn't silently fall back to the base config.
+func checkProfileFlag(cmd *cobra.Command, args []string) error {
+	if config.ProfileOverride == "" {
+		return nil
+	}
+	cfg, err := config.LoadFile()
+	if err != nil {
+		return nil
+	}
+	if _, ok := cfg.Profiles[config.ProfileOverride]; !ok {
+		names := cfg.ProfileNames()
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile %q — no [profiles] defined in config.toml", config.ProfileOverride)
+		}
+		return fmt.Errorf("unknown profile %q (available: %s)", config.ProfileOverride, strings.Join(names, ", "))
+	}
+	return nil
 }
 
 func Execute() {
@@ -47,6 +72,11 @@ func RunAsCommit(subcommandName string, args []string) error {
 }
 
 func runYeet(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
 	// 1. Stage: respect existing staged changes, otherwise stage all
 	autoStaged := false
 	if !git.HasStagedChanges() {
@@ -71,12 +101,29 @@ func runYeet(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
+	// Review first, so a blocked commit doesn't pay for a message too.
+	if reviewFlag || cfg.Review.BeforeCommit {
+		ok, err := reviewBeforeCommit(cfg)
+		if err != nil || !ok {
+			if autoStaged {
+				if err := git.Reset(); err != nil {
+					return fmt.Errorf("failed to unstage changes: %w", err)
+				}
+			}
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			fmt.Printf("  %sCancelled.%s\n", term.Dim, term.Reset)
+			return nil
+		}
+	}
+
 	// 3. Get commit message
 	var message string
 	var usage *ai.Usage
 	streamed := false
 	var capture *commitRunCapture
-	editedByUser := false
 	if messageFlag != "" {
 		message = messageFlag
 	} else if len(args) > 0 {
@@ -89,65 +136,18 @@ func runYeet(cmd *cobra.Command, args []string) error {
 	}
 
 	// 4. Confirm loop (show message, allow edit) — skip with -y
-	if yesFlag {
-		if streamed {
-			term.ClearRenderedBlock(streamedPreviewRenderedLines(message, terminalWidth()))
-		}
-		printMessage(message)
-	} else {
-		if streamed {
-			term.ClearRenderedBlock(streamedPreviewRenderedLines(message, terminalWidth()))
-		}
-		linesToClear := 3
-		for {
-			width := terminalWidth()
-			linesToClear = renderCommitConfirmation(message, width)
-
-			action, err := term.WaitForAction()
-			if err != nil {
-				return err
-			}
-
-			switch action {
-			case term.ActionCancel:
-				fmt.Println()
-				if autoStaged {
-					if err := git.Reset(); err != nil {
-						return fmt.Errorf("failed to unstage changes: %w", err)
-					}
-				}
-				fmt.Printf("  %sCancelled.%s\n", term.Dim, term.Reset)
-				return nil
-			case term.ActionEdit:
-				term.ClearLines(linesToClear)
-				prev := message
-				edited, err := term.EditLine(message)
-				if err != nil {
-					return err
-				}
-				message = edited
-				if message != prev {
-					editedByUser = true
-				}
-				continue
-			case term.ActionEditExternal:
-				term.ClearLines(linesToClear)
-				prev := message
-				edited, err := term.EditExternal(message)
-				if err != nil {
-					fmt.Printf("\n  Editor failed: %v\n", err)
-				} else {
-					message = edited
-					if message != prev {
-						editedByUser = true
-					}
-				}
-				continue
-			case term.ActionConfirm:
-				fmt.Println()
+	message, editedByUser, ok, err := confirmMessage(message, streamed, "commit")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if autoStaged {
+			if err := git.Reset(); err != nil {
+				return fmt.Errorf("failed to unstage changes: %w", err)
 			}
-			break
 		}
+		fmt.Printf("  %sCancelled.%s\n", term.Dim, term.Reset)
+		return nil
 	}
 
 	// 6. Commit
@@ -162,23 +162,29 @@ func runYeet(cmd *cobra.Command, args []string) error {
 		if editedByUser {
 			userAction = "edited"
 		}
-		_ = saveCommitRunCapture(*capture, usage, message, userAction, localFlag)
+		_ = saveCommitRunCapture(*capture, usage, message, userAction, localFlag || !cfg.PushEnabled())
 	}
 
 	// 7. Push (unless local-only)
 	if localFlag {
 		fmt.Printf("  %s✓%s %slocal commit only%s (skipped push)\n", term.Green, term.Reset, term.Dim, term.Reset)
+	} else if !cfg.PushEnabled() {
+		reason := "push = false in config"
+		if cfg.Profiles[cfg.ActiveProfile].Push != nil {
+			reason = "push disabled by profile " + cfg.ActiveProfile
+		}
+		fmt.Printf("  %s✓%s %slocal commit only%s (%s)\n", term.Green, term.Reset, term.Dim, term.Reset, reason)
 	} else {
 		pushOut, err := git.Push()
 		if err != nil {
-			pushOut, err = git.PushSetUpstream()
+			pushOut, err = git.PushSetUpstream(cfg.PushRemoteName())
 			if err != nil {
 				return fmt.Errorf("push failed: %s", pushOut)
 			}
 		}
 
 		branch, _ := git.CurrentBranch()
-		fmt.Printf("  %s✓%s %spushed to%s origin/%s\n", term.Green, term.Reset, term.Dim, term.Reset, branch)
+		fmt.Printf("  %s✓%s %spushed to%s %s/%s\n", term.Green, term.Reset, term.Dim, term.Reset, cfg.PushRemoteName(), branch)
 	}
 
 	if usage != nil && usage.InputTokens > 0 {
@@ -192,10 +198,68 @@ func runYeet(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func renderCommitConfirmation(message string, width int) int {
+// confirmMessage shows a commit message and lets the user accept, edit or
+// cancel it; action is what enter does ("commit", "amend", …). With -y the
+// message is accepted as it is. It reports whether the user changed the
+// message and whether it was accepted.
+func confirmMessage(message string, streamed bool, action string) (string, bool, bool, error) {
+	if streamed {
+		term.ClearRenderedBlock(streamedPreviewRenderedLines(message, terminalWidth()))
+	}
+	if yesFlag {
+		printMessage(message)
+		return message, false, true, nil
+	}
+
+	editedByUser := false
+	for {
+		linesToClear := renderCommitConfirmation(message, action, terminalWidth())
+
+		a, err := term.WaitForAction()
+		if err != nil {
+			return message, editedByUser, false, err
+		}
+
+		switch a {
+		case term.ActionCancel:
+			fmt.Println()
+			return message, editedByUser, false, nil
+		case term.ActionEdit:
+			term.ClearLines(linesToClear)
+			prev := message
+			edited, err := term.EditLine(message)
+			if err != nil {
+				return message, editedByUser, false, err
+			}
+			message = edited
+			if message != prev {
+				editedByUser = true
+			}
+			continue
+		case term.ActionEditExternal:
+			term.ClearLines(linesToClear)
+			prev := message
+			edited, err := term.EditExternal(message)
+			if err != nil {
+				fmt.Printf("\n  Editor failed: %v\n", err)
+			} else {
+				message = edited
+				if message != prev {
+					editedByUser = true
+				}
+			}
+			continue
+		case term.ActionConfirm:
+			fmt.Println()
+		}
+		return message, editedByUser, true, nil
+	}
+}
+
+func renderCommitConfirmation(message, action string, width int) int {
 	messageLines := printMessage(message)
 	hintLines := term.PrintHintActions([]term.HintAction{
-		{Key: "enter", Desc: "commit"},
+		{Key: "enter", Desc: action},
 		{Key: "e", Desc: "edit"},
 		{Key: "E", Desc: "editor"},
 		{Key: "q", Desc: "cancel"},
@@ -210,14 +274,76 @@ func printMessage(message string) int {
 }
 
 func generateOrFallback() (string, *ai.Usage, bool, *commitRunCapture, error) {
-	cfg, err := config.Load()
+	return generateMessage(stagedContext)
+}
+
+// stagedContext collects the git context for a message of the staged changes.
+func stagedContext() (ai.CommitContext, error) {
+	diff, err := git.DiffCached()
 	if err != nil {
-		cfg = config.DefaultConfig()
+		return ai.CommitContext{}, fmt.Errorf("failed to get diff: %w", err)
 	}
 
+	branch, _ := git.CurrentBranch()
+	recentLog, _ := git.LogOneline()
+	status, _ := git.StatusShort()
+
+	return ai.CommitContext{
+		Diff:          diff,
+		Branch:        branch,
+		RecentCommits: recentLog,
+		Status:        status,
+	}, nil
+}
+
+// setPricing registers the model prices from the config, so usage shows
+// costs for models yeet doesn't know.
+func setPricing(cfg config.Config) {
 	for model, p := range cfg.Pricing {
 		ai.SetPricing(model, p.Input, p.Output)
 	}
+}
+
+// generateText sends ctx to the configured provider and returns the whole
+// reply, with a spinner labelled spinnerLabel ("" for none) while waiting.
+func generateText(cfg config.Config, ctx ai.CommitContext, spinnerLabel string) (string, *ai.Usage, error) {
+	setPricing(cfg)
+	provider, err := ai.NewProvider(cfg)
+	if err != nil {
+		return "", nil, fmt.Errorf("no AI provider configured: %w", err)
+	}
+
+	var s term.Spinner
+	if spinnerLabel != "" {
+		s.Start(spinnerLabel)
+	}
+	var reply string
+	var usage ai.Usage
+	// Streaming requests are not cut off by the default request timeout.
+	if sp, ok := provider.(ai.StreamingProvider); ok {
+		reply, usage, err = sp.GenerateCommitMessageStream(ctx, func(string) {})
+	} else {
+		reply, usage, err = provider.GenerateCommitMessage(ctx)
+	}
+	if spinnerLabel != "" {
+		s.Stop()
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return reply, &usage, nil
+}
+
+// generateMessage generates a commit message for the context collect
+// returns, falling back to asking for one when no provider is set up or
+// generation fails.
+func generateMessage(collect func() (ai.CommitContext, error)) (string, *ai.Usage, bool, *commitRunCapture, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	setPricing(cfg)
 
 	provider, providerErr := ai.NewProvider(cfg)
 
@@ -250,20 +376,12 @@ func generateOrFallback() (string, *ai.Usage, bool, *commitRunCapture, error) {
 	}
 
 	// AI generation — collect git context
-	diff, err := git.DiffCached()
+	ctx, err := collect()
 	if err != nil {
-		return "", nil, false, nil, fmt.Errorf("failed to get diff: %w", err)
+		return "", nil, false, nil, err
 	}
-
-	branch, _ := git.CurrentBranch()
-	recentLog, _ := git.LogOneline()
-	status, _ := git.StatusShort()
-
-	ctx := ai.CommitContext{
-		Diff:          diff,
-		Branch:        branch,
-		RecentCommits: recentLog,
-		Status:        status,
+	if path := cfg.PromptPath(); path != "" {
+		ctx.SystemPrompt = ai.LoadPromptFile(path)
 	}
 
 	// Try streaming if supported
@@ -347,7 +465,7 @@ func generateStreaming(sp ai.StreamingProvider, ctx ai.CommitContext) (string, a
 
 func quickSetup(cfg config.Config) error {
 	fmt.Println()
-	return readAndSaveKey(cfg.Provider)
+	return readAndSaveKey(cfg.Provider, cfg.Keys[cfg.Provider])
 }
 
 func promptForMessage(initial string) (string, error) {
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -18,6 +19,20 @@ type runYeetMockGit struct {
 	commitMessage         string
 	pushCalled            bool
 	pushSetUpstreamCalled bool
+	// refs maps refs to commits for ResolveCommit; without it every ref
+	// resolves to itself.
+	refs     map[string]string
+	ancestor bool
+	// latestTag and releaseTag are the tags on HEAD's history; logs maps
+	// "from..to" ranges to git log --oneline output.
+	latestTag, releaseTag string
+	logs                  map[string]string
+	pushedTo              []string
+	fetchErr              error
+	// commitErr fails Commit; resets and updatedRefs record history edits.
+	commitErr   error
+	resets      []string
+	updatedRefs map[string]string
 }
 
 func (m *runYeetMockGit) HasStagedChanges() bool      { return m.hasStagedChanges }
@@ -27,22 +42,96 @@ func (m *runYeetMockGit) DiffCached() (string, error) { return "", nil }
 func (m *runYeetMockGit) Commit(msg string) (string, error) {
 	m.commitCalled = true
 	m.commitMessage = msg
-	return m.commitOut, nil
+	return m.commitOut, m.commitErr
 }
 func (m *runYeetMockGit) Push() (string, error) { m.pushCalled = true; return "", nil }
-func (m *runYeetMockGit) PushSetUpstream() (string, error) {
+func (m *runYeetMockGit) PushSetUpstream(string) (string, error) {
 	m.pushSetUpstreamCalled = true
 	return "", nil
 }
-func (m *runYeetMockGit) Reset() error                         { return nil }
-func (m *runYeetMockGit) LogOneline() (string, error)          { return "", nil }
-func (m *runYeetMockGit) StatusShort() (string, error)         { return "", nil }
-func (m *runYeetMockGit) CurrentBranch() (string, error)       { return m.currentBranch, nil }
-func (m *runYeetMockGit) DefaultBranch() (string, error)       { return "main", nil }
-func (m *runYeetMockGit) LogRange(string) (string, error)      { return "", nil }
-func (m *runYeetMockGit) DiffRange(string) (string, error)     { return "", nil }
-func (m *runYeetMockGit) DiffStatRange(string) (string, error) { return "", nil }
-func (m *runYeetMockGit) HasUpstream() bool                    { return true }
+func (m *runYeetMockGit) Reset() error                               { return nil }
+func (m *runYeetMockGit) LogOneline() (string, error)                { return "", nil }
+func (m *runYeetMockGit) StatusShort() (string, error)               { return "", nil }
+func (m *runYeetMockGit) CurrentBranch() (string, error)             { return m.currentBranch, nil }
+func (m *runYeetMockGit) DefaultBranch() (string, error)             { return "main", nil }
+func (m *runYeetMockGit) RemoteDefaultBranch(string) (string, error) { return "main", nil }
+func (m *runYeetMockGit) Remotes() ([]string, error)                 { return []string{"origin"}, nil }
+func (m *runYeetMockGit) RemoteURL(string) (string, error)           { return "", nil }
+func (m *runYeetMockGit) Fetch(string, string) (string, error) {
+	if m.fetchErr != nil {
+		return "fatal: " + m.fetchErr.Error(), m.fetchErr
+	}
+	return "", nil
+}
+func (m *runYeetMockGit) LogRange(string) (string, error) { return "", nil }
+func (m *runYeetMockGit) LogBetween(from, to string) (string, error) {
+	return m.logs[from+".."+to], nil
+}
+func (m *runYeetMockGit) LatestTag() (string, error) {
+	if m.latestTag == "" {
+		return "", fmt.Errorf("no version tag found")
+	}
+	return m.latestTag, nil
+}
+func (m *runYeetMockGit) LatestReleaseTag() (string, error) {
+	if m.releaseTag == "" {
+		return "", fmt.Errorf("no release tag found")
+	}
+	return m.releaseTag, nil
+}
+func (m *runYeetMockGit) TagDate(string) (string, error)         { return "", nil }
+func (m *runYeetMockGit) CreateTag(string, string) error         { return nil }
+func (m *runYeetMockGit) PushTag(string, string) (string, error) { return "", nil }
+func (m *runYeetMockGit) DiffRange(string) (string, error)       { return "", nil }
+func (m *runYeetMockGit) DiffStatRange(string) (string, error)   { return "", nil }
+func (m *runYeetMockGit) HasUpstream() bool                      { return true }
+func (m *runYeetMockGit) RepoRoot() (string, error)              { return "", nil }
+func (m *runYeetMockGit) ChangedFilesRange(string) ([]string, error) {
+	return nil, nil
+}
+func (m *runYeetMockGit) FileAuthors(string, []string) ([]git.Author, error) {
+	return nil, nil
+}
+func (m *runYeetMockGit) LocalBranches() ([]string, error)        { return nil, nil }
+func (m *runYeetMockGit) IsAncestor(string, string) bool          { return m.ancestor }
+func (m *runYeetMockGit) CommitCount(string, string) (int, error) { return 0, nil }
+func (m *runYeetMockGit) ResolveCommit(ref string) (string, error) {
+	if m.refs == nil {
+		return ref, nil
+	}
+	if sha, ok := m.refs[ref]; ok {
+		return sha, nil
+	}
+	return "", fmt.Errorf("unknown revision %s", ref)
+}
+func (m *runYeetMockGit) CommitMessage(string) (string, error)  { return "", nil }
+func (m *runYeetMockGit) CommitDiff(string) (string, error)     { return "", nil }
+func (m *runYeetMockGit) CommitStat(string) (string, error)     { return "", nil }
+func (m *runYeetMockGit) DiffCachedFrom(string) (string, error) { return "", nil }
+func (m *runYeetMockGit) DiffStatCachedFrom(string) (string, error) {
+	return m.diffStat, nil
+}
+func (m *runYeetMockGit) RemoteBranchesContaining(string) ([]string, error) {
+	return m.pushedTo, nil
+}
+func (m *runYeetMockGit) Amend(msg string) (string, error) {
+	m.commitCalled = true
+	m.commitMessage = msg
+	return m.commitOut, nil
+}
+func (m *runYeetMockGit) Reword(string, string) error              { return nil }
+func (m *runYeetMockGit) MergeBase(string, string) (string, error) { return "", nil }
+func (m *runYeetMockGit) UpdateRef(ref, sha string) error {
+	if m.updatedRefs == nil {
+		m.updatedRefs = map[string]string{}
+	}
+	m.updatedRefs[ref] = sha
+	return nil
+}
+func (m *runYeetMockGit) ResetSoft(ref string) error {
+	m.resets = append(m.resets, ref)
+	return nil
+}
 
 func TestFirstLine(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rasalas/yeet/internal/ai"
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/git"
+	"github.com/rasalas/yeet/internal/term"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	squashCmd.Flags().BoolVarP(&rewriteForce, "force", "f", false, "Squash even if the branch has been pushed")
+	rootCmd.AddCommand(squashCmd)
+}
+
+var squashCmd = &cobra.Command{
+	Use:   "squash [base]",
+	Short: "Squash the branch into one commit with a generated message",
+	Long: "Soft-reset the branch to its merge base with base (default: the default branch) and commit " +
+		"everything as one commit, with a message generated from the combined diff and the original " +
+		"commit subjects. The old branch tip is kept under refs/yeet/backup/<branch> to undo it.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSquash,
+
+	SilenceUsage: true,
+}
+
+func runSquash(cmd *cobra.Command, args []string) error {
+	branch, err := git.CurrentBranch()
+	if err != nil || branch == "" || branch == "HEAD" {
+		return fmt.Errorf("not on a branch")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+	base, err := squashBase(cfg, name, branch)
+	if err != nil {
+		return err
+	}
+	// Staged changes would end up in the squashed commit.
+	if git.HasStagedChanges() {
+		return fmt.Errorf("there are staged changes — commit or unstage them first")
+	}
+
+	head, err := git.ResolveCommit("HEAD")
+	if err != nil {
+		return err
+	}
+	mergeBase, err := git.MergeBase(base, head)
+	if err != nil {
+		return err
+	}
+	log, err := git.LogBetween(mergeBase, head)
+	if err != nil {
+		return fmt.Errorf("failed to read commits since %s: %s", base, log)
+	}
+	subjects := squashSubjects(log)
+	switch len(subjects) {
+	case 0:
+		fmt.Printf("\n  %sNo commits since %s — nothing to squash.%s\n\n", term.Dim, base, term.Reset)
+		return nil
+	case 1:
+		fmt.Printf("\n  %sOnly one commit since %s — use yeet reword HEAD to regenerate its message.%s\n\n", term.Dim, base, term.Reset)
+		return nil
+	}
+	pushedTo, err := checkRewritable(head, "squash")
+	if err != nil {
+		return err
+	}
+
+	stat, err := git.DiffStatRange(mergeBase)
+	if err != nil {
+		return fmt.Errorf("failed to get diff stat: %w", err)
+	}
+	fmt.Printf("\n  %sSquashing%s %d commits onto %s %s(%s)%s\n\n", term.Bold, term.Reset, len(subjects), base, term.Dim, mergeBase[:7], term.Reset)
+	for _, s := range subjects {
+		fmt.Printf("  %s· %s%s\n", term.Dim, s, term.Reset)
+	}
+	fmt.Println()
+	printStat(stat)
+
+	message, usage, streamed, _, err := generateMessage(func() (ai.CommitContext, error) {
+		diff, err := git.DiffRange(mergeBase)
+		if err != nil {
+			return ai.CommitContext{}, fmt.Errorf("failed to get diff: %w", err)
+		}
+		return ai.CommitContext{
+			Diff:     diff,
+			Branch:   branch,
+			Squashed: strings.Join(subjects, "\n"),
+		}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	message, _, ok, err := confirmMessage(message, streamed, "squash")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Printf("  %sCancelled.%s\n", term.Dim, term.Reset)
+		return nil
+	}
+
+	backup, err := squashCommits(branch, head, mergeBase, message)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %s✓%s Squashed %d commits: %s\n", term.Green, term.Reset, len(subjects), firstLine(message))
+	fmt.Printf("  %sUndo with: git reset --keep %s%s\n", term.Dim, backup, term.Reset)
+	printForcePushHint(pushedTo)
+	printPRUsage(usage)
+	return nil
+}
+
+// squashBase returns the ref to squash branch onto: name, or the default
+// branch when it is empty. The remote-tracking branch is fetched and
+// preferred, as a local branch that is behind its remote would fold commits
+// the branch already merged from upstream into the squash. In a fork, that
+// is the branch of the remote PRs are opened against.
+func squashBase(cfg config.Config, name, branch string) (string, error) {
+	remote, push := prRemotes(cfg)
+	if name == "" {
+		trunk, err := git.RemoteDefaultBranch(remote)
+		if err != nil {
+			if trunk, err = git.DefaultBranch(); err != nil {
+				return "", fmt.Errorf("failed to find the default branch — pass the base to squash onto")
+			}
+		}
+		name = trunk
+	}
+	if name == branch {
+		return "", fmt.Errorf("%s is the base branch — switch to the branch to squash", branch)
+	}
+	out, fetchErr := git.Fetch(remote, name)
+	base := name
+	if _, err := git.ResolveCommit(remote + "/" + name); err == nil {
+		base = remote + "/" + name
+	}
+	if fetchErr != nil {
+		fmt.Printf("  %s!%s Couldn't fetch %s from %s (%s) — squashing onto %s as it is locally, which may be out of date\n",
+			term.Yellow, term.Reset, name, remote, firstLine(out), base)
+	}
+	if remote != push && base != name {
+		fmt.Printf("  %sSquashing onto %s of the PR remote — pass %s/%s to squash onto your fork's branch%s\n",
+			term.Dim, base, push, name, term.Reset)
+	}
+	return base, nil
+}
+
+// squashCommits replaces the commits after mergeBase with one commit of
+// their combined changes. The old tip is kept as refs/yeet/backup/<branch>;
+// if the commit fails, the branch and the previous backup are restored.
+func squashCommits(branch, head, mergeBase, message string) (string, error) {
+	backup := "refs/yeet/backup/" + branch
+	previous, _ := git.ResolveCommit(backup)
+	restoreBackup := func() { git.UpdateRef(backup, previous) }
+
+	if err := git.UpdateRef(backup, head); err != nil {
+		return "", fmt.Errorf("failed to save backup %s: %w", backup, err)
+	}
+	if err := git.ResetSoft(mergeBase); err != nil {
+		restoreBackup()
+		return "", fmt.Errorf("reset failed: %w", err)
+	}
+	if out, err := git.Commit(message); err != nil {
+		// Put the branch back where it was; the index still holds its tree.
+		git.ResetSoft(head)
+		restoreBackup()
+		return "", fmt.Errorf("commit failed: %s", out)
+	}
+	return backup, nil
+}
+
+// squashSubjects returns the subjects of git log --oneline output, oldest
+// first.
+func squashSubjects(log string) []string {
+	var subjects []string
+	for _, line := range strings.Split(log, "\n") {
+		if _, subject, ok := strings.Cut(line, " "); ok {
+			subjects = append(subjects, subject)
+		}
+	}
+	slices.Reverse(subjects)
+	return subjects
+}
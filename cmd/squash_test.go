@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/git"
+)
+
+func TestSquashSubjects(t *testing.T) {
+	got := squashSubjects("c3 fix tests\nb2 wip\na1 feat: add squash")
+	want := []string{"feat: add squash", "wip", "fix tests"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("squashSubjects = %q, want %q", got, want)
+	}
+	if got := squashSubjects(""); got != nil {
+		t.Errorf("squashSubjects(\"\") = %q, want nil", got)
+	}
+}
+
+func TestSquashBase(t *testing.T) {
+	orig := git.Default
+	defer func() { git.Default = orig }()
+	cfg := config.DefaultConfig()
+
+	// The remote-tracking branch wins over a possibly stale local one.
+	git.Default = &runYeetMockGit{refs: map[string]string{"origin/main": "a1", "main": "00"}}
+	if base, err := squashBase(cfg, "", "feat"); err != nil || base != "origin/main" {
+		t.Errorf("squashBase = %q, %v, want origin/main", base, err)
+	}
+	if base, err := squashBase(cfg, "develop", "feat"); err != nil || base != "develop" {
+		t.Errorf("squashBase(develop) = %q, %v, want the local branch without a remote one", base, err)
+	}
+	// Offline, the stale remote-tracking branch is used with a warning.
+	git.Default = &runYeetMockGit{refs: map[string]string{"origin/main": "a1"}, fetchErr: errors.New("could not resolve host")}
+	if base, err := squashBase(cfg, "", "feat"); err != nil || base != "origin/main" {
+		t.Errorf("squashBase offline = %q, %v, want origin/main", base, err)
+	}
+	if _, err := squashBase(cfg, "", "main"); err == nil {
+		t.Error("squashBase on the base branch succeeded")
+	}
+}
+
+func TestSquashCommits(t *testing.T) {
+	orig := git.Default
+	defer func() { git.Default = orig }()
+
+	mock := &runYeetMockGit{refs: map[string]string{}}
+	git.Default = mock
+	backup, err := squashCommits("feat", "head1", "base0", "feat: squashed")
+	if err != nil || backup != "refs/yeet/backup/feat" {
+		t.Fatalf("squashCommits = %q, %v", backup, err)
+	}
+	if mock.updatedRefs[backup] != "head1" || !reflect.DeepEqual(mock.resets, []string{"base0"}) || mock.commitMessage != "feat: squashed" {
+		t.Errorf("refs %v, resets %v, message %q", mock.updatedRefs, mock.resets, mock.commitMessage)
+	}
+
+	// A failed commit (e.g. a rejecting hook) puts the branch and the
+	// previous backup back.
+	mock = &runYeetMockGit{refs: map[string]string{"refs/yeet/backup/feat": "old9"}, commitErr: errors.New("hook failed")}
+	git.Default = mock
+	if _, err := squashCommits("feat", "head1", "base0", "feat: squashed"); err == nil {
+		t.Fatal("squashCommits succeeded despite a failing commit")
+	}
+	if !reflect.DeepEqual(mock.resets, []string{"base0", "head1"}) {
+		t.Errorf("resets = %v, want the branch reset back to head1", mock.resets)
+	}
+	if got := mock.updatedRefs["refs/yeet/backup/feat"]; got != "old9" {
+		t.Errorf("backup = %q, want the previous backup old9 restored", got)
+	}
+}
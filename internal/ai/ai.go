@@ -10,4 +10,5 @@ type Usage struct {
 	Model        string
 	InputTokens  int
 	OutputTokens int
+	KeySlot      string // keyring slot that paid for the call, "" for other key sources
 }
@@ -1,7 +1,6 @@
 package ai
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,16 +10,19 @@ import (
 const anthropicVersion = "2023-06-01"
 
 type AnthropicProvider struct {
-	APIKey string
-	Model  string
+	APIKey  string
+	Model   string
+	BaseURL string
+	Options RequestOptions
 }
 
 type anthropicRequest struct {
-	Model     string             `json:"model"`
-	MaxTokens int                `json:"max_tokens"`
-	System    string             `json:"system"`
-	Messages  []anthropicMessage `json:"messages"`
-	Stream    bool               `json:"stream,omitempty"`
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	System      string             `json:"system"`
+	Messages    []anthropicMessage `json:"messages"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	Stream      bool               `json:"stream,omitempty"`
 }
 
 type anthropicMessage struct {
@@ -42,25 +44,44 @@ type anthropicResponse struct {
 }
 
 func (p *AnthropicProvider) headers() map[string]string {
-	return map[string]string{
+	return p.Options.headers(map[string]string{
 		"x-api-key":         p.APIKey,
 		"anthropic-version": anthropicVersion,
+	})
+}
+
+func (p *AnthropicProvider) messagesURL() string {
+	if p.BaseURL != "" {
+		return strings.TrimRight(p.BaseURL, "/") + "/messages"
 	}
+	return "https://api.anthropic.com/v1/messages"
 }
 
-func (p *AnthropicProvider) GenerateCommitMessage(ctx CommitContext) (string, Usage, error) {
-	body := anthropicRequest{
-		Model:     p.Model,
-		MaxTokens: ctx.EffectiveMaxTokens(),
-		System:    ctx.EffectivePrompt(),
-		Messages:  []anthropicMessage{{Role: "user", Content: ctx.BuildUserMessage()}},
+func (p *AnthropicProvider) request(ctx CommitContext, stream bool) anthropicRequest {
+	system := ctx.EffectivePrompt()
+	return anthropicRequest{
+		Model:       p.Model,
+		MaxTokens:   p.Options.maxTokens(ctx),
+		System:      system,
+		Messages:    []anthropicMessage{{Role: "user", Content: p.Options.userMessage(ctx, system)}},
+		Temperature: p.Options.Temperature,
+		Stream:      stream,
 	}
+}
 
-	reqCtx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+func (p *AnthropicProvider) GenerateCommitMessage(ctx CommitContext) (string, Usage, error) {
+	body := p.request(ctx, false)
+
+	reqCtx, cancel := p.Options.requestContext()
 	defer cancel()
 
+	client, err := p.Options.client()
+	if err != nil {
+		return "", Usage{}, err
+	}
+
 	var result anthropicResponse
-	if err := doRequest(reqCtx, "POST", "https://api.anthropic.com/v1/messages", body, p.headers(), &result); err != nil {
+	if err := doRequest(reqCtx, client, "POST", p.messagesURL(), body, p.headers(), &result); err != nil {
 		return "", Usage{}, err
 	}
 
@@ -71,7 +92,7 @@ func (p *AnthropicProvider) GenerateCommitMessage(ctx CommitContext) (string, Us
 		return "", Usage{}, fmt.Errorf("empty response from API")
 	}
 
-	usage := Usage{Model: p.Model}
+	usage := p.Options.usage(p.Model)
 	if result.Usage != nil {
 		usage.InputTokens = result.Usage.InputTokens
 		usage.OutputTokens = result.Usage.OutputTokens
@@ -81,15 +102,16 @@ func (p *AnthropicProvider) GenerateCommitMessage(ctx CommitContext) (string, Us
 }
 
 func (p *AnthropicProvider) GenerateCommitMessageStream(ctx CommitContext, onToken func(string)) (string, Usage, error) {
-	body := anthropicRequest{
-		Model:     p.Model,
-		MaxTokens: ctx.EffectiveMaxTokens(),
-		System:    ctx.EffectivePrompt(),
-		Messages:  []anthropicMessage{{Role: "user", Content: ctx.BuildUserMessage()}},
-		Stream:    true,
-	}
+	body := p.request(ctx, true)
+
+	streamCtx, cancel := p.Options.streamContext()
+	defer cancel()
 
-	resp, err := doStream(context.Background(), "https://api.anthropic.com/v1/messages", body, p.headers())
+	client, err := p.Options.client()
+	if err != nil {
+		return "", Usage{}, err
+	}
+	resp, err := doStream(streamCtx, client, p.messagesURL(), body, p.headers())
 	if err != nil {
 		return "", Usage{}, err
 	}
@@ -105,7 +127,7 @@ func (p *AnthropicProvider) GenerateCommitMessageStream(ctx CommitContext, onTok
 	}
 
 	var full strings.Builder
-	usage := Usage{Model: p.Model}
+	usage := p.Options.usage(p.Model)
 
 	if err := parseSSE(resp.Body, func(eventType, data string) {
 		switch eventType {
@@ -0,0 +1,90 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/keyring"
+)
+
+// KeyStatus classifies the outcome of a key check.
+type KeyStatus string
+
+const (
+	KeyValid    KeyStatus = "valid"
+	KeyInvalid  KeyStatus = "invalid key"
+	KeyNoAccess KeyStatus = "no model access"
+	KeyNetwork  KeyStatus = "network error"
+	KeyMissing  KeyStatus = "no key"
+	KeyError    KeyStatus = "error"
+)
+
+// KeyCheck is the result of CheckKey. Detail explains non-valid results.
+type KeyCheck struct {
+	Status KeyStatus
+	Detail string
+}
+
+// CheckKey verifies the provider's key with a cheap authenticated call: it
+// lists the provider's models and looks for the configured model.
+func CheckKey(ctx context.Context, provider string, cfg config.Config) KeyCheck {
+	rp, ok := cfg.ResolveProviderFull(provider)
+	if !ok {
+		return KeyCheck{Status: KeyError, Detail: "unknown provider " + provider}
+	}
+	var key string
+	if rp.Protocol != config.ProtocolOllama {
+		var err error
+		if key, err = keyring.Get(rp.Name, rp.KeyLookup()); err != nil {
+			return KeyCheck{Status: KeyMissing}
+		}
+	}
+	models, err := listModels(ctx, rp, key)
+	return classifyCheck(rp, models, err)
+}
+
+// classifyCheck maps a model list response to a KeyCheck.
+func classifyCheck(rp config.ResolvedProvider, models []string, err error) KeyCheck {
+	var status *StatusError
+	var urlErr *url.Error
+	var netErr net.Error
+	switch {
+	case errors.As(err, &status):
+		switch status.Code {
+		case 401:
+			return KeyCheck{Status: KeyInvalid, Detail: "rejected by the API (401)"}
+		case 403:
+			return KeyCheck{Status: KeyInvalid, Detail: "key lacks permission (403)"}
+		}
+		return KeyCheck{Status: KeyError, Detail: status.Error()}
+	case errors.As(err, &urlErr), errors.As(err, &netErr):
+		return KeyCheck{Status: KeyNetwork, Detail: err.Error()}
+	case err != nil:
+		return KeyCheck{Status: KeyError, Detail: err.Error()}
+	}
+
+	if rp.Model == "" || hasModel(models, rp.Model) {
+		return KeyCheck{Status: KeyValid}
+	}
+	detail := fmt.Sprintf("%s is not in the provider's model list", rp.Model)
+	if rp.Protocol == config.ProtocolOllama {
+		detail = fmt.Sprintf("%s is not pulled — run: ollama pull %s", rp.Model, rp.Model)
+	}
+	return KeyCheck{Status: KeyNoAccess, Detail: detail}
+}
+
+// hasModel reports whether models contains model. Listings may prefix IDs
+// (e.g. Gemini's "models/") or tag them (Ollama's ":latest").
+func hasModel(models []string, model string) bool {
+	for _, m := range models {
+		if m == model || strings.TrimPrefix(m, "models/") == model || strings.TrimSuffix(model, ":latest") == m {
+			return true
+		}
+	}
+	return false
+}
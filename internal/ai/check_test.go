@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/keyring"
+)
+
+// isolateKeys keeps CheckKey away from the developer's keyring, key state
+// and other tools' config files.
+func isolateKeys(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	t.Setenv("LLM_USER_PATH", "")
+	t.Setenv("CONTINUE_GLOBAL_DIR", "")
+	t.Setenv("YEET_KEYRING_BACKEND", keyring.BackendFile)
+	t.Setenv("YEET_KEYRING_PASSPHRASE", "")
+}
+
+func TestCheckKey(t *testing.T) {
+	isolateKeys(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data": [{"id": "small"}, {"id": "models/large"}]}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		key, model string
+		want       KeyStatus
+	}{
+		{"good", "small", KeyValid},
+		{"good", "large", KeyValid},
+		{"good", "huge", KeyNoAccess},
+		{"bad", "small", KeyInvalid},
+		{"", "small", KeyMissing},
+	}
+	for _, tt := range tests {
+		t.Setenv("CHECK_TEST_KEY", tt.key)
+		cfg := config.DefaultConfig()
+		cfg.Providers = map[string]config.ProviderConfig{
+			"checktest": {URL: server.URL, Env: "CHECK_TEST_KEY", Model: tt.model},
+		}
+		if got := CheckKey(context.Background(), "checktest", cfg); got.Status != tt.want {
+			t.Errorf("key %q, model %q: got %+v, want %s", tt.key, tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestCheckKeyNetworkError(t *testing.T) {
+	isolateKeys(t)
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	t.Setenv("CHECK_TEST_KEY", "good")
+	cfg := config.DefaultConfig()
+	cfg.Providers = map[string]config.ProviderConfig{
+		"checktest": {URL: url, Env: "CHECK_TEST_KEY", Model: "small"},
+	}
+	if got := CheckKey(context.Background(), "checktest", cfg); got.Status != KeyNetwork {
+		t.Errorf("got %+v, want %s", got, KeyNetwork)
+	}
+}
@@ -11,6 +11,8 @@ type CommitContext struct {
 	Branch        string
 	RecentCommits string
 	Status        string
+	// Squashed lists the subjects of commits being squashed into one.
+	Squashed string
 
 	// SystemPrompt overrides the default commit-message prompt when set.
 	SystemPrompt string
@@ -50,8 +52,14 @@ func (c CommitContext) BuildUserMessage() string {
 		fmt.Fprintf(&b, "Recent commits:\n%s\n\n", c.RecentCommits)
 	}
 
-	b.WriteString("Diff:\n")
-	b.WriteString(truncateDiff(c.Diff))
+	if c.Squashed != "" {
+		fmt.Fprintf(&b, "Commits being squashed into this one:\n%s\n\n", c.Squashed)
+	}
+
+	if c.Diff != "" {
+		b.WriteString("Diff:\n")
+		b.WriteString(truncateDiff(c.Diff))
+	}
 
 	return b.String()
 }
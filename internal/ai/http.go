@@ -14,13 +14,15 @@ import (
 // aiClient is the shared HTTP client for AI provider requests.
 // Transport-level timeouts protect against unreachable servers.
 // No overall Client.Timeout — streaming responses can take as long as needed.
+// Providers with transport settings get their own client (see Transport).
 var aiClient = &http.Client{
 	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
-			Timeout: 10 * time.Second,
+			Timeout: defaultDialTimeout,
 		}).DialContext,
 		TLSHandshakeTimeout:   10 * time.Second,
-		ResponseHeaderTimeout: 15 * time.Second,
+		ResponseHeaderTimeout: defaultHeaderTimeout,
 	},
 }
 
@@ -30,7 +32,8 @@ const requestTimeout = 60 * time.Second
 
 // doRequest sends a JSON request with a context timeout and returns the parsed response.
 // It handles marshalling, sending, reading, and unmarshalling in one call.
-func doRequest(ctx context.Context, method, url string, body any, headers map[string]string, result any) error {
+// A nil client uses aiClient.
+func doRequest(ctx context.Context, client *http.Client, method, url string, body any, headers map[string]string, result any) error {
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
@@ -45,7 +48,10 @@ func doRequest(ctx context.Context, method, url string, body any, headers map[st
 		req.Header.Set(k, v)
 	}
 
-	resp, err := aiClient.Do(req)
+	if client == nil {
+		client = aiClient
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("API request failed: %w", err)
 	}
@@ -64,8 +70,8 @@ func doRequest(ctx context.Context, method, url string, body any, headers map[st
 }
 
 // doStream sends a JSON request and returns the open response for streaming.
-// The caller is responsible for closing resp.Body.
-func doStream(ctx context.Context, url string, body any, headers map[string]string) (*http.Response, error) {
+// The caller is responsible for closing resp.Body. A nil client uses aiClient.
+func doStream(ctx context.Context, client *http.Client, url string, body any, headers map[string]string) (*http.Response, error) {
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -80,7 +86,10 @@ func doStream(ctx context.Context, url string, body any, headers map[string]stri
 		req.Header.Set(k, v)
 	}
 
-	resp, err := aiClient.Do(req)
+	if client == nil {
+		client = aiClient
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("API request failed: %w", err)
 	}
@@ -34,6 +34,7 @@ func TestDoRequest(t *testing.T) {
 		var result response
 		err := doRequest(
 			context.Background(),
+			nil,
 			"POST",
 			server.URL,
 			map[string]string{"key": "value"},
@@ -60,7 +61,7 @@ func TestDoRequest(t *testing.T) {
 		defer server.Close()
 
 		var result map[string]any
-		err := doRequest(context.Background(), "POST", server.URL, nil, nil, &result)
+		err := doRequest(context.Background(), nil, "POST", server.URL, nil, nil, &result)
 		if err == nil {
 			t.Error("expected error for invalid JSON response")
 		}
@@ -73,7 +74,7 @@ func TestDoRequest(t *testing.T) {
 		defer server.Close()
 
 		var result map[string]any
-		err := doRequest(context.Background(), "POST", server.URL, nil, nil, &result)
+		err := doRequest(context.Background(), nil, "POST", server.URL, nil, nil, &result)
 		if err == nil {
 			t.Error("expected error for malformed JSON")
 		}
@@ -94,6 +95,7 @@ func TestDoStream(t *testing.T) {
 
 		resp, err := doStream(
 			context.Background(),
+			nil,
 			server.URL,
 			map[string]string{"key": "value"},
 			map[string]string{"X-Custom": "test"},
@@ -114,7 +116,7 @@ func TestDoStream(t *testing.T) {
 		}))
 		defer server.Close()
 
-		resp, err := doStream(context.Background(), server.URL, nil, nil)
+		resp, err := doStream(context.Background(), nil, server.URL, nil, nil)
 		if err != nil {
 			t.Fatalf("doStream with nil headers failed: %v", err)
 		}
@@ -14,7 +14,25 @@ import (
 	"github.com/rasalas/yeet/internal/keyring"
 )
 
-var modelsClient = &http.Client{Timeout: 5 * time.Second}
+const modelsTimeout = 5 * time.Second
+
+var modelsClient = &http.Client{Timeout: modelsTimeout}
+
+// modelsClientFor returns the client for listing a provider's models: the
+// provider's transport settings with the short models timeout.
+func modelsClientFor(rp config.ResolvedProvider) (*http.Client, error) {
+	t := optionsFor(rp).Transport
+	if t == (Transport{}) {
+		return modelsClient, nil
+	}
+	c, err := t.client()
+	if err != nil {
+		return nil, err
+	}
+	withTimeout := *c
+	withTimeout.Timeout = modelsTimeout
+	return &withTimeout, nil
+}
 
 // FetchModels queries the provider's API for available models.
 // Returns a sorted list of model IDs, or an error if the request fails.
@@ -24,22 +42,43 @@ func FetchModels(ctx context.Context, provider string, cfg config.Config) ([]str
 		return nil, fmt.Errorf("unknown provider: %s", provider)
 	}
 
+	var key string
+	if rp.Protocol != config.ProtocolOllama {
+		var err error
+		if key, err = keyring.Get(rp.Name, rp.KeyLookup()); err != nil {
+			return nil, fmt.Errorf("no API key for %s", rp.Name)
+		}
+	}
+	return listModels(ctx, rp, key)
+}
+
+// StatusError is returned when a provider API answers with a non-200 status.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API returned status %d", e.Code)
+}
+
+// listModels lists the provider's models using key.
+func listModels(ctx context.Context, rp config.ResolvedProvider, key string) ([]string, error) {
+	client, err := modelsClientFor(rp)
+	if err != nil {
+		return nil, err
+	}
+
 	switch rp.Protocol {
 	case config.ProtocolAnthropic:
-		return fetchAnthropic(ctx, rp)
+		return fetchAnthropic(ctx, client, rp, key)
 	case config.ProtocolOllama:
-		return fetchOllama(ctx, rp)
+		return fetchOllama(ctx, client, rp)
 	default:
-		return fetchOpenAICompatible(ctx, rp)
+		return fetchOpenAICompatible(ctx, client, rp, key)
 	}
 }
 
-func fetchAnthropic(ctx context.Context, rp config.ResolvedProvider) ([]string, error) {
-	key, err := keyring.GetWithEnv(rp.Name, rp.Env)
-	if err != nil {
-		return nil, fmt.Errorf("no API key for %s", rp.Name)
-	}
-
+func fetchAnthropic(ctx context.Context, client *http.Client, rp config.ResolvedProvider, key string) ([]string, error) {
 	url := strings.TrimRight(rp.URL, "/") + "/models?limit=100"
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -47,24 +86,30 @@ func fetchAnthropic(ctx context.Context, rp config.ResolvedProvider) ([]string,
 	}
 	req.Header.Set("x-api-key", key)
 	req.Header.Set("anthropic-version", anthropicVersion)
+	setHeaders(req, rp.Headers)
 
-	return doOpenAIModelList(req)
+	return doOpenAIModelList(client, req)
 }
 
-func fetchOllama(ctx context.Context, rp config.ResolvedProvider) ([]string, error) {
+func fetchOllama(ctx context.Context, client *http.Client, rp config.ResolvedProvider) ([]string, error) {
 	url := strings.TrimRight(rp.URL, "/") + "/api/tags"
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
+	setHeaders(req, rp.Headers)
 
-	resp, err := modelsClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch models: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Code: resp.StatusCode}
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -89,32 +134,35 @@ func fetchOllama(ctx context.Context, rp config.ResolvedProvider) ([]string, err
 	return models, nil
 }
 
-func fetchOpenAICompatible(ctx context.Context, rp config.ResolvedProvider) ([]string, error) {
-	key, err := keyring.GetWithEnv(rp.Name, rp.Env)
-	if err != nil {
-		return nil, fmt.Errorf("no API key for %s", rp.Name)
-	}
-
+func fetchOpenAICompatible(ctx context.Context, client *http.Client, rp config.ResolvedProvider, key string) ([]string, error) {
 	url := strings.TrimRight(rp.URL, "/") + "/models"
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+key)
+	setHeaders(req, rp.Headers)
 
-	return doOpenAIModelList(req)
+	return doOpenAIModelList(client, req)
+}
+
+// setHeaders applies the provider's configured extra headers.
+func setHeaders(req *http.Request, headers map[string]string) {
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 }
 
 // doOpenAIModelList executes a request and parses the standard {"data": [{"id": "..."}]} response.
-func doOpenAIModelList(req *http.Request) ([]string, error) {
-	resp, err := modelsClient.Do(req)
+func doOpenAIModelList(client *http.Client, req *http.Request) ([]string, error) {
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch models: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, &StatusError{Code: resp.StatusCode}
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -2,21 +2,28 @@ package ai
 
 import (
 	"bufio"
-	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 )
 
 type OllamaProvider struct {
-	URL   string
-	Model string
+	URL     string
+	Model   string
+	Options RequestOptions
 }
 
 type ollamaRequest struct {
 	Model    string          `json:"model"`
 	Messages []ollamaMessage `json:"messages"`
 	Stream   bool            `json:"stream"`
+	Options  *ollamaOptions  `json:"options,omitempty"`
+}
+
+type ollamaOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+	NumCtx      int      `json:"num_ctx,omitempty"`
 }
 
 type ollamaMessage struct {
@@ -37,21 +44,35 @@ func (p *OllamaProvider) apiURL() string {
 	return strings.TrimRight(p.URL, "/") + "/api/chat"
 }
 
-func (p *OllamaProvider) GenerateCommitMessage(ctx CommitContext) (string, Usage, error) {
+func (p *OllamaProvider) request(ctx CommitContext, stream bool) ollamaRequest {
+	system := ctx.EffectivePrompt()
 	body := ollamaRequest{
 		Model: p.Model,
 		Messages: []ollamaMessage{
-			{Role: "system", Content: ctx.EffectivePrompt()},
-			{Role: "user", Content: ctx.BuildUserMessage()},
+			{Role: "system", Content: system},
+			{Role: "user", Content: p.Options.userMessage(ctx, system)},
 		},
-		Stream: false,
+		Stream: stream,
 	}
+	if o := p.Options; o.Temperature != nil || o.MaxTokens > 0 || o.ContextWindow > 0 {
+		body.Options = &ollamaOptions{Temperature: o.Temperature, NumPredict: o.MaxTokens, NumCtx: o.ContextWindow}
+	}
+	return body
+}
 
-	reqCtx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+func (p *OllamaProvider) GenerateCommitMessage(ctx CommitContext) (string, Usage, error) {
+	body := p.request(ctx, false)
+
+	reqCtx, cancel := p.Options.requestContext()
 	defer cancel()
 
+	client, err := p.Options.client()
+	if err != nil {
+		return "", Usage{}, err
+	}
+
 	var result ollamaResponse
-	if err := doRequest(reqCtx, "POST", p.apiURL(), body, nil, &result); err != nil {
+	if err := doRequest(reqCtx, client, "POST", p.apiURL(), body, p.Options.headers(nil), &result); err != nil {
 		if strings.Contains(err.Error(), "API request failed") {
 			return "", Usage{}, fmt.Errorf("API request failed (is Ollama running at %s?): %w", p.URL, err)
 		}
@@ -62,33 +83,31 @@ func (p *OllamaProvider) GenerateCommitMessage(ctx CommitContext) (string, Usage
 		return "", Usage{}, fmt.Errorf("Ollama error: %s", result.Error)
 	}
 
-	usage := Usage{
-		Model:        p.Model,
-		InputTokens:  result.PromptEvalCount,
-		OutputTokens: result.EvalCount,
-	}
+	usage := p.Options.usage(p.Model)
+	usage.InputTokens = result.PromptEvalCount
+	usage.OutputTokens = result.EvalCount
 
 	return strings.TrimSpace(result.Message.Content), usage, nil
 }
 
 func (p *OllamaProvider) GenerateCommitMessageStream(ctx CommitContext, onToken func(string)) (string, Usage, error) {
-	body := ollamaRequest{
-		Model: p.Model,
-		Messages: []ollamaMessage{
-			{Role: "system", Content: ctx.EffectivePrompt()},
-			{Role: "user", Content: ctx.BuildUserMessage()},
-		},
-		Stream: true,
-	}
+	body := p.request(ctx, true)
 
-	resp, err := doStream(context.Background(), p.apiURL(), body, nil)
+	streamCtx, cancel := p.Options.streamContext()
+	defer cancel()
+
+	client, err := p.Options.client()
+	if err != nil {
+		return "", Usage{}, err
+	}
+	resp, err := doStream(streamCtx, client, p.apiURL(), body, p.Options.headers(nil))
 	if err != nil {
 		return "", Usage{}, fmt.Errorf("API request failed (is Ollama running at %s?): %w", p.URL, err)
 	}
 	defer resp.Body.Close()
 
 	var full strings.Builder
-	usage := Usage{Model: p.Model}
+	usage := p.Options.usage(p.Model)
 
 	scanner := bufio.NewScanner(resp.Body)
 	for scanner.Scan() {
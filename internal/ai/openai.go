@@ -1,7 +1,6 @@
 package ai
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,11 +11,14 @@ type OpenAIProvider struct {
 	APIKey  string
 	Model   string
 	BaseURL string
+	Options RequestOptions
 }
 
 type openaiRequest struct {
 	Model         string            `json:"model"`
 	Messages      []openaiMessage   `json:"messages"`
+	MaxTokens     int               `json:"max_tokens,omitempty"`
+	Temperature   *float64          `json:"temperature,omitempty"`
 	Stream        bool              `json:"stream,omitempty"`
 	StreamOptions *openaiStreamOpts `json:"stream_options,omitempty"`
 }
@@ -53,25 +55,47 @@ func (p *OpenAIProvider) baseURL() string {
 }
 
 func (p *OpenAIProvider) headers() map[string]string {
-	return map[string]string{
-		"Authorization": "Bearer " + p.APIKey,
+	base := map[string]string{}
+	if p.APIKey != "" {
+		base["Authorization"] = "Bearer " + p.APIKey
 	}
+	return p.Options.headers(base)
 }
 
-func (p *OpenAIProvider) GenerateCommitMessage(ctx CommitContext) (string, Usage, error) {
+func (p *OpenAIProvider) request(ctx CommitContext, stream bool) openaiRequest {
+	system := ctx.EffectivePrompt()
 	body := openaiRequest{
 		Model: p.Model,
 		Messages: []openaiMessage{
-			{Role: "system", Content: ctx.EffectivePrompt()},
-			{Role: "user", Content: ctx.BuildUserMessage()},
+			{Role: "system", Content: system},
+			{Role: "user", Content: p.Options.userMessage(ctx, system)},
 		},
+		Temperature: p.Options.Temperature,
+	}
+	// Only send max_tokens when configured — reasoning models reject it otherwise.
+	if p.Options.MaxTokens > 0 {
+		body.MaxTokens = p.Options.maxTokens(ctx)
+	}
+	if stream {
+		body.Stream = true
+		body.StreamOptions = &openaiStreamOpts{IncludeUsage: true}
 	}
+	return body
+}
+
+func (p *OpenAIProvider) GenerateCommitMessage(ctx CommitContext) (string, Usage, error) {
+	body := p.request(ctx, false)
 
-	reqCtx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	reqCtx, cancel := p.Options.requestContext()
 	defer cancel()
 
+	client, err := p.Options.client()
+	if err != nil {
+		return "", Usage{}, err
+	}
+
 	var result openaiResponse
-	if err := doRequest(reqCtx, "POST", p.baseURL()+"/chat/completions", body, p.headers(), &result); err != nil {
+	if err := doRequest(reqCtx, client, "POST", p.baseURL()+"/chat/completions", body, p.headers(), &result); err != nil {
 		return "", Usage{}, err
 	}
 
@@ -82,7 +106,7 @@ func (p *OpenAIProvider) GenerateCommitMessage(ctx CommitContext) (string, Usage
 		return "", Usage{}, fmt.Errorf("empty response from API")
 	}
 
-	usage := Usage{Model: p.Model}
+	usage := p.Options.usage(p.Model)
 	if result.Usage != nil {
 		usage.InputTokens = result.Usage.PromptTokens
 		usage.OutputTokens = result.Usage.CompletionTokens
@@ -92,17 +116,16 @@ func (p *OpenAIProvider) GenerateCommitMessage(ctx CommitContext) (string, Usage
 }
 
 func (p *OpenAIProvider) GenerateCommitMessageStream(ctx CommitContext, onToken func(string)) (string, Usage, error) {
-	body := openaiRequest{
-		Model: p.Model,
-		Messages: []openaiMessage{
-			{Role: "system", Content: ctx.EffectivePrompt()},
-			{Role: "user", Content: ctx.BuildUserMessage()},
-		},
-		Stream:        true,
-		StreamOptions: &openaiStreamOpts{IncludeUsage: true},
-	}
+	body := p.request(ctx, true)
 
-	resp, err := doStream(context.Background(), p.baseURL()+"/chat/completions", body, p.headers())
+	streamCtx, cancel := p.Options.streamContext()
+	defer cancel()
+
+	client, err := p.Options.client()
+	if err != nil {
+		return "", Usage{}, err
+	}
+	resp, err := doStream(streamCtx, client, p.baseURL()+"/chat/completions", body, p.headers())
 	if err != nil {
 		return "", Usage{}, err
 	}
@@ -118,7 +141,7 @@ func (p *OpenAIProvider) GenerateCommitMessageStream(ctx CommitContext, onToken
 	}
 
 	var full strings.Builder
-	usage := Usage{Model: p.Model}
+	usage := p.Options.usage(p.Model)
 
 	if err := parseSSE(resp.Body, func(eventType, data string) {
 		if data == "[DONE]" {
@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/rasalas/yeet/internal/config"
+)
+
+// RequestOptions carries per-provider request settings from [providers.<name>].
+// Zero values keep each provider's defaults.
+type RequestOptions struct {
+	Headers       map[string]string
+	Temperature   *float64
+	MaxTokens     int
+	Timeout       time.Duration
+	ContextWindow int
+	Transport     Transport
+	KeySlot       string
+}
+
+// optionsFor extracts the request options of a resolved provider.
+func optionsFor(rp config.ResolvedProvider) RequestOptions {
+	return RequestOptions{
+		Headers:       rp.Headers,
+		Temperature:   rp.Temperature,
+		MaxTokens:     rp.MaxTokens,
+		Timeout:       rp.Timeout,
+		ContextWindow: rp.ContextWindow,
+		KeySlot:       rp.KeySlot,
+		Transport: Transport{
+			Proxy:         rp.Proxy,
+			CAFile:        rp.CAFile,
+			ClientCert:    rp.ClientCert,
+			ClientKey:     rp.ClientKey,
+			DialTimeout:   rp.DialTimeout,
+			HeaderTimeout: rp.HeaderTimeout,
+		},
+	}
+}
+
+// usage starts the Usage of a request to model.
+func (o RequestOptions) usage(model string) Usage {
+	return Usage{Model: model, KeySlot: o.KeySlot}
+}
+
+// headers returns base with the configured extra headers layered on top.
+func (o RequestOptions) headers(base map[string]string) map[string]string {
+	out := make(map[string]string, len(base)+len(o.Headers))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range o.Headers {
+		out[k] = v
+	}
+	return out
+}
+
+// maxTokens returns the caller's limit, else the configured one, else 256.
+func (o RequestOptions) maxTokens(ctx CommitContext) int {
+	if ctx.MaxTokens == 0 && o.MaxTokens > 0 {
+		return o.MaxTokens
+	}
+	return ctx.EffectiveMaxTokens()
+}
+
+// requestContext bounds a non-streaming request by the configured timeout,
+// falling back to requestTimeout.
+func (o RequestOptions) requestContext() (context.Context, context.CancelFunc) {
+	timeout := requestTimeout
+	if o.Timeout > 0 {
+		timeout = o.Timeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// streamContext bounds a streaming request only when a timeout is configured.
+func (o RequestOptions) streamContext() (context.Context, context.CancelFunc) {
+	if o.Timeout > 0 {
+		return context.WithTimeout(context.Background(), o.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// userMessage builds the user message, trimming the diff so it fits the
+// configured context window next to system (estimated at ~4 characters per token).
+func (o RequestOptions) userMessage(ctx CommitContext, system string) string {
+	msg := ctx.BuildUserMessage()
+	if o.ContextWindow <= 0 {
+		return msg
+	}
+	budget := (o.ContextWindow-o.maxTokens(ctx))*4 - len(system)
+	if budget <= 0 || len(msg) <= budget {
+		return msg
+	}
+	over := len(msg) - budget
+	if over >= len(ctx.Diff) {
+		return msg
+	}
+	trimmed := ctx
+	trimmed.Diff = ctx.Diff[:len(ctx.Diff)-over] + "\n... (diff truncated to fit context window)"
+	return trimmed.BuildUserMessage()
+}
+
+// client returns the HTTP client for the configured transport settings.
+func (o RequestOptions) client() (*http.Client, error) {
+	return o.Transport.client()
+}
@@ -0,0 +1,63 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequestOptionsHeaders(t *testing.T) {
+	o := RequestOptions{Headers: map[string]string{"X-Team": "core", "Authorization": "Bearer proxy"}}
+	got := o.headers(map[string]string{"Authorization": "Bearer key", "anthropic-version": "v"})
+	if got["X-Team"] != "core" || got["Authorization"] != "Bearer proxy" || got["anthropic-version"] != "v" {
+		t.Errorf("headers() = %v", got)
+	}
+}
+
+func TestRequestOptionsMaxTokens(t *testing.T) {
+	tests := []struct {
+		configured, caller, want int
+	}{
+		{0, 0, 256},
+		{512, 0, 512},
+		{512, 4096, 4096},
+	}
+	for _, tt := range tests {
+		o := RequestOptions{MaxTokens: tt.configured}
+		if got := o.maxTokens(CommitContext{MaxTokens: tt.caller}); got != tt.want {
+			t.Errorf("maxTokens(config %d, caller %d) = %d, want %d", tt.configured, tt.caller, got, tt.want)
+		}
+	}
+}
+
+func TestRequestOptionsUserMessage(t *testing.T) {
+	ctx := CommitContext{Diff: strings.Repeat("+line\n", 2000)}
+	full := RequestOptions{}.userMessage(ctx, "system")
+	if strings.Contains(full, "truncated") {
+		t.Fatal("message truncated without a context window")
+	}
+
+	o := RequestOptions{ContextWindow: 1000, MaxTokens: 200}
+	got := o.userMessage(ctx, "system")
+	if len(got) > (1000-200)*4+100 {
+		t.Errorf("message is %d chars, want it to fit the context window", len(got))
+	}
+	if !strings.Contains(got, "truncated to fit context window") {
+		t.Error("missing truncation marker")
+	}
+}
+
+func TestOpenAIRequestOptions(t *testing.T) {
+	temp := 0.3
+	ctx := CommitContext{SystemPrompt: "sys", Diff: "+x"}
+
+	p := &OpenAIProvider{Model: "m"}
+	if body := p.request(ctx, false); body.MaxTokens != 0 || body.Temperature != nil {
+		t.Errorf("unconfigured request = %+v, want no max_tokens/temperature", body)
+	}
+
+	p.Options = RequestOptions{MaxTokens: 300, Temperature: &temp}
+	body := p.request(ctx, true)
+	if body.MaxTokens != 300 || body.Temperature == nil || *body.Temperature != 0.3 || !body.Stream {
+		t.Errorf("configured request = %+v", body)
+	}
+}
@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -35,6 +36,61 @@ Rules:
 - Do NOT include a test plan or checklist — only the summary
 - Return ONLY the title and body, nothing else — no quotes, no explanation`
 
+// prTemplatePrompt is used instead of PRPrompt when the repository has a
+// PR template; %s is replaced by the template.
+const prTemplatePrompt = `You are a pull request description generator. Given branch commits and a diff, generate a PR title and a markdown body that fills in the repository's pull request template.
+
+Rules:
+- First line: a concise PR title (under 72 characters, no prefix like "PR:" or "feat:")
+- Second line: empty
+- Remaining lines: the template below, filled in
+- Keep the template's headings and their order; do not add new sections
+- Replace placeholders and HTML comments with content describing WHAT changed and WHY
+- Leave checkboxes unchecked unless the diff clearly satisfies them
+- Write "N/A" under sections that do not apply
+- Match the language and style of the commit messages when provided
+- Return ONLY the title and body, nothing else — no quotes, no explanation
+
+Template:
+%s`
+
+// PRPromptFor returns the PR system prompt, filling in the repository's PR
+// template when there is one.
+func PRPromptFor(template string) string {
+	template = strings.TrimSpace(template)
+	if template == "" {
+		return PRPrompt
+	}
+	return fmt.Sprintf(prTemplatePrompt, template)
+}
+
+// ReviewPrompt is the system prompt for yeet review.
+const ReviewPrompt = `You are a senior code reviewer. Given a git diff, point out real problems in the changed code.
+
+Rules:
+- Focus on bugs, security issues, data loss, race conditions, error handling and clear performance problems
+- Mention style or naming only when it hurts readability; skip anything a formatter or linter would catch
+- Only comment on added or changed lines (starting with "+")
+- Diff lines are prefixed with their line number in the new file; use it as "line"
+- Severity is "high" (must fix before merging), "medium" (should fix) or "low" (suggestion)
+- Keep each message to one or two sentences: the problem and, if obvious, the fix
+- Do not praise the code or summarize the change
+- Return ONLY a JSON array, nothing else — no code fence, no explanation:
+  [{"file": "path/to/file.go", "line": 42, "severity": "high", "message": "..."}]
+- Return [] when there is nothing worth pointing out`
+
+// ChangelogPrompt is the system prompt for yeet changelog.
+const ChangelogPrompt = `You are a release notes writer. Given the commits of a release grouped under Keep a Changelog headings, write the notes for users of the project.
+
+Rules:
+- Keep the "### Added", "### Changed", "### Deprecated", "### Removed", "### Fixed" and "### Security" headings; drop sections that end up empty
+- One "- " bullet per user-visible change, in plain language: what users get, not how it was implemented
+- Merge commits that describe the same change into one bullet; leave out purely internal changes
+- Keep PR references like (#12) at the end of the bullet they belong to
+- Keep a "**Breaking:**" prefix and say what users need to do
+- Move an entry to Deprecated or Removed when it clearly deprecates or removes something
+- Return ONLY the Markdown sections, no version heading and no introduction`
+
 const maxDiffLines = 8000
 
 // PromptPath returns the path to the user's prompt file.
@@ -67,6 +123,23 @@ func LoadPrompt() string {
 	return prompt
 }
 
+// LoadPromptFile reads a prompt from an explicit path (e.g. a profile's prompt).
+// Falls back to LoadPrompt when path is empty or unreadable.
+func LoadPromptFile(path string) string {
+	if path == "" {
+		return LoadPrompt()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LoadPrompt()
+	}
+	prompt := strings.TrimSpace(string(data))
+	if prompt == "" {
+		return LoadPrompt()
+	}
+	return prompt
+}
+
 // WritePrompt writes the prompt content to the prompt file.
 func WritePrompt(content string) error {
 	path, err := PromptPath()
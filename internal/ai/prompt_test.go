@@ -31,6 +31,18 @@ func TestBuildUserMessage(t *testing.T) {
 		}
 	})
 
+	t.Run("squashed commits", func(t *testing.T) {
+		ctx := CommitContext{
+			Diff:     "some diff",
+			Squashed: "wip\nfix tests",
+		}
+		msg := ctx.BuildUserMessage()
+
+		if !strings.Contains(msg, "Commits being squashed into this one:\nwip\nfix tests\n\nDiff:") {
+			t.Errorf("missing squashed commits before the diff:\n%s", msg)
+		}
+	})
+
 	t.Run("empty optional fields", func(t *testing.T) {
 		ctx := CommitContext{
 			Diff: "some diff",
@@ -46,6 +58,9 @@ func TestBuildUserMessage(t *testing.T) {
 		if strings.Contains(msg, "Recent commits:") {
 			t.Error("should not contain recent commits when empty")
 		}
+		if strings.Contains(msg, "Commits being squashed") {
+			t.Error("should not contain squashed commits when empty")
+		}
 		if !strings.Contains(msg, "Diff:\nsome diff") {
 			t.Error("missing diff")
 		}
@@ -215,3 +230,16 @@ func TestEffectiveMaxTokens(t *testing.T) {
 		}
 	})
 }
+
+func TestPRPromptFor(t *testing.T) {
+	if got := PRPromptFor("  \n"); got != PRPrompt {
+		t.Error("PRPromptFor without a template should return PRPrompt")
+	}
+	got := PRPromptFor("## What\n<!-- describe -->\n\n## 100% tested?\n")
+	if !strings.HasSuffix(got, "## What\n<!-- describe -->\n\n## 100% tested?") {
+		t.Errorf("PRPromptFor did not append the template:\n%s", got)
+	}
+	if !strings.Contains(got, "N/A") {
+		t.Error("PRPromptFor should explain how to handle sections that don't apply")
+	}
+}
@@ -16,32 +16,39 @@ func NewProvider(cfg config.Config) (Provider, error) {
 
 	rp, ok := cfg.ResolveProviderFull(cfg.Provider)
 	if !ok {
-		return nil, fmt.Errorf("unknown provider: %s — add it to [custom.%s] in config.toml", cfg.Provider, cfg.Provider)
+		return nil, fmt.Errorf("unknown provider: %s — add it to [providers.%s] in config.toml", cfg.Provider, cfg.Provider)
 	}
 
 	return buildProvider(rp)
 }
 
 func buildProvider(rp config.ResolvedProvider) (Provider, error) {
+	var key string
 	if rp.NeedsAuth {
-		key, err := keyring.GetWithEnv(rp.Name, rp.Env)
-		if err != nil {
+		o := keyring.Locate(rp.Name, rp.KeyLookup())
+		if o.Source == keyring.SourceNone {
+			if rp.KeySlot != "" {
+				return nil, fmt.Errorf("%s key %q not found — run: yeet auth set %s --name %s", rp.Name, rp.KeySlot, rp.Name, rp.KeySlot)
+			}
 			return nil, fmt.Errorf("%s API key not found — run: yeet auth set %s", rp.Name, rp.Name)
 		}
-		switch rp.Protocol {
-		case config.ProtocolAnthropic:
-			return &AnthropicProvider{APIKey: key, Model: rp.Model}, nil
-		default:
-			return &OpenAIProvider{APIKey: key, Model: rp.Model, BaseURL: rp.URL}, nil
-		}
+		key = o.Key
+		rp.KeySlot = o.Slot
+		keyring.MarkUsed(rp.Name, o.Slot)
 	}
+	return newProvider(rp, key), nil
+}
 
-	// No auth required (e.g. Ollama)
+// newProvider builds the client for a resolved provider's protocol.
+func newProvider(rp config.ResolvedProvider, key string) Provider {
+	opts := optionsFor(rp)
 	switch rp.Protocol {
+	case config.ProtocolAnthropic:
+		return &AnthropicProvider{APIKey: key, Model: rp.Model, BaseURL: rp.URL, Options: opts}
 	case config.ProtocolOllama:
-		return &OllamaProvider{URL: rp.URL, Model: rp.Model}, nil
+		return &OllamaProvider{URL: rp.URL, Model: rp.Model, Options: opts}
 	default:
-		return &OpenAIProvider{Model: rp.Model, BaseURL: rp.URL}, nil
+		return &OpenAIProvider{APIKey: key, Model: rp.Model, BaseURL: rp.URL, Options: opts}
 	}
 }
 
@@ -69,23 +76,18 @@ func autoCandidates(cfg config.Config) []candidate {
 		if !rp.NeedsAuth {
 			continue
 		}
-		key, _ := keyring.GetWithEnv(name, rp.Env)
-		if key == "" {
+		o := keyring.Locate(name, rp.KeyLookup())
+		if o.Key == "" {
 			continue
 		}
+		rp.KeySlot = o.Slot
 
-		// Capture for closure
-		model, baseURL, proto := rp.Model, rp.URL, rp.Protocol
 		candidates = append(candidates, candidate{
-			model: model,
-			cost:  ModelInputCost(model),
+			model: rp.Model,
+			cost:  ModelInputCost(rp.Model),
 			builder: func() Provider {
-				switch proto {
-				case config.ProtocolAnthropic:
-					return &AnthropicProvider{APIKey: key, Model: model}
-				default:
-					return &OpenAIProvider{APIKey: key, Model: model, BaseURL: baseURL}
-				}
+				keyring.MarkUsed(rp.Name, o.Slot)
+				return newProvider(rp, o.Key)
 			},
 		})
 	}
@@ -0,0 +1,109 @@
+package ai
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Transport defaults, shared by aiClient and per-provider clients.
+const (
+	defaultDialTimeout   = 10 * time.Second
+	defaultHeaderTimeout = 15 * time.Second
+)
+
+// Transport holds the per-provider connection settings from [providers.<name>].
+// The zero value uses aiClient.
+type Transport struct {
+	Proxy         string
+	CAFile        string
+	ClientCert    string
+	ClientKey     string
+	DialTimeout   time.Duration
+	HeaderTimeout time.Duration
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[Transport]*http.Client{}
+)
+
+// client returns the HTTP client for t. Clients are cached so connections
+// are reused across requests to the same provider.
+func (t Transport) client() (*http.Client, error) {
+	if t == (Transport{}) {
+		return aiClient, nil
+	}
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if c, ok := clients[t]; ok {
+		return c, nil
+	}
+	tr, err := t.transport()
+	if err != nil {
+		return nil, err
+	}
+	c := &http.Client{Transport: tr}
+	clients[t] = c
+	return c, nil
+}
+
+// transport builds an http.Transport from t, keeping aiClient's defaults
+// for everything that isn't configured.
+func (t Transport) transport() (*http.Transport, error) {
+	dial, header := defaultDialTimeout, defaultHeaderTimeout
+	if t.DialTimeout > 0 {
+		dial = t.DialTimeout
+	}
+	if t.HeaderTimeout > 0 {
+		header = t.HeaderTimeout
+	}
+	tr := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: dial}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: header,
+	}
+
+	if t.Proxy != "" {
+		u, err := url.Parse(t.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", t.Proxy, err)
+		}
+		tr.Proxy = http.ProxyURL(u)
+	}
+
+	if t.CAFile == "" && t.ClientCert == "" {
+		return tr, nil
+	}
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", t.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if t.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(t.ClientCert, t.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	tr.TLSClientConfig = tlsCfg
+	return tr, nil
+}
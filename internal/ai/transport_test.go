@@ -0,0 +1,63 @@
+package ai
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTransportClient(t *testing.T) {
+	if c, err := (Transport{}).client(); err != nil || c != aiClient {
+		t.Errorf("zero Transport should use aiClient, got %p, %v", c, err)
+	}
+
+	tr := Transport{Proxy: "http://proxy.corp:3128", DialTimeout: 3 * time.Second}
+	a, err := tr.client()
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	if b, _ := tr.client(); a != b {
+		t.Error("client not cached")
+	}
+	req, _ := http.NewRequest("GET", "https://api.example.com", nil)
+	proxy, err := a.Transport.(*http.Transport).Proxy(req)
+	if err != nil || proxy == nil || proxy.Host != "proxy.corp:3128" {
+		t.Errorf("proxy = %v, %v", proxy, err)
+	}
+
+	bad := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(bad, []byte("not a certificate"), 0600)
+	if _, err := (Transport{CAFile: bad}).client(); err == nil {
+		t.Error("expected error for CA file without certificates")
+	}
+}
+
+func TestTransportCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	var result struct{ OK bool }
+	if err := doRequest(context.Background(), nil, "POST", server.URL, nil, nil, &result); err == nil {
+		t.Fatal("self-signed server should be rejected without ca_file")
+	}
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(ca, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	client, err := Transport{CAFile: ca}.client()
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+	if err := doRequest(context.Background(), client, "POST", server.URL, nil, nil, &result); err != nil || !result.OK {
+		t.Errorf("request with ca_file = %+v, %v", result, err)
+	}
+}
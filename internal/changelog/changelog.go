@@ -0,0 +1,327 @@
+// Package changelog groups commits into Keep a Changelog sections and
+// renders release notes.
+package changelog
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rasalas/yeet/internal/semver"
+)
+
+// Keep a Changelog sections, in the order they appear in a release.
+const (
+	Added      = "Added"
+	Changed    = "Changed"
+	Deprecated = "Deprecated"
+	Removed    = "Removed"
+	Fixed      = "Fixed"
+	Security   = "Security"
+)
+
+// Sections lists the sections in release order.
+var Sections = []string{Added, Changed, Deprecated, Removed, Fixed, Security}
+
+// Unreleased is the heading of changes that aren't part of a release yet.
+const Unreleased = "Unreleased"
+
+// Entry is one commit of a release.
+type Entry struct {
+	Hash     string
+	Type     string // conventional commit type, "" when the subject has none
+	Scope    string
+	Breaking bool
+	Subject  string // description without type, scope and PR reference
+	PR       int    // referenced PR number, 0 when there is none
+	Merge    bool   // merge commit; its subject says nothing until the PR title is known
+}
+
+var (
+	conventionalRe = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+	// "title (#12)" (GitHub, Gitea squash), "title (!12)" (GitLab).
+	prSuffixRe = regexp.MustCompile(`\s*\([#!](\d+)\)$`)
+	// "Merge pull request #12 from …", "Merge pull request 'title' (#12) from …",
+	// "Merged in feature (pull request #12)".
+	mergeRe = regexp.MustCompile(`^Merge(?:d in .*\(pull request #(\d+)\)| pull request #(\d+) | pull request '.*' \(#(\d+)\) )`)
+)
+
+// Parse reads one-line log entries ("<hash> <subject>", as git log
+// --oneline prints them).
+func Parse(log string) []Entry {
+	var entries []Entry
+	for _, line := range strings.Split(log, "\n") {
+		hash, subject, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		e := ParseSubject(subject)
+		e.Hash = hash
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// ParseSubject splits a commit subject or PR title into its conventional
+// commit parts and PR reference.
+func ParseSubject(subject string) Entry {
+	subject = strings.TrimSpace(subject)
+	if strings.HasPrefix(subject, "Merge ") || strings.HasPrefix(subject, "Merged in ") {
+		e := Entry{Subject: subject, Merge: true}
+		if m := mergeRe.FindStringSubmatch(subject); m != nil {
+			e.PR, _ = strconv.Atoi(m[1] + m[2] + m[3])
+		}
+		return e
+	}
+
+	var e Entry
+	if m := prSuffixRe.FindStringSubmatch(subject); m != nil {
+		e.PR, _ = strconv.Atoi(m[1])
+		subject = strings.TrimSpace(subject[:len(subject)-len(m[0])])
+	}
+	e.Subject = subject
+	if m := conventionalRe.FindStringSubmatch(subject); m != nil {
+		e.Type = strings.ToLower(m[1])
+		e.Scope = m[2]
+		e.Breaking = m[3] == "!"
+		e.Subject = m[4]
+	}
+	if strings.HasPrefix(e.Subject, "BREAKING CHANGE") {
+		e.Breaking = true
+	}
+	return e
+}
+
+// WithTitle replaces the entry's subject with the title of its PR, which
+// usually describes the change better than a single commit — and is the
+// only description a merge commit has.
+func (e Entry) WithTitle(title string) Entry {
+	t := ParseSubject(title)
+	t.Hash, t.PR, t.Merge = e.Hash, e.PR, false
+	t.Breaking = t.Breaking || e.Breaking
+	return t
+}
+
+// Level returns the semantic version bump the entries call for: major for
+// breaking changes, minor for features and patch for anything else users
+// notice. It returns "" when only internal changes were made.
+func Level(entries []Entry) string {
+	level := ""
+	for _, e := range entries {
+		switch s := Section(e); {
+		case s == "":
+		case e.Breaking:
+			return semver.Major
+		case e.Type == "feat":
+			level = semver.Minor
+		case level == "":
+			level = semver.Patch
+		}
+	}
+	return level
+}
+
+// internalTypes are conventional types that don't change what users get.
+var internalTypes = []string{"build", "chore", "ci", "docs", "style", "test", "tests"}
+
+// Section returns the Keep a Changelog section of an entry, or "" for
+// entries users don't need to read about: internal changes and merge
+// commits without a PR title.
+func Section(e Entry) string {
+	switch {
+	case e.Merge:
+		return ""
+	case e.Type == "security" || strings.EqualFold(e.Scope, "security"):
+		return Security
+	case e.Breaking:
+		return Changed
+	case e.Type == "feat":
+		return Added
+	case e.Type == "fix":
+		return Fixed
+	case e.Type == "revert":
+		return Removed
+	case e.Type == "deprecate":
+		return Deprecated
+	case slices.Contains(internalTypes, e.Type):
+		return ""
+	}
+	// perf, refactor and subjects without a type.
+	return Changed
+}
+
+// Group sorts entries into sections, in release order. Sections without
+// entries are left out.
+func Group(entries []Entry) (sections []string, groups map[string][]Entry) {
+	groups = map[string][]Entry{}
+	for _, e := range entries {
+		if s := Section(e); s != "" {
+			groups[s] = append(groups[s], e)
+		}
+	}
+	for _, s := range Sections {
+		if len(groups[s]) > 0 {
+			sections = append(sections, s)
+		}
+	}
+	return sections, groups
+}
+
+// Outline lists grouped entries as Markdown, one "### Section" heading per
+// group. It is what the AI rewrites into release notes, and the notes
+// themselves when no AI is used.
+func Outline(sections []string, groups map[string][]Entry) string {
+	var b strings.Builder
+	for i, s := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n", s)
+		for _, e := range groups[s] {
+			b.WriteString("- ")
+			if e.Breaking {
+				b.WriteString("**Breaking:** ")
+			}
+			if e.Scope != "" {
+				fmt.Fprintf(&b, "%s: ", e.Scope)
+			}
+			b.WriteString(e.Subject)
+			if e.PR > 0 {
+				fmt.Fprintf(&b, " (#%d)", e.PR)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// ParseNotes reads "### Section" headings and their "- " bullets from a
+// model reply. Unknown sections, prose and empty sections are dropped, and
+// the sections are returned in release order.
+func ParseNotes(reply string) (sections []string, notes map[string][]string) {
+	notes = map[string][]string{}
+	current := ""
+	for _, line := range strings.Split(reply, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if heading, ok := strings.CutPrefix(trimmed, "#"); ok {
+			heading = strings.TrimSpace(strings.TrimLeft(heading, "#"))
+			current = ""
+			for _, s := range Sections {
+				if strings.EqualFold(heading, s) {
+					current = s
+				}
+			}
+			continue
+		}
+		if current == "" {
+			continue
+		}
+		if item, ok := cutBullet(trimmed); ok && item != "" {
+			notes[current] = append(notes[current], item)
+		} else if trimmed != "" && len(notes[current]) > 0 && line != trimmed {
+			// An indented continuation of the previous bullet.
+			last := &notes[current][len(notes[current])-1]
+			*last += " " + trimmed
+		}
+	}
+	for _, s := range Sections {
+		if len(notes[s]) > 0 {
+			sections = append(sections, s)
+		}
+	}
+	return sections, notes
+}
+
+func cutBullet(line string) (string, bool) {
+	for _, prefix := range []string{"- ", "* ", "+ "} {
+		if item, ok := strings.CutPrefix(line, prefix); ok {
+			return strings.TrimSpace(item), true
+		}
+	}
+	return "", false
+}
+
+// Render formats a release in Keep a Changelog style:
+//
+//	## [1.2.0] - 2024-05-01
+//
+//	### Added
+//
+//	- …
+func Render(version, date string, sections []string, notes map[string][]string) string {
+	var b strings.Builder
+	if version == Unreleased {
+		fmt.Fprintf(&b, "## [%s]\n", version)
+	} else {
+		fmt.Fprintf(&b, "## [%s] - %s\n", version, date)
+	}
+	for _, s := range sections {
+		fmt.Fprintf(&b, "\n### %s\n\n", s)
+		for _, n := range notes[s] {
+			fmt.Fprintf(&b, "- %s\n", n)
+		}
+	}
+	return b.String()
+}
+
+// header starts a new CHANGELOG.md.
+const header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+`
+
+// Prepend inserts a rendered release above the newest one in an existing
+// changelog, below its title and introduction. An [Unreleased] section stays
+// on top of versioned releases. An empty changelog gets the standard Keep a
+// Changelog header. A release with the same heading as an
+// existing one replaces it, so regenerating the notes doesn't duplicate
+// them.
+func Prepend(existing, release string) string {
+	existing = strings.ReplaceAll(existing, "\r\n", "\n")
+	if strings.TrimSpace(existing) == "" {
+		return header + "\n" + release
+	}
+	release = strings.TrimRight(release, "\n") + "\n"
+
+	lines := strings.SplitAfter(existing, "\n")
+	heading := releaseHeading(strings.SplitN(release, "\n", 2)[0])
+	i := nextRelease(lines, 0)
+	if i < len(lines) && heading != Unreleased && releaseHeading(lines[i]) == Unreleased {
+		i = nextRelease(lines, i+1)
+	}
+	if i == len(lines) {
+		return strings.TrimRight(existing, "\n") + "\n\n" + release
+	}
+	before, rest := strings.Join(lines[:i], ""), lines[i:]
+	if releaseHeading(rest[0]) == heading {
+		// Drop the old copy of this release, up to the next one.
+		rest = rest[nextRelease(rest, 1):]
+	}
+	if len(rest) == 0 {
+		return before + release
+	}
+	return before + release + "\n" + strings.Join(rest, "")
+}
+
+// nextRelease returns the index of the first release heading at or after
+// from, or len(lines).
+func nextRelease(lines []string, from int) int {
+	for i := from; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// releaseHeading returns the version part of a "## [1.2.0] - date" heading.
+func releaseHeading(line string) string {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+	version, _, _ := strings.Cut(line, " - ")
+	return strings.Trim(strings.TrimSpace(version), "[]")
+}
@@ -0,0 +1,187 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	log := `a1 feat(cli): add changelog command (#12)
+b2 fix!: drop --legacy flag
+c3 Merge pull request #9 from acme/feature
+d4 docs: update README
+e5 Update dependencies
+f6 Merge branch 'main' into feature
+g7 fix(security): escape branch names (!4)
+h8 Merged in bugfix (pull request #31)`
+	got := Parse(log)
+	want := []Entry{
+		{Hash: "a1", Type: "feat", Scope: "cli", Subject: "add changelog command", PR: 12},
+		{Hash: "b2", Type: "fix", Breaking: true, Subject: "drop --legacy flag"},
+		{Hash: "c3", Subject: "Merge pull request #9 from acme/feature", PR: 9, Merge: true},
+		{Hash: "d4", Type: "docs", Subject: "update README"},
+		{Hash: "e5", Subject: "Update dependencies"},
+		{Hash: "f6", Subject: "Merge branch 'main' into feature", Merge: true},
+		{Hash: "g7", Type: "fix", Scope: "security", Subject: "escape branch names", PR: 4},
+		{Hash: "h8", Subject: "Merged in bugfix (pull request #31)", PR: 31, Merge: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse =\n%+v\nwant\n%+v", got, want)
+	}
+
+	titled := got[2].WithTitle("feat: stacked PRs")
+	if titled.Merge || titled.Type != "feat" || titled.Subject != "stacked PRs" || titled.PR != 9 || titled.Hash != "c3" {
+		t.Errorf("WithTitle = %+v", titled)
+	}
+}
+
+func TestGroupAndOutline(t *testing.T) {
+	entries := Parse(`a1 fix: handle empty diff
+b2 feat(pr): add --watch (#7)
+c3 chore: bump deps
+d4 refactor!: rename config keys
+e5 Merge branch 'x'`)
+	sections, groups := Group(entries)
+	if !reflect.DeepEqual(sections, []string{Added, Changed, Fixed}) {
+		t.Fatalf("sections = %v", sections)
+	}
+	want := `### Added
+
+- pr: add --watch (#7)
+
+### Changed
+
+- **Breaking:** rename config keys
+
+### Fixed
+
+- handle empty diff
+`
+	if got := Outline(sections, groups); got != want {
+		t.Errorf("Outline =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseNotesAndRender(t *testing.T) {
+	reply := "Here are the notes:\n\n## Added\n\n- PR checks can be watched\n  until they finish (#7)\n\n### Misc\n\n- ignored\n\n### fixed\n* Empty diffs no longer crash\n\n### Removed\n"
+	sections, notes := ParseNotes(reply)
+	if !reflect.DeepEqual(sections, []string{Added, Fixed}) {
+		t.Fatalf("sections = %v", sections)
+	}
+	got := Render("1.2.0", "2024-05-01", sections, notes)
+	want := `## [1.2.0] - 2024-05-01
+
+### Added
+
+- PR checks can be watched until they finish (#7)
+
+### Fixed
+
+- Empty diffs no longer crash
+`
+	if got != want {
+		t.Errorf("Render =\n%s\nwant\n%s", got, want)
+	}
+	if got := Render(Unreleased, "2024-05-01", nil, nil); got != "## [Unreleased]\n" {
+		t.Errorf("Render(Unreleased) = %q", got)
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	release := "## [1.1.0] - 2024-06-01\n\n### Fixed\n\n- b\n"
+	existing := `# Changelog
+
+Intro.
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- a
+`
+	want := `# Changelog
+
+Intro.
+
+## [1.1.0] - 2024-06-01
+
+### Fixed
+
+- b
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- a
+`
+	got := Prepend(existing, release)
+	if got != want {
+		t.Errorf("Prepend =\n%s\nwant\n%s", got, want)
+	}
+	// Regenerating a release replaces it.
+	if again := Prepend(got, "## [1.1.0] - 2024-06-02\n\n### Fixed\n\n- c\n"); again != want[:len("# Changelog\n\nIntro.\n\n")]+"## [1.1.0] - 2024-06-02\n\n### Fixed\n\n- c\n\n"+existing[len("# Changelog\n\nIntro.\n\n"):] {
+		t.Errorf("Prepend (replace) =\n%s", again)
+	}
+	if got := Prepend("", release); got != header+"\n"+release {
+		t.Errorf("Prepend(empty) =\n%s", got)
+	}
+}
+
+func TestPrependBelowUnreleased(t *testing.T) {
+	existing := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- c
+
+## [1.0.0] - 2024-01-01
+
+- a
+`
+	want := `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- c
+
+## [1.1.0] - 2024-06-01
+
+- b
+
+## [1.0.0] - 2024-01-01
+
+- a
+`
+	got := Prepend(existing, "## [1.1.0] - 2024-06-01\n\n- b\n")
+	if got != want {
+		t.Errorf("Prepend =\n%s\nwant\n%s", got, want)
+	}
+	// Regenerating the Unreleased notes replaces them in place.
+	if got := Prepend(existing, "## [Unreleased]\n\n- d\n"); got != "# Changelog\n\n## [Unreleased]\n\n- d\n\n## [1.0.0] - 2024-01-01\n\n- a\n" {
+		t.Errorf("Prepend(Unreleased) =\n%s", got)
+	}
+	// Only an Unreleased section: the release goes after it.
+	if got := Prepend("# Changelog\n\n## [Unreleased]\n", "## [1.0.0] - 2024-01-01\n\n- a\n"); got != "# Changelog\n\n## [Unreleased]\n\n## [1.0.0] - 2024-01-01\n\n- a\n" {
+		t.Errorf("Prepend(only Unreleased) =\n%s", got)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	for log, want := range map[string]string{
+		"a1 chore: bump deps\nb2 docs: typo":      "",
+		"a1 chore: bump deps\nb2 fix: crash":      "patch",
+		"a1 Update README":                        "patch",
+		"a1 fix: crash\nb2 feat: new flag":        "minor",
+		"a1 feat: new flag\nb2 refactor!: rename": "major",
+		"a1 Merge branch 'main' into x":           "",
+	} {
+		if got := Level(Parse(log)); got != want {
+			t.Errorf("Level(%q) = %q, want %q", log, got, want)
+		}
+	}
+}
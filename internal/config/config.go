@@ -1,20 +1,45 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/rasalas/yeet/internal/keyring"
 	"github.com/rasalas/yeet/internal/xdg"
 )
 
+// ProviderConfig configures one provider under [providers.<name>]. Builtin,
+// registry and custom providers share this shape; unset fields fall back to
+// the Registry defaults.
 type ProviderConfig struct {
-	Model string `toml:"model,omitempty"`
-	URL   string `toml:"url,omitempty"`
-	Env   string `toml:"env,omitempty"`
+	Model    string            `toml:"model,omitempty"`
+	URL      string            `toml:"url,omitempty"`
+	Env      string            `toml:"env,omitempty"`
+	KeyCmd   string            `toml:"key_cmd,omitempty"`
+	Protocol Protocol          `toml:"protocol,omitempty"`
+	Headers  map[string]string `toml:"headers,omitempty"`
+
+	// Request tuning. Zero values keep the provider defaults.
+	Temperature   *float64      `toml:"temperature,omitempty"`
+	MaxTokens     int           `toml:"max_tokens,omitzero"`
+	Timeout       time.Duration `toml:"timeout,omitzero"`
+	ContextWindow int           `toml:"context_window,omitzero"`
+
+	// Transport settings for corporate proxies, internal gateways and mTLS.
+	Proxy         string        `toml:"proxy,omitempty"`
+	CAFile        string        `toml:"ca_file,omitempty"`
+	ClientCert    string        `toml:"client_cert,omitempty"`
+	ClientKey     string        `toml:"client_key,omitempty"`
+	DialTimeout   time.Duration `toml:"dial_timeout,omitzero"`
+	HeaderTimeout time.Duration `toml:"header_timeout,omitzero"`
 }
 
 type PricingOverride struct {
@@ -22,13 +47,106 @@ type PricingOverride struct {
 	Output float64 `toml:"output"`
 }
 
+// Forge types for [forges."<host>"].
+const (
+	ForgeGitHub    = "github"
+	ForgeGitLab    = "gitlab"
+	ForgeGitea     = "gitea"
+	ForgeForgejo   = "forgejo"
+	ForgeBitbucket = "bitbucket"
+)
+
+// ForgeTypes lists the supported forge types.
+var ForgeTypes = []string{ForgeGitHub, ForgeGitLab, ForgeGitea, ForgeForgejo, ForgeBitbucket}
+
+// MergeStrategies lists the values of pr.merge_strategy.
+var MergeStrategies = []string{"merge", "squash", "rebase"}
+
+// ForgeConfig tells yeet pr which forge runs on a host, for self-hosted
+// instances whose name does not give it away.
+type ForgeConfig struct {
+	Type   string `toml:"type,omitempty"`
+	APIURL string `toml:"api_url,omitempty"` // defaults to the type's standard API path on the host
+}
+
+// PRConfig holds defaults for yeet pr; the command-line flags add to them.
+type PRConfig struct {
+	// Remote is the remote PRs are opened against, e.g. "upstream" when
+	// working from a fork. Defaults to a remote named "upstream" that points
+	// at another repository, else the push remote.
+	Remote string `toml:"remote,omitempty"`
+
+	Draft     bool     `toml:"draft,omitempty"`
+	Labels    []string `toml:"labels,omitempty"`
+	Reviewers []string `toml:"reviewers,omitempty"`
+	Assignees []string `toml:"assignees,omitempty"`
+
+	// SuggestReviewers proposes this many reviewers from the git history of
+	// the changed files; 0 turns suggestions off.
+	SuggestReviewers int `toml:"suggest_reviewers,omitzero"`
+
+	// MergeStrategy is how --auto-merge merges: "merge", "squash" or
+	// "rebase". Defaults to "merge".
+	MergeStrategy string `toml:"merge_strategy,omitempty"`
+}
+
+// ReviewBlockLevels lists the values of review.block_on.
+var ReviewBlockLevels = []string{"high", "medium", "low", "never"}
+
+// ReviewConfig holds settings for yeet review and the review step of the
+// commit flow.
+type ReviewConfig struct {
+	// BeforeCommit reviews the staged changes before every yeet commit.
+	BeforeCommit bool `toml:"before_commit,omitempty"`
+	// BlockOn is the lowest severity that stops a commit until confirmed
+	// (and makes yeet review exit non-zero): "high" (default), "medium",
+	// "low" or "never".
+	BlockOn string `toml:"block_on,omitempty"`
+}
+
+// BlockLevel returns BlockOn, defaulting to "high".
+func (r ReviewConfig) BlockLevel() string {
+	if r.BlockOn == "" {
+		return "high"
+	}
+	return r.BlockOn
+}
+
+// ReleaseConfig holds settings for yeet release.
+type ReleaseConfig struct {
+	// ForgeRelease also creates a release on the forge for each pushed tag.
+	ForgeRelease bool `toml:"forge_release,omitempty"`
+}
+
 type Config struct {
+	Version   int                        `toml:"version"`
 	Provider  string                     `toml:"provider"`
-	Anthropic ProviderConfig             `toml:"anthropic"`
-	OpenAI    ProviderConfig             `toml:"openai"`
-	Ollama    ProviderConfig             `toml:"ollama"`
-	Custom    map[string]ProviderConfig  `toml:"custom"`
+	Profile   string                     `toml:"profile,omitempty"`
+	Prompt    string                     `toml:"prompt,omitempty"`
+	Push      *bool                      `toml:"push,omitempty"`
+	Providers map[string]ProviderConfig  `toml:"providers,omitempty"`
 	Pricing   map[string]PricingOverride `toml:"pricing"`
+	Profiles  map[string]Profile         `toml:"profiles,omitempty"`
+
+	// PushRemote is the remote new branches are pushed to (default "origin").
+	PushRemote string `toml:"push_remote,omitempty"`
+
+	// Keys selects a named key slot per provider (see yeet auth set --name).
+	Keys map[string]string `toml:"keys,omitempty"`
+
+	// Forges maps git hosts to forge types, e.g. [forges."git.company.io"].
+	Forges map[string]ForgeConfig `toml:"forges,omitempty"`
+
+	PR      PRConfig      `toml:"pr,omitzero"`
+	Review  ReviewConfig  `toml:"review,omitzero"`
+	Release ReleaseConfig `toml:"release,omitzero"`
+
+	// ActiveProfile and ProfileSource are set by Load and never persisted.
+	ActiveProfile string `toml:"-"`
+	ProfileSource string `toml:"-"`
+
+	// warnings collects non-fatal load problems (e.g. invalid YEET_* values) for Validate.
+	warnings []string
 }
 
 // KnownModels lists available models per provider for the TUI picker.
@@ -44,10 +162,8 @@ var KnownModels = map[string][]string{
 
 func DefaultConfig() Config {
 	return Config{
-		Provider:  "auto",
-		Anthropic: ProviderConfig{Model: Registry["anthropic"].DefaultModel},
-		OpenAI:    ProviderConfig{Model: Registry["openai"].DefaultModel},
-		Ollama:    ProviderConfig{Model: Registry["ollama"].DefaultModel, URL: Registry["ollama"].DefaultURL},
+		Version:  CurrentVersion,
+		Provider: "auto",
 	}
 }
 
@@ -73,19 +189,40 @@ func Path() (string, error) {
 	return path, nil
 }
 
+// Load reads the config file, applies the active profile (see SelectProfile)
+// and layers YEET_* environment overrides on top.
+// Use LoadFile when the result will be written back with Save.
 func Load() (Config, error) {
+	cfg, err := LoadFile()
+	if err != nil {
+		return cfg, err
+	}
+	cfg = cfg.ApplyProfile(cfg.SelectProfile())
+	cfg.applyEnv()
+	return cfg, nil
+}
+
+// LoadFile reads the config file as written, without profile overrides.
+// Older schema versions are migrated in memory; Save writes the upgrade back.
+func LoadFile() (Config, error) {
 	cfg := DefaultConfig()
 	path, err := configPath()
 	if err != nil {
 		return cfg, err
 	}
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
 		return cfg, nil
 	}
-	_, err = toml.DecodeFile(path, &cfg)
-	return cfg, err
+	if err != nil {
+		return cfg, err
+	}
+	return decode(string(data), cfg)
 }
 
+// Save writes the config back to disk. Existing files are edited in place —
+// only changed keys are touched, so comments and layout survive. The previous
+// file is kept as config.toml.bak and the new one is swapped in atomically.
 func Save(cfg Config) error {
 	path, err := configPath()
 	if err != nil {
@@ -95,35 +232,159 @@ func Save(cfg Config) error {
 		return err
 	}
 
-	// Don't persist models that match defaults — they'll auto-update with new versions.
+	old, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	data, err := render(string(old), prepareSave(cfg))
+	if err != nil {
+		return err
+	}
+	return replaceFile(path, old, data)
+}
+
+// prepareSave normalizes a config before writing it.
+func prepareSave(cfg Config) Config {
 	out := cfg
-	for _, name := range Providers() {
-		entry, ok := Registry[name]
-		if !ok {
-			continue
-		}
-		switch name {
-		case "anthropic":
-			if out.Anthropic.Model == entry.DefaultModel {
-				out.Anthropic.Model = ""
+	out.Version = CurrentVersion
+
+	// Don't persist registry defaults — models auto-update with new versions.
+	// Entries left empty are dropped entirely.
+	out.Providers = make(map[string]ProviderConfig, len(cfg.Providers))
+	for name, pc := range cfg.Providers {
+		if entry, ok := Registry[name]; ok {
+			if pc.Model == entry.DefaultModel {
+				pc.Model = ""
 			}
-		case "openai":
-			if out.OpenAI.Model == entry.DefaultModel {
-				out.OpenAI.Model = ""
+			if pc.URL == entry.DefaultURL {
+				pc.URL = ""
 			}
-		case "ollama":
-			if out.Ollama.Model == entry.DefaultModel {
-				out.Ollama.Model = ""
+			if pc.Env == entry.DefaultEnv {
+				pc.Env = ""
 			}
+			if pc.Protocol == entry.Protocol {
+				pc.Protocol = ""
+			}
+		}
+		if !pc.IsZero() {
+			out.Providers[name] = pc
+		}
+	}
+	return out
+}
+
+// IsZero reports whether no field of the provider config is set.
+func (pc ProviderConfig) IsZero() bool {
+	return pc.Model == "" && pc.URL == "" && pc.Env == "" && pc.KeyCmd == "" && pc.Protocol == "" &&
+		len(pc.Headers) == 0 && pc.Temperature == nil && pc.MaxTokens == 0 &&
+		pc.Timeout == 0 && pc.ContextWindow == 0 && pc.Proxy == "" && pc.CAFile == "" &&
+		pc.ClientCert == "" && pc.ClientKey == "" && pc.DialTimeout == 0 && pc.HeaderTimeout == 0
+}
+
+// render applies cfg to the existing file contents: first any schema
+// migrations, then the changed keys. Only a new or blank file is encoded
+// from scratch; a file that can't be edited by line is an error rather than
+// being re-encoded without its comments and layout.
+func render(existing string, cfg Config) (string, error) {
+	if strings.TrimSpace(existing) != "" {
+		text, err := edit(existing, cfg)
+		if err != nil {
+			return "", fmt.Errorf("config.toml can't be updated without losing its comments and layout (%w) — change it with yeet config edit", err)
 		}
+		return text, nil
 	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(cfg); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
 
-	f, err := os.Create(path)
+func edit(existing string, cfg Config) (string, error) {
+	doc, err := parseDocument(existing)
+	if err != nil {
+		return "", err
+	}
+
+	var raw, migrated map[string]any
+	if _, err := toml.Decode(existing, &raw); err != nil {
+		return "", err
+	}
+	if _, err := toml.Decode(existing, &migrated); err != nil {
+		return "", err
+	}
+	if _, err := migrateTree(migrated); err != nil {
+		return "", err
+	}
+	var rawLeaves, migratedLeaves []keyValue
+	treeLeaves(raw, nil, &rawLeaves)
+	treeLeaves(migrated, nil, &migratedLeaves)
+	if err := doc.applyChanges(rawLeaves, migratedLeaves, nil); err != nil {
+		return "", err
+	}
+	var tables [][]string
+	treeTables(raw, nil, &tables)
+	for _, path := range tables {
+		if !hasTable(migrated, path) {
+			doc.dropEmptyTable(path)
+		}
+	}
+
+	before, err := decode(existing, Config{})
+	if err != nil {
+		return "", err
+	}
+	var defaults []keyValue
+	if strings.TrimSpace(existing) != "" {
+		defaults = structLeaves(DefaultConfig())
+	}
+	if err := doc.applyChanges(structLeaves(before), structLeaves(cfg), defaults); err != nil {
+		return "", err
+	}
+	return doc.String(), nil
+}
+
+// replaceFile writes data to path unless it is unchanged, keeping old as a
+// .bak backup.
+func replaceFile(path string, old []byte, data string) error {
+	if old != nil && data == string(old) {
+		return nil
+	}
+	if old != nil {
+		if err := os.WriteFile(path+".bak", old, 0644); err != nil {
+			return fmt.Errorf("backup config: %w", err)
+		}
+	}
+	return writeAtomic(path, []byte(data))
+}
+
+// writeAtomic writes data to a temp file next to path and renames it into
+// place, so a crash never leaves a truncated config behind.
+func writeAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.toml")
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	return toml.NewEncoder(f).Encode(out)
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
 // Providers returns the builtin provider names.
@@ -131,7 +392,7 @@ func Providers() []string {
 	return []string{"anthropic", "openai", "ollama"}
 }
 
-// AllProviders returns builtin + custom + discovered (OpenCode) provider names.
+// AllProviders returns builtin + configured + registry + discovered (OpenCode) provider names.
 func (c Config) AllProviders() []string {
 	builtin := Providers()
 	seen := make(map[string]bool, len(builtin))
@@ -140,7 +401,7 @@ func (c Config) AllProviders() []string {
 	}
 
 	var extra []string
-	for name := range c.Custom {
+	for name := range c.Providers {
 		if !seen[name] {
 			extra = append(extra, name)
 			seen[name] = true
@@ -171,14 +432,16 @@ func DefaultModel(provider string) string {
 	return ""
 }
 
-// ResolveProviderFull returns the fully-resolved provider configuration.
-// Three-layer merge: Registry defaults → named struct fields (builtins) → Custom overrides.
-// Purely custom providers (not in Registry) default to ProtocolOpenAI + NeedsAuth.
+// ResolveProviderFull returns the fully-resolved provider configuration:
+// Registry defaults overlaid with [providers.<name>].
+// Providers not in the Registry default to ProtocolOpenAI + NeedsAuth.
 func (c Config) ResolveProviderFull(name string) (ResolvedProvider, bool) {
 	entry, inRegistry := Registry[name]
-	custom, hasCustom := c.Custom[name]
+	pc, configured := c.Providers[name]
+	if !inRegistry && !configured {
+		return ResolvedProvider{}, false
+	}
 
-	// Start from registry defaults
 	rp := ResolvedProvider{
 		Name:      name,
 		Model:     entry.DefaultModel,
@@ -187,135 +450,229 @@ func (c Config) ResolveProviderFull(name string) (ResolvedProvider, bool) {
 		Protocol:  entry.Protocol,
 		NeedsAuth: entry.NeedsAuth,
 	}
-
-	// Layer 2: named struct fields for builtins
-	switch name {
-	case "anthropic":
-		if c.Anthropic.Model != "" {
-			rp.Model = c.Anthropic.Model
-		}
-		if c.Anthropic.URL != "" {
-			rp.URL = c.Anthropic.URL
-		}
-		if c.Anthropic.Env != "" {
-			rp.Env = c.Anthropic.Env
-		}
-	case "openai":
-		if c.OpenAI.Model != "" {
-			rp.Model = c.OpenAI.Model
-		}
-		if c.OpenAI.URL != "" {
-			rp.URL = c.OpenAI.URL
-		}
-		if c.OpenAI.Env != "" {
-			rp.Env = c.OpenAI.Env
-		}
-	case "ollama":
-		if c.Ollama.Model != "" {
-			rp.Model = c.Ollama.Model
-		}
-		if c.Ollama.URL != "" {
-			rp.URL = c.Ollama.URL
-		}
-		if c.Ollama.Env != "" {
-			rp.Env = c.Ollama.Env
-		}
+	if !inRegistry {
+		rp.Protocol = ProtocolOpenAI
+		rp.NeedsAuth = true
 	}
 
-	// Layer 3: Custom overrides (covers well-known overrides + purely custom)
-	if hasCustom {
-		if custom.Model != "" {
-			rp.Model = custom.Model
-		}
-		if custom.URL != "" {
-			rp.URL = custom.URL
-		}
-		if custom.Env != "" {
-			rp.Env = custom.Env
-		}
+	if pc.Model != "" {
+		rp.Model = pc.Model
 	}
-
-	// Purely custom provider not in registry
-	if !inRegistry && !hasCustom {
-		return ResolvedProvider{}, false
+	if pc.URL != "" {
+		rp.URL = pc.URL
 	}
-	if !inRegistry {
-		rp.Protocol = ProtocolOpenAI
-		rp.NeedsAuth = true
+	if pc.Env != "" {
+		rp.Env = pc.Env
 	}
+	if pc.Protocol != "" {
+		rp.Protocol = pc.Protocol
+	}
+	rp.KeyCmd = pc.KeyCmd
+	rp.KeySlot = c.Keys[name]
+	rp.Headers = pc.Headers
+	rp.Temperature = pc.Temperature
+	rp.MaxTokens = pc.MaxTokens
+	rp.Timeout = pc.Timeout
+	rp.ContextWindow = pc.ContextWindow
+	rp.Proxy = pc.Proxy
+	rp.CAFile = expandHome(pc.CAFile)
+	rp.ClientCert = expandHome(pc.ClientCert)
+	rp.ClientKey = expandHome(pc.ClientKey)
+	rp.DialTimeout = pc.DialTimeout
+	rp.HeaderTimeout = pc.HeaderTimeout
 
 	return rp, true
 }
 
-
-// SetModel writes a model to the appropriate config location.
+// SetModel writes a model to the provider's [providers.<name>] entry.
 func (c *Config) SetModel(provider, model string) {
-	switch provider {
-	case "anthropic":
-		c.Anthropic.Model = model
-	case "openai":
-		c.OpenAI.Model = model
-	case "ollama":
-		c.Ollama.Model = model
-	default:
-		if c.Custom == nil {
-			c.Custom = make(map[string]ProviderConfig)
-		}
-		pc := c.Custom[provider]
-		pc.Model = model
-		// Inherit URL/Env from Registry if not set
-		if entry, ok := Registry[provider]; ok {
-			if pc.URL == "" {
-				pc.URL = entry.DefaultURL
-			}
-			if pc.Env == "" {
-				pc.Env = entry.DefaultEnv
-			}
-		}
-		c.Custom[provider] = pc
+	if c.Providers == nil {
+		c.Providers = make(map[string]ProviderConfig)
 	}
+	pc := c.Providers[provider]
+	pc.Model = model
+	c.Providers[provider] = pc
+}
+
+// Problem is a single validation finding. Warnings don't make a config invalid.
+type Problem struct {
+	Message string
+	Warning bool
 }
 
 // Validate checks the config for problems and returns all warnings/errors.
 func (c Config) Validate() []string {
-	var problems []string
+	var messages []string
+	for _, p := range c.Problems() {
+		messages = append(messages, p.Message)
+	}
+	return messages
+}
+
+// Errors returns only the problems that make the config invalid.
+func (c Config) Errors() []string {
+	var messages []string
+	for _, p := range c.Problems() {
+		if !p.Warning {
+			messages = append(messages, p.Message)
+		}
+	}
+	return messages
+}
+
+// Problems checks the config and returns all findings with their severity.
+// It only looks at the config itself — whether key slots are stored or TLS
+// files exist is checked by yeet doctor.
+func (c Config) Problems() []Problem {
+	var problems []Problem
+	add := func(warning bool, format string, args ...any) {
+		problems = append(problems, Problem{Message: fmt.Sprintf(format, args...), Warning: warning})
+	}
 
 	if c.Provider != "" && c.Provider != "auto" {
 		if _, ok := Registry[c.Provider]; !ok {
-			if _, ok := c.Custom[c.Provider]; !ok {
-				problems = append(problems, fmt.Sprintf("unknown provider %q — add it to [custom.%s] in config.toml or use a known provider", c.Provider, c.Provider))
+			if _, ok := c.Providers[c.Provider]; !ok {
+				add(false, "unknown provider %q — add it to [providers.%s] in config.toml or use a known provider", c.Provider, c.Provider)
+			}
+		}
+	}
+
+	if c.ActiveProfile != "" {
+		if _, ok := c.Profiles[c.ActiveProfile]; !ok {
+			add(false, "unknown profile %q (%s) — add [profiles.%s] to config.toml", c.ActiveProfile, c.ProfileSource, c.ActiveProfile)
+		}
+	} else if c.Profile != "" {
+		if _, ok := c.Profiles[c.Profile]; !ok {
+			add(false, "default profile %q is not defined in [profiles]", c.Profile)
+		}
+	}
+	for _, name := range c.ProfileNames() {
+		p := c.Profiles[name].Provider
+		if p == "" || p == "auto" {
+			continue
+		}
+		if _, ok := Registry[p]; !ok {
+			if _, ok := c.Providers[p]; !ok {
+				add(false, "profile %q uses unknown provider %q", name, p)
 			}
 		}
 	}
 
-	for name, pc := range c.Custom {
-		if _, ok := Registry[name]; ok {
-			continue // registry providers don't need url
+	for _, name := range sortedProviderNames(c.Providers) {
+		pc := c.Providers[name]
+		if _, ok := Registry[name]; !ok {
+			if pc.URL == "" {
+				add(false, "provider %q is missing url", name)
+			}
+			if pc.Env == "" && pc.KeyCmd == "" {
+				add(true, "provider %q has no env var set (key must be in keyring)", name)
+			}
 		}
-		if pc.URL == "" {
-			problems = append(problems, fmt.Sprintf("custom provider %q is missing url", name))
+		switch pc.Protocol {
+		case "", ProtocolAnthropic, ProtocolOpenAI, ProtocolOllama:
+		default:
+			add(false, "provider %q has unknown protocol %q (use anthropic, openai or ollama)", name, pc.Protocol)
 		}
-		if pc.Env == "" {
-			problems = append(problems, fmt.Sprintf("custom provider %q has no env var set (key must be in keyring)", name))
+		if pc.Temperature != nil && (*pc.Temperature < 0 || *pc.Temperature > 2) {
+			add(false, "provider %q: temperature must be between 0 and 2", name)
+		}
+		if pc.MaxTokens < 0 || pc.ContextWindow < 0 || pc.Timeout < 0 || pc.DialTimeout < 0 || pc.HeaderTimeout < 0 {
+			add(false, "provider %q: token limits and timeouts must not be negative", name)
+		}
+		if pc.Proxy != "" {
+			if u, err := url.Parse(pc.Proxy); err != nil || u.Host == "" {
+				add(false, "provider %q: invalid proxy %q (e.g. http://proxy.corp:3128)", name, pc.Proxy)
+			} else if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5" {
+				add(false, "provider %q: proxy scheme must be http, https or socks5", name)
+			}
+		}
+		if (pc.ClientCert == "") != (pc.ClientKey == "") {
+			add(false, "provider %q: client_cert and client_key must be set together", name)
 		}
 	}
 
+	for model, p := range c.Pricing {
+		if p.Input < 0 || p.Output < 0 {
+			add(false, "pricing for %q must not be negative", model)
+		}
+	}
+	hosts := make([]string, 0, len(c.Forges))
+	for host := range c.Forges {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		fc := c.Forges[host]
+		if !slices.Contains(ForgeTypes, fc.Type) {
+			add(false, "forge %q: type must be one of %s", host, strings.Join(ForgeTypes, ", "))
+		}
+		if fc.APIURL != "" {
+			if u, err := url.Parse(fc.APIURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+				add(false, "forge %q: invalid api_url %q", host, fc.APIURL)
+			}
+		}
+	}
+
+	if c.PR.SuggestReviewers < 0 {
+		add(false, "pr.suggest_reviewers must not be negative")
+	}
+	if c.PR.MergeStrategy != "" && !slices.Contains(MergeStrategies, c.PR.MergeStrategy) {
+		add(false, "pr.merge_strategy must be one of %s", strings.Join(MergeStrategies, ", "))
+	}
+	if c.Review.BlockOn != "" && !slices.Contains(ReviewBlockLevels, c.Review.BlockOn) {
+		add(false, "review.block_on must be one of %s", strings.Join(ReviewBlockLevels, ", "))
+	}
+
+	keyProviders := make([]string, 0, len(c.Keys))
+	for name := range c.Keys {
+		keyProviders = append(keyProviders, name)
+	}
+	sort.Strings(keyProviders)
+	for _, name := range keyProviders {
+		slot := c.Keys[name]
+		if err := keyring.ValidSlotName(slot); err != nil {
+			add(false, "keys.%s: %v", name, err)
+		}
+	}
+	for _, w := range c.warnings {
+		add(true, "%s", w)
+	}
+
 	return problems
 }
 
-// CustomEnvs returns a map of provider name to env var name.
-// Includes registry providers' env vars, overridden by custom config.
-func (c Config) CustomEnvs() map[string]string {
-	envs := make(map[string]string)
+// KeyLookups returns the key sources (env var, key_cmd, slot) of every provider.
+// Includes registry providers' env vars, overridden by [providers.*] and [keys].
+func (c Config) KeyLookups() map[string]keyring.Lookup {
+	lookups := make(map[string]keyring.Lookup)
 	for name, entry := range Registry {
 		if entry.DefaultEnv != "" {
-			envs[name] = entry.DefaultEnv
+			lookups[name] = keyring.Lookup{Env: entry.DefaultEnv}
 		}
 	}
-	for name, pc := range c.Custom {
+	for name, pc := range c.Providers {
+		l := lookups[name]
 		if pc.Env != "" {
-			envs[name] = pc.Env
+			l.Env = pc.Env
 		}
+		l.Cmd = pc.KeyCmd
+		if l != (keyring.Lookup{}) {
+			lookups[name] = l
+		}
+	}
+	for name, slot := range c.Keys {
+		l := lookups[name]
+		l.Slot = slot
+		lookups[name] = l
+	}
+	return lookups
+}
+
+func sortedProviderNames(m map[string]ProviderConfig) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
 	}
-	return envs
+	sort.Strings(names)
+	return names
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -13,17 +14,17 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.Provider != "auto" {
 		t.Errorf("Provider = %q, want \"auto\"", cfg.Provider)
 	}
-	if cfg.Anthropic.Model != "claude-haiku-4-5-20251001" {
-		t.Errorf("Anthropic.Model = %q", cfg.Anthropic.Model)
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, CurrentVersion)
 	}
-	if cfg.OpenAI.Model != "gpt-4o-mini" {
-		t.Errorf("OpenAI.Model = %q", cfg.OpenAI.Model)
-	}
-	if cfg.Ollama.Model != "llama3" {
-		t.Errorf("Ollama.Model = %q", cfg.Ollama.Model)
+	// Builtins resolve to registry defaults without any [providers] entries.
+	for name, want := range map[string]string{"anthropic": "claude-haiku-4-5-20251001", "openai": "gpt-4o-mini", "ollama": "llama3"} {
+		if rp, _ := cfg.ResolveProviderFull(name); rp.Model != want {
+			t.Errorf("%s model = %q, want %q", name, rp.Model, want)
+		}
 	}
-	if cfg.Ollama.URL != Registry["ollama"].DefaultURL {
-		t.Errorf("Ollama.URL = %q, want %q", cfg.Ollama.URL, Registry["ollama"].DefaultURL)
+	if rp, _ := cfg.ResolveProviderFull("ollama"); rp.URL != Registry["ollama"].DefaultURL {
+		t.Errorf("ollama URL = %q, want %q", rp.URL, Registry["ollama"].DefaultURL)
 	}
 }
 
@@ -51,9 +52,9 @@ func TestDefaultModel(t *testing.T) {
 }
 
 func TestResolveProviderFull(t *testing.T) {
-	t.Run("builtin with struct overrides", func(t *testing.T) {
+	t.Run("builtin with overrides", func(t *testing.T) {
 		cfg := DefaultConfig()
-		cfg.Anthropic.Model = "claude-opus-4-6"
+		cfg.Providers = map[string]ProviderConfig{"anthropic": {Model: "claude-opus-4-6"}}
 		rp, ok := cfg.ResolveProviderFull("anthropic")
 		if !ok {
 			t.Fatal("returned false")
@@ -86,9 +87,9 @@ func TestResolveProviderFull(t *testing.T) {
 		}
 	})
 
-	t.Run("custom overrides registry", func(t *testing.T) {
+	t.Run("providers entry overrides registry", func(t *testing.T) {
 		cfg := DefaultConfig()
-		cfg.Custom = map[string]ProviderConfig{
+		cfg.Providers = map[string]ProviderConfig{
 			"groq": {Model: "custom-model"},
 		}
 		rp, ok := cfg.ResolveProviderFull("groq")
@@ -120,7 +121,7 @@ func TestResolveProviderFull(t *testing.T) {
 
 	t.Run("purely custom defaults to openai protocol", func(t *testing.T) {
 		cfg := DefaultConfig()
-		cfg.Custom = map[string]ProviderConfig{
+		cfg.Providers = map[string]ProviderConfig{
 			"together": {Model: "llama-70b", URL: "https://api.together.xyz/v1", Env: "TOGETHER_API_KEY"},
 		}
 		rp, ok := cfg.ResolveProviderFull("together")
@@ -135,6 +136,33 @@ func TestResolveProviderFull(t *testing.T) {
 		}
 	})
 
+	t.Run("protocol and request options", func(t *testing.T) {
+		temp := 0.2
+		cfg := DefaultConfig()
+		cfg.Providers = map[string]ProviderConfig{
+			"proxy": {
+				URL:           "https://llm.internal/v1",
+				Protocol:      ProtocolAnthropic,
+				Headers:       map[string]string{"X-Team": "core"},
+				Temperature:   &temp,
+				MaxTokens:     512,
+				Timeout:       90 * time.Second,
+				ContextWindow: 32000,
+			},
+		}
+		rp, ok := cfg.ResolveProviderFull("proxy")
+		if !ok {
+			t.Fatal("returned false")
+		}
+		if rp.Protocol != ProtocolAnthropic {
+			t.Errorf("Protocol = %q, want anthropic", rp.Protocol)
+		}
+		if rp.Headers["X-Team"] != "core" || rp.Temperature == nil || *rp.Temperature != 0.2 ||
+			rp.MaxTokens != 512 || rp.Timeout != 90*time.Second || rp.ContextWindow != 32000 {
+			t.Errorf("options not resolved: %+v", rp)
+		}
+	})
+
 	t.Run("unknown provider returns false", func(t *testing.T) {
 		cfg := DefaultConfig()
 		_, ok := cfg.ResolveProviderFull("nonexistent")
@@ -145,51 +173,25 @@ func TestResolveProviderFull(t *testing.T) {
 }
 
 func TestSetModel(t *testing.T) {
-	t.Run("builtin anthropic", func(t *testing.T) {
-		cfg := DefaultConfig()
-		cfg.SetModel("anthropic", "claude-opus-4-6")
-		if cfg.Anthropic.Model != "claude-opus-4-6" {
-			t.Errorf("Anthropic.Model = %q", cfg.Anthropic.Model)
-		}
-	})
-
-	t.Run("builtin openai", func(t *testing.T) {
-		cfg := DefaultConfig()
-		cfg.SetModel("openai", "gpt-4o")
-		if cfg.OpenAI.Model != "gpt-4o" {
-			t.Errorf("OpenAI.Model = %q", cfg.OpenAI.Model)
-		}
-	})
-
-	t.Run("builtin ollama", func(t *testing.T) {
-		cfg := DefaultConfig()
-		cfg.SetModel("ollama", "mistral")
-		if cfg.Ollama.Model != "mistral" {
-			t.Errorf("Ollama.Model = %q", cfg.Ollama.Model)
-		}
-	})
+	for _, name := range []string{"anthropic", "groq", "together"} {
+		t.Run(name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.SetModel(name, "some-model")
+			if got := cfg.Providers[name].Model; got != "some-model" {
+				t.Errorf("Providers[%s].Model = %q", name, got)
+			}
+			if rp, _ := cfg.ResolveProviderFull(name); rp.Model != "some-model" {
+				t.Errorf("resolved model = %q", rp.Model)
+			}
+		})
+	}
 
-	t.Run("registry provider goes to custom", func(t *testing.T) {
+	t.Run("registry defaults still apply", func(t *testing.T) {
 		cfg := DefaultConfig()
 		cfg.SetModel("groq", "llama-custom")
-		pc := cfg.Custom["groq"]
-		if pc.Model != "llama-custom" {
-			t.Errorf("Custom[groq].Model = %q", pc.Model)
-		}
-		if pc.URL != "https://api.groq.com/openai/v1" {
-			t.Errorf("Custom[groq].URL = %q", pc.URL)
-		}
-		if pc.Env != "GROQ_API_KEY" {
-			t.Errorf("Custom[groq].Env = %q", pc.Env)
-		}
-	})
-
-	t.Run("purely custom provider", func(t *testing.T) {
-		cfg := DefaultConfig()
-		cfg.SetModel("together", "llama-70b")
-		pc := cfg.Custom["together"]
-		if pc.Model != "llama-70b" {
-			t.Errorf("Custom[together].Model = %q", pc.Model)
+		rp, _ := cfg.ResolveProviderFull("groq")
+		if rp.URL != "https://api.groq.com/openai/v1" || rp.Env != "GROQ_API_KEY" {
+			t.Errorf("URL = %q, Env = %q", rp.URL, rp.Env)
 		}
 	})
 }
@@ -221,7 +223,7 @@ func TestValidate(t *testing.T) {
 
 	t.Run("custom missing url", func(t *testing.T) {
 		cfg := DefaultConfig()
-		cfg.Custom = map[string]ProviderConfig{
+		cfg.Providers = map[string]ProviderConfig{
 			"myapi": {Model: "test", Env: "MY_KEY"},
 		}
 		problems := cfg.Validate()
@@ -238,7 +240,7 @@ func TestValidate(t *testing.T) {
 
 	t.Run("custom missing env", func(t *testing.T) {
 		cfg := DefaultConfig()
-		cfg.Custom = map[string]ProviderConfig{
+		cfg.Providers = map[string]ProviderConfig{
 			"myapi": {Model: "test", URL: "https://example.com"},
 		}
 		problems := cfg.Validate()
@@ -253,9 +255,9 @@ func TestValidate(t *testing.T) {
 		}
 	})
 
-	t.Run("registry override in custom is fine", func(t *testing.T) {
+	t.Run("registry override is fine", func(t *testing.T) {
 		cfg := DefaultConfig()
-		cfg.Custom = map[string]ProviderConfig{
+		cfg.Providers = map[string]ProviderConfig{
 			"groq": {Model: "custom-model"},
 		}
 		problems := cfg.Validate()
@@ -263,6 +265,79 @@ func TestValidate(t *testing.T) {
 			t.Errorf("unexpected problems for registry override: %v", problems)
 		}
 	})
+
+	t.Run("invalid request options", func(t *testing.T) {
+		hot := 3.0
+		cfg := DefaultConfig()
+		cfg.Providers = map[string]ProviderConfig{
+			"groq": {Protocol: "grpc", Temperature: &hot, MaxTokens: -1},
+		}
+		if got := len(cfg.Errors()); got != 3 {
+			t.Errorf("Errors() = %v, want protocol, temperature and max_tokens errors", cfg.Errors())
+		}
+	})
+
+	t.Run("invalid transport options", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Providers = map[string]ProviderConfig{
+			"openai": {Proxy: "ftp://proxy", ClientCert: "/tmp/cert.pem"},
+			"groq":   {CAFile: "/nonexistent/ca.pem", DialTimeout: -time.Second},
+		}
+		for _, want := range []string{"proxy scheme", "set together", "must not be negative"} {
+			found := false
+			for _, p := range cfg.Errors() {
+				if strings.Contains(p, want) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected %q error, got: %v", want, cfg.Errors())
+			}
+		}
+		// Whether the files exist is up to yeet doctor, not validation.
+		for _, p := range cfg.Validate() {
+			if strings.Contains(p, "ca_file") {
+				t.Errorf("Validate read the file system: %v", p)
+			}
+		}
+	})
+
+	t.Run("invalid forges", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Forges = map[string]ForgeConfig{
+			"git.company.io": {Type: "gitlab"},
+			"code.corp":      {Type: "svn"},
+			"ghe.corp":       {Type: "github", APIURL: "ghe.corp/api"},
+		}
+		errs := cfg.Errors()
+		if len(errs) != 2 || !strings.Contains(errs[0], `forge "code.corp": type`) || !strings.Contains(errs[1], "invalid api_url") {
+			t.Errorf("errors = %v", errs)
+		}
+	})
+
+	t.Run("negative suggest_reviewers", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.PR.SuggestReviewers = -1
+		if errs := cfg.Errors(); len(errs) != 1 || !strings.Contains(errs[0], "pr.suggest_reviewers") {
+			t.Errorf("errors = %v", errs)
+		}
+	})
+
+	t.Run("unknown merge_strategy", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.PR.MergeStrategy = "octopus"
+		if errs := cfg.Errors(); len(errs) != 1 || !strings.Contains(errs[0], "pr.merge_strategy") {
+			t.Errorf("errors = %v", errs)
+		}
+	})
+
+	t.Run("unknown review block_on", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Review.BlockOn = "critical"
+		if errs := cfg.Errors(); len(errs) != 1 || !strings.Contains(errs[0], "review.block_on") {
+			t.Errorf("errors = %v", errs)
+		}
+	})
 }
 
 func TestProviders(t *testing.T) {
@@ -307,22 +382,31 @@ output = 0.10
 	}
 }
 
-func TestCustomEnvs(t *testing.T) {
+func TestKeyLookups(t *testing.T) {
 	cfg := DefaultConfig()
-	cfg.Custom = map[string]ProviderConfig{
-		"myapi": {Env: "MY_API_KEY"},
+	cfg.Providers = map[string]ProviderConfig{
+		"myapi":     {Env: "MY_API_KEY"},
+		"anthropic": {KeyCmd: "op read op://dev/anthropic/key"},
 	}
-	envs := cfg.CustomEnvs()
+	cfg.Keys = map[string]string{"openai": "work"}
+	lookups := cfg.KeyLookups()
 
 	// Registry providers should be present
-	if envs["google"] != "GOOGLE_API_KEY" {
-		t.Errorf("google env = %q", envs["google"])
+	if lookups["google"].Env != "GOOGLE_API_KEY" {
+		t.Errorf("google env = %q", lookups["google"].Env)
 	}
-	if envs["anthropic"] != "ANTHROPIC_API_KEY" {
-		t.Errorf("anthropic env = %q", envs["anthropic"])
+	// key_cmd keeps the registry env var as a fallback
+	if l := lookups["anthropic"]; l.Env != "ANTHROPIC_API_KEY" || l.Cmd != "op read op://dev/anthropic/key" {
+		t.Errorf("anthropic lookup = %+v", l)
 	}
 	// Custom provider should be present
-	if envs["myapi"] != "MY_API_KEY" {
-		t.Errorf("myapi env = %q", envs["myapi"])
+	if lookups["myapi"].Env != "MY_API_KEY" {
+		t.Errorf("myapi env = %q", lookups["myapi"].Env)
+	}
+	if l := lookups["openai"]; l.Env != "OPENAI_API_KEY" || l.Slot != "work" {
+		t.Errorf("openai lookup = %+v", l)
+	}
+	if rp, _ := cfg.ResolveProviderFull("anthropic"); rp.KeyLookup() != lookups["anthropic"] {
+		t.Errorf("KeyLookup() = %+v, want %+v", rp.KeyLookup(), lookups["anthropic"])
 	}
 }
@@ -0,0 +1,497 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// document is a line-level view of a TOML file. It can set and delete single
+// keys while leaving comments, ordering and formatting of everything else alone.
+// Constructs it can't edit safely (inline tables, arrays of tables) make the
+// affected edit fail, and Save reports it instead of rewriting the file.
+type document struct {
+	lines   []string
+	entries []docEntry
+	headers []docHeader
+}
+
+// docEntry is a key/value pair, possibly spanning several lines (multi-line arrays/strings).
+type docEntry struct {
+	start, end int      // line range [start, end)
+	table      []string // table the entry lives in
+	key        []string // full key path (table + dotted key)
+	inArray    bool     // inside an [[array of tables]]
+	inline     bool     // value is an inline table
+}
+
+type docHeader struct {
+	line  int
+	path  []string
+	array bool
+}
+
+// keyValue is a flattened leaf, see flattenOrdered.
+type keyValue struct {
+	path  []string
+	value any
+}
+
+func parseDocument(text string) (*document, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	d := &document{}
+	if text != "" {
+		d.lines = strings.Split(text, "\n")
+	}
+	if err := d.index(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// index rebuilds entries and headers from lines.
+func (d *document) index() error {
+	d.entries = nil
+	d.headers = nil
+	var table []string
+	inArray := false
+
+	for i := 0; i < len(d.lines); i++ {
+		trimmed := strings.TrimSpace(d.lines[i])
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+		if trimmed[0] == '[' {
+			array := strings.HasPrefix(trimmed, "[[")
+			inner := trimmed[1:]
+			if array {
+				inner = trimmed[2:]
+			}
+			end := closingBracket(inner)
+			if end < 0 {
+				return fmt.Errorf("line %d: malformed table header", i+1)
+			}
+			path, err := ParseKey(inner[:end])
+			if err != nil {
+				return fmt.Errorf("line %d: %w", i+1, err)
+			}
+			d.headers = append(d.headers, docHeader{line: i, path: path, array: array})
+			table, inArray = path, array
+			continue
+		}
+
+		eq := indexOutsideQuotes(d.lines[i], '=')
+		if eq < 0 {
+			return fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		key, err := ParseKey(d.lines[i][:eq])
+		if err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+		rest := d.lines[i][eq+1:]
+		span, ok := valueSpan(d.lines[i:], rest)
+		if !ok {
+			return fmt.Errorf("line %d: unterminated value", i+1)
+		}
+		d.entries = append(d.entries, docEntry{
+			start:   i,
+			end:     i + span,
+			table:   table,
+			key:     append(append([]string{}, table...), key...),
+			inArray: inArray,
+			inline:  strings.HasPrefix(strings.TrimSpace(rest), "{"),
+		})
+		i += span - 1
+	}
+	return nil
+}
+
+func (d *document) String() string {
+	if len(d.lines) == 0 {
+		return ""
+	}
+	return strings.Join(d.lines, "\n") + "\n"
+}
+
+func (d *document) find(path []string) (docEntry, bool) {
+	for _, e := range d.entries {
+		if equalPath(e.key, path) {
+			return e, true
+		}
+	}
+	return docEntry{}, false
+}
+
+// line returns the 1-based line where path is defined (as key or table header), 0 if not found.
+func (d *document) line(path []string) int {
+	if e, ok := d.find(path); ok {
+		return e.start + 1
+	}
+	for _, h := range d.headers {
+		if equalPath(h.path, path) {
+			return h.line + 1
+		}
+	}
+	return 0
+}
+
+// blocked reports whether path is inside a construct we can't edit line by line.
+func (d *document) blocked(path []string) bool {
+	for _, e := range d.entries {
+		if e.inline && hasPrefix(path, e.key) && len(path) > len(e.key) {
+			return true
+		}
+		if e.inArray && hasPrefix(path, e.table) {
+			return true
+		}
+	}
+	return false
+}
+
+// set writes literal at path, replacing the existing value in place or
+// inserting a new line next to its siblings.
+func (d *document) set(path []string, literal string) error {
+	if d.blocked(path) {
+		return fmt.Errorf("%s is inside an inline table or array of tables", FormatKey(path))
+	}
+
+	if e, ok := d.find(path); ok {
+		if e.inline {
+			return fmt.Errorf("%s is an inline table", FormatKey(path))
+		}
+		line := d.lines[e.start]
+		eq := indexOutsideQuotes(line, '=')
+		prefix := strings.TrimRight(line[:eq+1], " \t") + " "
+		comment := trailingComment(d.lines[e.end-1], e.start == e.end-1, eq)
+		d.replace(e.start, e.end, prefix+literal+comment)
+		return d.index()
+	}
+
+	parent := path[:len(path)-1]
+
+	// Insert after the last entry that already lives under the parent table,
+	// expressing the key relative to that entry's table.
+	for i := len(d.entries) - 1; i >= 0; i-- {
+		e := d.entries[i]
+		if e.inArray || !equalPath(e.key[:len(e.key)-1], parent) {
+			continue
+		}
+		indent := leadingSpace(d.lines[e.start])
+		d.insert(e.end, indent+FormatKey(path[len(e.table):])+" = "+literal)
+		return d.index()
+	}
+
+	// Empty table header for the parent.
+	for _, h := range d.headers {
+		if !h.array && equalPath(h.path, parent) {
+			indent := leadingSpace(d.lines[h.line])
+			d.insert(h.line+1, indent+FormatKey(path[len(parent):])+" = "+literal)
+			return d.index()
+		}
+	}
+
+	// Top-level key: place it before the first table header.
+	if len(parent) == 0 {
+		at := len(d.lines)
+		if len(d.headers) > 0 {
+			at = d.headers[0].line
+			for at > 0 && strings.TrimSpace(d.lines[at-1]) == "" {
+				at--
+			}
+		}
+		d.insert(at, FormatKey(path)+" = "+literal)
+		return d.index()
+	}
+
+	// New table at the end of the file.
+	var add []string
+	if len(d.lines) > 0 && strings.TrimSpace(d.lines[len(d.lines)-1]) != "" {
+		add = append(add, "")
+	}
+	add = append(add, "["+FormatKey(parent)+"]", FormatKey(path[len(parent):])+" = "+literal)
+	d.insert(len(d.lines), add...)
+	return d.index()
+}
+
+// remove deletes the key at path. A table header left without entries by the
+// removal is dropped too, unless it carries comments.
+func (d *document) remove(path []string) error {
+	if d.blocked(path) {
+		return fmt.Errorf("%s is inside an inline table or array of tables", FormatKey(path))
+	}
+	e, ok := d.find(path)
+	if !ok {
+		return nil
+	}
+	d.replace(e.start, e.end)
+	if err := d.index(); err != nil {
+		return err
+	}
+	d.dropEmptyTable(e.table)
+	return nil
+}
+
+// dropEmptyTable removes the header of the table at path if no entries or
+// comments remain under it, leaving one blank line before whatever follows.
+func (d *document) dropEmptyTable(path []string) {
+	for _, h := range d.headers {
+		if h.array || !equalPath(h.path, path) {
+			continue
+		}
+		end := len(d.lines)
+		for _, other := range d.headers {
+			if other.line > h.line {
+				end = other.line
+				break
+			}
+		}
+		for i := h.line + 1; i < end; i++ {
+			if strings.TrimSpace(d.lines[i]) != "" {
+				return // entries or comments remain
+			}
+		}
+		d.replace(h.line, end)
+		at := h.line
+		for at > 0 && strings.TrimSpace(d.lines[at-1]) == "" {
+			d.replace(at-1, at)
+			at--
+		}
+		if at > 0 && at < len(d.lines) {
+			d.insert(at, "")
+		}
+		d.index()
+		return
+	}
+}
+
+func (d *document) replace(start, end int, with ...string) {
+	lines := append([]string{}, d.lines[:start]...)
+	lines = append(lines, with...)
+	d.lines = append(lines, d.lines[end:]...)
+}
+
+func (d *document) insert(at int, with ...string) {
+	d.replace(at, at, with...)
+}
+
+// applyChanges edits doc so that the values of old become those of new.
+// Keys only present in old are removed; unknown keys in the file are untouched.
+// New keys whose value matches defaults are skipped to keep the file minimal.
+func (d *document) applyChanges(old, new, defaults []keyValue) error {
+	defaultByKey := make(map[string]string, len(defaults))
+	for _, kv := range defaults {
+		defaultByKey[FormatKey(kv.path)] = tomlLiteral(kv.value)
+	}
+	oldByKey := make(map[string]string, len(old))
+	for _, kv := range old {
+		oldByKey[FormatKey(kv.path)] = tomlLiteral(kv.value)
+	}
+	newKeys := make(map[string]bool, len(new))
+	for _, kv := range new {
+		k := FormatKey(kv.path)
+		newKeys[k] = true
+		literal := tomlLiteral(kv.value)
+		prev, ok := oldByKey[k]
+		if ok && prev == literal || !ok && defaultByKey[k] == literal {
+			continue
+		}
+		if err := d.set(kv.path, literal); err != nil {
+			return err
+		}
+	}
+	for _, kv := range old {
+		if !newKeys[FormatKey(kv.path)] {
+			if err := d.remove(kv.path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tomlLiteral renders a leaf value as a TOML literal.
+func tomlLiteral(v any) string {
+	switch v := v.(type) {
+	case string:
+		return tomlString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		s := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.ContainsAny(s, ".eE") {
+			s += ".0"
+		}
+		return s
+	case []string:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = tomlString(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = tomlLiteral(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return tomlString(fmt.Sprint(v))
+	}
+}
+
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// valueSpan returns how many lines the value starting with rest occupies.
+// It tracks strings (incl. multi-line) and bracket depth across lines.
+func valueSpan(lines []string, rest string) (int, bool) {
+	const (
+		normal = iota
+		basic
+		literal
+		mlBasic
+		mlLiteral
+	)
+	state, depth := normal, 0
+	text := rest
+	for n := 0; n < len(lines); n++ {
+		if n > 0 {
+			text = lines[n]
+		}
+		for i := 0; i < len(text); i++ {
+			c := text[i]
+			switch state {
+			case normal:
+				switch {
+				case strings.HasPrefix(text[i:], `"""`):
+					state, i = mlBasic, i+2
+				case strings.HasPrefix(text[i:], `'''`):
+					state, i = mlLiteral, i+2
+				case c == '"':
+					state = basic
+				case c == '\'':
+					state = literal
+				case c == '[' || c == '{':
+					depth++
+				case c == ']' || c == '}':
+					depth--
+				case c == '#':
+					i = len(text)
+				}
+			case basic:
+				if c == '\\' {
+					i++
+				} else if c == '"' {
+					state = normal
+				}
+			case literal:
+				if c == '\'' {
+					state = normal
+				}
+			case mlBasic:
+				if c == '\\' {
+					i++
+				} else if strings.HasPrefix(text[i:], `"""`) {
+					state, i = normal, i+2
+				}
+			case mlLiteral:
+				if strings.HasPrefix(text[i:], `'''`) {
+					state, i = normal, i+2
+				}
+			}
+		}
+		if state == basic || state == literal {
+			return 0, false
+		}
+		if state == normal && depth <= 0 {
+			return n + 1, true
+		}
+	}
+	return 0, false
+}
+
+// trailingComment returns " # ..." from the last line of a value, if any.
+// For single-line values the search starts after the '=' at eq.
+func trailingComment(line string, single bool, eq int) string {
+	from := 0
+	if single {
+		from = eq + 1
+	}
+	if i := indexOutsideQuotes(line[from:], '#'); i >= 0 {
+		return " " + strings.TrimSpace(line[from+i:])
+	}
+	return ""
+}
+
+// indexOutsideQuotes finds the first c in s that isn't inside a quoted string.
+func indexOutsideQuotes(s string, c byte) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == c:
+			return i
+		}
+	}
+	return -1
+}
+
+func closingBracket(s string) int {
+	return indexOutsideQuotes(s, ']')
+}
+
+func leadingSpace(s string) string {
+	return s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+}
+
+func equalPath(a, b []string) bool {
+	return len(a) == len(b) && hasPrefix(a, b)
+}
+
+func hasPrefix(path, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
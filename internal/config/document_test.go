@@ -0,0 +1,195 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const handWritten = `# my yeet config
+version = 2
+provider = "anthropic" # main one
+
+[providers.anthropic]
+  # pinned for stability
+  model = "claude-opus-4-6"
+
+[providers.together]
+url = "https://api.together.xyz/v1"   # endpoint
+env = "TOGETHER_KEY"
+
+[pricing."meta/llama"]
+input = 1.0
+output = 2.0
+
+[profiles.work]
+remotes = [
+  "github.com/acme/*", # work org
+]
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := configPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSavePreservesComments(t *testing.T) {
+	path := writeConfig(t, handWritten)
+
+	cfg, err := LoadFile()
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	cfg.Provider = "groq"
+	pc := cfg.Providers["together"]
+	pc.Model = "llama"
+	cfg.Providers["together"] = pc
+	delete(cfg.Pricing, "meta/llama")
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, _ := os.ReadFile(path)
+	want := `# my yeet config
+version = 2
+provider = "groq" # main one
+
+[providers.anthropic]
+  # pinned for stability
+  model = "claude-opus-4-6"
+
+[providers.together]
+url = "https://api.together.xyz/v1"   # endpoint
+env = "TOGETHER_KEY"
+model = "llama"
+
+[profiles.work]
+remotes = [
+  "github.com/acme/*", # work org
+]
+`
+	if string(got) != want {
+		t.Errorf("Save wrote:\n%s\nwant:\n%s", got, want)
+	}
+
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil || string(backup) != handWritten {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+}
+
+func TestSaveUnchangedIsNoop(t *testing.T) {
+	path := writeConfig(t, handWritten)
+	cfg, err := LoadFile()
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	if string(got) != handWritten {
+		t.Errorf("Save changed an unmodified config:\n%s", got)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Error("backup written for a no-op save")
+	}
+}
+
+func TestSaveNewTables(t *testing.T) {
+	path := writeConfig(t, "version = 2\nprovider = \"auto\"\n")
+	cfg, err := LoadFile()
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if err := cfg.Set(`pricing."a.b".input`, "0.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Set("profiles.work.push", "false"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, _ := os.ReadFile(path)
+	for _, want := range []string{"[pricing.\"a.b\"]\ninput = 0.5\noutput = 0.0\n", "[profiles.work]\npush = false\n"} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(string(got), "[ollama]") {
+		t.Errorf("default values were written:\n%s", got)
+	}
+
+	back, err := LoadFile()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if back.Pricing["a.b"].Input != 0.5 || back.Profiles["work"].Push == nil {
+		t.Errorf("reloaded config lost values: %+v", back)
+	}
+}
+
+func TestSaveRefusesToReencode(t *testing.T) {
+	const inline = "# mine\nversion = 2\nproviders = { together = { url = \"https://a\" } }\n"
+	path := writeConfig(t, inline)
+	cfg, err := LoadFile()
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if err := cfg.Set("providers.together.url", "https://b"); err != nil {
+		t.Fatal(err)
+	}
+	err = Save(cfg)
+	if err == nil || !strings.Contains(err.Error(), "inline table") {
+		t.Fatalf("Save = %v, want an error naming the inline table", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != inline {
+		t.Errorf("file changed after a failed save:\n%s", data)
+	}
+
+	// A blank file has nothing to lose and is written from scratch.
+	writeConfig(t, "\n")
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save(blank): %v", err)
+	}
+	back, err := LoadFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Providers["together"].URL != "https://b" {
+		t.Errorf("url = %q", back.Providers["together"].URL)
+	}
+}
+
+func TestValueSpan(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  int
+		ok    bool
+	}{
+		{[]string{` "a # b" # c`}, 1, true},
+		{[]string{` [`, `  "x", # ]`, `]`}, 3, true},
+		{[]string{` """`, `multi`, `"""`}, 3, true},
+		{[]string{` "unterminated`}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := valueSpan(tt.lines, tt.lines[0])
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("valueSpan(%q) = %d, %v; want %d, %v", tt.lines, got, ok, tt.want, tt.ok)
+		}
+	}
+}
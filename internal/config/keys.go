@@ -0,0 +1,420 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Get returns the value at a dotted key like "providers.together.url".
+// Tables (structs and maps) are returned as flattened key/value maps.
+func (c Config) Get(key string) (any, error) {
+	path, err := ParseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.ValueOf(c)
+	for i, part := range path {
+		if isLeaf(v) {
+			return nil, fmt.Errorf("%s: unknown key", key)
+		}
+		next, err := child(v, part)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", FormatKey(path[:i+1]), err)
+		}
+		v = next
+	}
+	if isLeaf(v) {
+		if isEmptyLeaf(v) {
+			return nil, fmt.Errorf("%s: not set", key)
+		}
+		return leafValue(v), nil
+	}
+	out := make(map[string]any)
+	flatten(v, path, out)
+	return out, nil
+}
+
+// Set parses value according to the type of the field at key and stores it.
+// Missing map entries (e.g. a new custom provider) are created on the way.
+func (c *Config) Set(key, value string) error {
+	path, err := ParseKey(key)
+	if err != nil {
+		return err
+	}
+	err = setPath(reflect.ValueOf(c).Elem(), path, func(v reflect.Value) error {
+		if !isLeaf(v) {
+			return fmt.Errorf("is a table — set one of its keys instead")
+		}
+		return parseInto(v, value)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// Unset resets the value at key to its zero value, or removes a map entry
+// (e.g. "providers.together" or "pricing.my-model").
+func (c *Config) Unset(key string) error {
+	path, err := ParseKey(key)
+	if err != nil {
+		return err
+	}
+	// Check first so walking the path doesn't create empty map entries.
+	if _, err := c.Get(key); err != nil {
+		return err
+	}
+	parent, last := path[:len(path)-1], path[len(path)-1]
+	err = setPath(reflect.ValueOf(c).Elem(), parent, func(v reflect.Value) error {
+		if v.Kind() == reflect.Map {
+			v.SetMapIndex(reflect.ValueOf(last), reflect.Value{})
+			return nil
+		}
+		f, err := child(v, last)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// Flatten returns all non-empty leaf values keyed by their dotted key.
+func (c Config) Flatten() map[string]any {
+	out := make(map[string]any)
+	flatten(reflect.ValueOf(c), nil, out)
+	return out
+}
+
+// SortedKeys returns the keys of a flattened map in a stable order.
+func SortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FormatValue renders a leaf value the way `yeet config get` prints it.
+func FormatValue(v any) string {
+	switch v := v.(type) {
+	case []string:
+		return strings.Join(v, ",")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// envOverrides maps YEET_* environment variables onto config keys.
+// YEET_MODEL is handled separately since its key depends on the provider.
+var envOverrides = []struct {
+	env string
+	key string
+}{
+	{"YEET_PROVIDER", "provider"},
+	{"YEET_PROMPT", "prompt"},
+	{"YEET_PUSH", "push"},
+}
+
+// applyEnv layers YEET_* environment overrides on top of the config.
+// Invalid values are kept as warnings for Validate instead of failing Load.
+func (c *Config) applyEnv() {
+	for _, o := range envOverrides {
+		value, ok := os.LookupEnv(o.env)
+		if !ok || value == "" {
+			continue
+		}
+		if err := c.Set(o.key, value); err != nil {
+			c.warnings = append(c.warnings, fmt.Sprintf("ignoring %s: %v", o.env, err))
+		}
+	}
+
+	if model := os.Getenv("YEET_MODEL"); model != "" {
+		if c.Provider == "" || c.Provider == "auto" {
+			c.warnings = append(c.warnings, "ignoring YEET_MODEL: provider is auto — set YEET_PROVIDER too")
+		} else {
+			c.SetModel(c.Provider, model)
+		}
+	}
+}
+
+// EnvOverrides lists the supported YEET_* variables with their config keys.
+func EnvOverrides() map[string]string {
+	out := map[string]string{
+		"YEET_MODEL":   "providers.<provider>.model",
+		"YEET_PROFILE": "profile selection",
+	}
+	for _, o := range envOverrides {
+		out[o.env] = o.key
+	}
+	return out
+}
+
+// ParseKey splits a dotted key into parts, honoring TOML quoting:
+// pricing."meta-llama/Llama-3-70b" → [pricing meta-llama/Llama-3-70b].
+func ParseKey(key string) ([]string, error) {
+	var parts []string
+	s := strings.TrimSpace(key)
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return nil, fmt.Errorf("invalid key %q", key)
+		}
+		var part string
+		switch s[0] {
+		case '"', '\'':
+			end := strings.IndexByte(s[1:], s[0])
+			if end < 0 {
+				return nil, fmt.Errorf("invalid key %q: unterminated quote", key)
+			}
+			part, s = s[1:end+1], s[end+2:]
+		default:
+			end := strings.IndexAny(s, ". \t")
+			if end < 0 {
+				end = len(s)
+			}
+			part, s = s[:end], s[end:]
+			if part == "" {
+				return nil, fmt.Errorf("invalid key %q", key)
+			}
+		}
+		parts = append(parts, part)
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return parts, nil
+		}
+		if s[0] != '.' {
+			return nil, fmt.Errorf("invalid key %q", key)
+		}
+		s = s[1:]
+	}
+}
+
+// FormatKey joins key parts, quoting those that aren't bare TOML keys.
+func FormatKey(parts []string) string {
+	out := make([]string, len(parts))
+	for i, p := range parts {
+		if isBareKey(p) {
+			out[i] = p
+		} else {
+			out[i] = tomlString(p)
+		}
+	}
+	return strings.Join(out, ".")
+}
+
+func isBareKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// tomlName returns the TOML key of a struct field, or "" if it isn't serialized.
+func tomlName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	tag := f.Tag.Get("toml")
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// omitEmpty reports whether a field's zero value is left out of the file.
+func omitEmpty(f reflect.StructField) bool {
+	_, opts, _ := strings.Cut(f.Tag.Get("toml"), ",")
+	return strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero")
+}
+
+// child returns the struct field or map entry named part.
+func child(v reflect.Value, part string) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if tomlName(t.Field(i)) == part {
+				return v.Field(i), nil
+			}
+		}
+	case reflect.Map:
+		if e := v.MapIndex(reflect.ValueOf(part)); e.IsValid() {
+			return e, nil
+		}
+		return reflect.Value{}, fmt.Errorf("not set")
+	}
+	return reflect.Value{}, fmt.Errorf("unknown key")
+}
+
+// setPath walks path from an addressable value and calls fn on the target.
+// Map entries are copied out, modified and written back since they aren't addressable.
+func setPath(v reflect.Value, path []string, fn func(reflect.Value) error) error {
+	if len(path) == 0 {
+		return fn(v)
+	}
+	part := path[0]
+	switch v.Kind() {
+	case reflect.Struct:
+		f, err := child(v, part)
+		if err != nil {
+			return err
+		}
+		return setPath(f, path[1:], fn)
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		k := reflect.ValueOf(part)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(k); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := setPath(elem, path[1:], fn); err != nil {
+			return err
+		}
+		v.SetMapIndex(k, elem)
+		return nil
+	default:
+		return fmt.Errorf("unknown key")
+	}
+}
+
+// isLeaf reports whether v is a scalar-like value rather than a table.
+func isLeaf(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Struct:
+		return false
+	case reflect.Map:
+		return false
+	}
+	return true
+}
+
+func leafValue(v reflect.Value) any {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		return v.Elem().Interface()
+	}
+	return v.Interface()
+}
+
+func isEmptyLeaf(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice:
+		return v.Len() == 0
+	case reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+func flatten(v reflect.Value, prefix []string, out map[string]any) {
+	var leaves []keyValue
+	flattenOrdered(v, prefix, &leaves)
+	for _, kv := range leaves {
+		out[FormatKey(kv.path)] = kv.value
+	}
+}
+
+// flattenOrdered collects all non-empty leaves in struct field order, with map keys sorted.
+func flattenOrdered(v reflect.Value, prefix []string, out *[]keyValue) {
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := tomlName(t.Field(i))
+			if name == "" || omitEmpty(t.Field(i)) && v.Field(i).IsZero() {
+				continue
+			}
+			flattenOrdered(v.Field(i), append(append([]string{}, prefix...), name), out)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			flattenOrdered(v.MapIndex(k), append(append([]string{}, prefix...), k.String()), out)
+		}
+	default:
+		if !isEmptyLeaf(v) {
+			*out = append(*out, keyValue{path: prefix, value: leafValue(v)})
+		}
+	}
+}
+
+// parseInto parses s according to the kind of v and stores it.
+func parseInto(v reflect.Value, s string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q (e.g. 30s, 2m)", s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q (use true or false)", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported list type")
+		}
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := parseInto(elem.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(elem)
+	default:
+		return fmt.Errorf("unsupported value type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		want    []string
+		wantErr bool
+	}{
+		{"provider", []string{"provider"}, false},
+		{"providers.together.url", []string{"providers", "together", "url"}, false},
+		{`pricing."meta-llama/Llama-3".input`, []string{"pricing", "meta-llama/Llama-3", "input"}, false},
+		{`pricing.'a.b'`, []string{"pricing", "a.b"}, false},
+		{"providers..url", nil, true},
+		{`pricing."open`, nil, true},
+		{"", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseKey(tt.key)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseKey(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestFormatKey(t *testing.T) {
+	got := FormatKey([]string{"pricing", "meta-llama/Llama-3", "input"})
+	if got != `pricing."meta-llama/Llama-3".input` {
+		t.Errorf("FormatKey() = %q", got)
+	}
+}
+
+func TestSetGetUnset(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if err := cfg.Set("providers.together.url", "https://api.together.xyz/v1"); err != nil {
+		t.Fatalf("Set url: %v", err)
+	}
+	if err := cfg.Set("push", "false"); err != nil {
+		t.Fatalf("Set push: %v", err)
+	}
+	if err := cfg.Set(`pricing."my/model".input`, "0.5"); err != nil {
+		t.Fatalf("Set pricing: %v", err)
+	}
+	if err := cfg.Set("profiles.work.remotes", "github.com/acme/*, gitlab.com/acme/*"); err != nil {
+		t.Fatalf("Set remotes: %v", err)
+	}
+
+	if got, _ := cfg.Get("providers.together.url"); got != "https://api.together.xyz/v1" {
+		t.Errorf("Get url = %v", got)
+	}
+	if cfg.PushEnabled() {
+		t.Error("push should be disabled")
+	}
+	if cfg.Pricing["my/model"].Input != 0.5 {
+		t.Errorf("pricing = %v", cfg.Pricing)
+	}
+	if got := cfg.Profiles["work"].Remotes; len(got) != 2 || got[1] != "gitlab.com/acme/*" {
+		t.Errorf("remotes = %q", got)
+	}
+
+	table, err := cfg.Get("providers.together")
+	if err != nil {
+		t.Fatalf("Get table: %v", err)
+	}
+	if m, ok := table.(map[string]any); !ok || m["providers.together.url"] == nil {
+		t.Errorf("Get table = %v", table)
+	}
+
+	if err := cfg.Unset("providers.together"); err != nil {
+		t.Fatalf("Unset: %v", err)
+	}
+	if _, ok := cfg.Providers["together"]; ok {
+		t.Error("providers.together still present after Unset")
+	}
+	if err := cfg.Unset("push"); err != nil {
+		t.Fatalf("Unset push: %v", err)
+	}
+	if cfg.Push != nil {
+		t.Error("push still set after Unset")
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	cfg := DefaultConfig()
+	tests := []struct {
+		key, value, want string
+	}{
+		{"push", "maybe", "invalid boolean"},
+		{"pricing.x.input", "cheap", "invalid number"},
+		{"nope", "1", "unknown key"},
+		{"providers.together.nope", "1", "unknown key"},
+		{"providers.anthropic", "x", "is a table"},
+	}
+	for _, tt := range tests {
+		err := cfg.Set(tt.key, tt.value)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Set(%q, %q) error = %v, want %q", tt.key, tt.value, err, tt.want)
+		}
+	}
+}
+
+func TestUnsetMissingDoesNotCreate(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Unset("providers.ghost.url"); err == nil {
+		t.Error("expected error for missing key")
+	}
+	if _, ok := cfg.Providers["ghost"]; ok {
+		t.Error("Unset created an empty map entry")
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Providers = map[string]ProviderConfig{"together": {URL: "https://x"}}
+	flat := cfg.Flatten()
+
+	if flat["provider"] != "auto" {
+		t.Errorf("provider = %v", flat["provider"])
+	}
+	if flat["providers.together.url"] != "https://x" {
+		t.Errorf("providers.together.url = %v", flat["providers.together.url"])
+	}
+	if _, ok := flat["providers.together.env"]; ok {
+		t.Error("empty values should be omitted")
+	}
+	if _, ok := flat["push"]; ok {
+		t.Error("nil pointers should be omitted")
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("YEET_PROVIDER", "groq")
+	t.Setenv("YEET_MODEL", "llama-3.1-8b-instant")
+	t.Setenv("YEET_PUSH", "nah")
+
+	cfg := DefaultConfig()
+	cfg.applyEnv()
+
+	if cfg.Provider != "groq" {
+		t.Errorf("Provider = %q", cfg.Provider)
+	}
+	if rp, _ := cfg.ResolveProviderFull("groq"); rp.Model != "llama-3.1-8b-instant" {
+		t.Errorf("groq model = %q", rp.Model)
+	}
+	if !cfg.PushEnabled() {
+		t.Error("invalid YEET_PUSH should be ignored")
+	}
+
+	problems := cfg.Problems()
+	if len(problems) != 1 || !problems[0].Warning || !strings.Contains(problems[0].Message, "YEET_PUSH") {
+		t.Errorf("Problems() = %v", problems)
+	}
+}
+
+func TestApplyEnvModelNeedsProvider(t *testing.T) {
+	t.Setenv("YEET_PROVIDER", "")
+	t.Setenv("YEET_MODEL", "gpt-4o")
+
+	cfg := DefaultConfig()
+	cfg.applyEnv()
+	if _, ok := cfg.Providers["openai"]; ok {
+		t.Errorf("Providers[openai] = %+v, want unchanged", cfg.Providers["openai"])
+	}
+	if len(cfg.Validate()) != 1 {
+		t.Errorf("Validate() = %v, want YEET_MODEL warning", cfg.Validate())
+	}
+}
+
+func TestErrorsExcludeWarnings(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Providers = map[string]ProviderConfig{"myapi": {URL: "https://example.com"}}
+	if len(cfg.Validate()) != 1 {
+		t.Fatalf("Validate() = %v", cfg.Validate())
+	}
+	if errs := cfg.Errors(); len(errs) != 0 {
+		t.Errorf("Errors() = %v, want none (missing env is a warning)", errs)
+	}
+}
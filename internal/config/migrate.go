@@ -0,0 +1,284 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+)
+
+// CurrentVersion is the config schema version written by this build.
+// Files without a version key are version 0.
+const CurrentVersion = 2
+
+// migration upgrades a decoded config tree from version to-1 to version to.
+type migration struct {
+	to   int
+	desc string
+	run  func(tree map[string]any)
+}
+
+// migrations run in order on every file older than their target version.
+var migrations = []migration{
+	{1, "move builtin providers out of [custom] into their own tables", migrateBuiltinCustom},
+	{2, "move [anthropic], [openai], [ollama] and [custom.*] into [providers.*]", migrateProvidersTable},
+}
+
+// Migration describes how the config file would be upgraded to CurrentVersion.
+type Migration struct {
+	Path          string
+	From, To      int
+	Steps         []string
+	Before, After string
+}
+
+// PlanMigration computes the upgraded config file without writing it.
+func PlanMigration() (Migration, error) {
+	path, err := configPath()
+	if err != nil {
+		return Migration{}, err
+	}
+	m := Migration{Path: path, To: CurrentVersion}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		m.From = CurrentVersion
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+	m.Before = string(data)
+
+	var tree map[string]any
+	if _, err := toml.Decode(m.Before, &tree); err != nil {
+		return m, err
+	}
+	m.From = treeVersion(tree)
+	if m.Steps, err = migrateTree(tree); err != nil {
+		return m, err
+	}
+
+	cfg, err := decode(m.Before, DefaultConfig())
+	if err != nil {
+		return m, err
+	}
+	m.After, err = render(m.Before, prepareSave(cfg))
+	return m, err
+}
+
+// Apply writes the upgraded file, keeping the previous one as config.toml.bak.
+func (m Migration) Apply() error {
+	return replaceFile(m.Path, []byte(m.Before), m.After)
+}
+
+// treeVersion returns the version key of a decoded config, 0 when missing.
+func treeVersion(tree map[string]any) int {
+	if v, ok := tree["version"].(int64); ok {
+		return int(v)
+	}
+	return 0
+}
+
+// migrateTree upgrades tree in place to CurrentVersion and returns the
+// descriptions of the steps that ran.
+func migrateTree(tree map[string]any) ([]string, error) {
+	version := treeVersion(tree)
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("config version %d is newer than this yeet supports (%d) — please upgrade yeet", version, CurrentVersion)
+	}
+	var steps []string
+	for _, m := range migrations {
+		if m.to > version {
+			m.run(tree)
+			steps = append(steps, fmt.Sprintf("v%d: %s", m.to, m.desc))
+		}
+	}
+	tree["version"] = int64(CurrentVersion)
+	return steps, nil
+}
+
+// upgrade returns text migrated to CurrentVersion. Current files are returned
+// unchanged so TOML metadata (e.g. line numbers) still matches the original.
+func upgrade(text string) (string, error) {
+	var tree map[string]any
+	if _, err := toml.Decode(text, &tree); err != nil {
+		return "", err
+	}
+	if treeVersion(tree) == CurrentVersion {
+		return text, nil
+	}
+	if _, err := migrateTree(tree); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(tree); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// decode parses config text on top of cfg, upgrading older schema versions
+// first. Keys the struct doesn't know are kept as warnings for Validate.
+func decode(text string, cfg Config) (Config, error) {
+	upgraded, err := upgrade(text)
+	if err != nil {
+		return cfg, err
+	}
+	md, err := toml.Decode(upgraded, &cfg)
+	if err != nil {
+		return cfg, err
+	}
+	if upgraded != text {
+		cfg.warnings = append(cfg.warnings, fmt.Sprintf("config.toml uses an older schema — run `yeet config migrate` to upgrade it to version %d", CurrentVersion))
+	}
+	cfg.warnings = append(cfg.warnings, unknownKeys(md, text)...)
+	return cfg, nil
+}
+
+// unknownKeys reports undecoded keys with their line in the original text.
+// Keys inside an unknown table are covered by the table itself.
+func unknownKeys(md toml.MetaData, text string) []string {
+	undecoded := md.Undecoded()
+	if len(undecoded) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(undecoded))
+	for _, k := range undecoded {
+		seen[FormatKey(k)] = true
+	}
+	doc, _ := parseDocument(text)
+
+	var out []string
+	for _, k := range undecoded {
+		if len(k) > 1 && seen[FormatKey(k[:len(k)-1])] {
+			continue
+		}
+		msg := fmt.Sprintf("unknown key %s", FormatKey(k))
+		if doc != nil {
+			if line := doc.line(k); line > 0 {
+				msg += fmt.Sprintf(" (line %d)", line)
+			}
+		}
+		out = append(out, msg)
+	}
+	return out
+}
+
+// migrateBuiltinCustom moves [custom.anthropic] and friends into the named
+// builtin tables. Custom entries used to override those tables, so their
+// values win.
+func migrateBuiltinCustom(tree map[string]any) {
+	custom, ok := tree["custom"].(map[string]any)
+	if !ok {
+		return
+	}
+	for _, name := range Providers() {
+		entry, ok := custom[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		dst, _ := tree[name].(map[string]any)
+		if dst == nil {
+			dst = make(map[string]any)
+		}
+		for k, v := range entry {
+			dst[k] = v
+		}
+		tree[name] = dst
+		delete(custom, name)
+	}
+	if len(custom) == 0 {
+		delete(tree, "custom")
+	}
+}
+
+// migrateProvidersTable replaces the named builtin tables and the custom map
+// with a single providers table. Existing [providers.*] values win.
+func migrateProvidersTable(tree map[string]any) {
+	providers, _ := tree["providers"].(map[string]any)
+	if providers == nil {
+		providers = make(map[string]any)
+	}
+	move := func(name string, entry map[string]any) {
+		dst, _ := providers[name].(map[string]any)
+		if dst == nil {
+			dst = make(map[string]any)
+		}
+		for k, v := range entry {
+			if _, set := dst[k]; !set {
+				dst[k] = v
+			}
+		}
+		if len(dst) > 0 {
+			providers[name] = dst
+		}
+	}
+
+	for _, name := range Providers() {
+		if entry, ok := tree[name].(map[string]any); ok {
+			move(name, entry)
+			delete(tree, name)
+		}
+	}
+	if custom, ok := tree["custom"].(map[string]any); ok {
+		for name, v := range custom {
+			if entry, ok := v.(map[string]any); ok {
+				move(name, entry)
+			}
+		}
+		delete(tree, "custom")
+	}
+	if len(providers) > 0 {
+		tree["providers"] = providers
+	}
+}
+
+// treeTables collects the paths of all tables in a decoded TOML tree.
+func treeTables(tree map[string]any, prefix []string, out *[][]string) {
+	for k, v := range tree {
+		if sub, ok := v.(map[string]any); ok {
+			path := append(append([]string{}, prefix...), k)
+			*out = append(*out, path)
+			treeTables(sub, path, out)
+		}
+	}
+}
+
+// hasTable reports whether tree contains a table at path.
+func hasTable(tree map[string]any, path []string) bool {
+	for _, part := range path {
+		sub, ok := tree[part].(map[string]any)
+		if !ok {
+			return false
+		}
+		tree = sub
+	}
+	return true
+}
+
+// treeLeaves collects the leaves of a decoded TOML tree with sorted keys.
+func treeLeaves(tree map[string]any, prefix []string, out *[]keyValue) {
+	keys := make([]string, 0, len(tree))
+	for k := range tree {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		path := append(append([]string{}, prefix...), k)
+		if sub, ok := tree[k].(map[string]any); ok {
+			treeLeaves(sub, path, out)
+			continue
+		}
+		*out = append(*out, keyValue{path: path, value: tree[k]})
+	}
+}
+
+// structLeaves collects the non-empty leaves of a config in field order.
+func structLeaves(cfg Config) []keyValue {
+	var out []keyValue
+	flattenOrdered(reflect.ValueOf(cfg), nil, &out)
+	return out
+}
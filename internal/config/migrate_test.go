@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const legacyConfig = `# old layout
+provider = "anthropic"
+
+[anthropic]
+model = "claude-haiku-4-5-20251001"
+
+[custom.anthropic]
+model = "claude-opus-4-6"
+
+[custom.together]
+url = "https://api.together.xyz/v1"
+env = "TOGETHER_API_KEY"
+`
+
+func TestLoadFileMigrates(t *testing.T) {
+	writeConfig(t, legacyConfig)
+	cfg, err := LoadFile()
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, CurrentVersion)
+	}
+	if got := cfg.Providers["anthropic"].Model; got != "claude-opus-4-6" {
+		t.Errorf("anthropic model = %q, custom entry should win", got)
+	}
+	if cfg.Providers["together"].URL == "" {
+		t.Error("custom.together was not moved to providers")
+	}
+	if len(cfg.Validate()) != 1 {
+		t.Errorf("Validate() = %v, want only the migrate hint", cfg.Validate())
+	}
+}
+
+func TestPlanMigration(t *testing.T) {
+	path := writeConfig(t, legacyConfig)
+	m, err := PlanMigration()
+	if err != nil {
+		t.Fatalf("PlanMigration: %v", err)
+	}
+	if m.From != 0 || m.To != CurrentVersion || len(m.Steps) != 2 {
+		t.Errorf("plan = v%d → v%d, steps %v", m.From, m.To, m.Steps)
+	}
+	for _, gone := range []string{"[custom", "[anthropic]", "claude-haiku"} {
+		if strings.Contains(m.After, gone) {
+			t.Errorf("After still contains %q:\n%s", gone, m.After)
+		}
+	}
+	for _, want := range []string{"[providers.anthropic]\nmodel = \"claude-opus-4-6\"", "[providers.together]\n"} {
+		if !strings.Contains(m.After, want) {
+			t.Errorf("After missing %q:\n%s", want, m.After)
+		}
+	}
+	if !strings.Contains(m.After, "# old layout") || !strings.Contains(m.After, "version = 2") {
+		t.Errorf("After lost comments or version:\n%s", m.After)
+	}
+
+	// Planning must not write anything.
+	if data, _ := os.ReadFile(path); string(data) != legacyConfig {
+		t.Error("PlanMigration modified the file")
+	}
+
+	if err := m.Apply(); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	again, err := PlanMigration()
+	if err != nil {
+		t.Fatalf("PlanMigration after Apply: %v", err)
+	}
+	if again.From != CurrentVersion || again.After != again.Before {
+		t.Errorf("second plan not a no-op: v%d\n%s", again.From, again.After)
+	}
+}
+
+func TestLoadFileNewerVersion(t *testing.T) {
+	writeConfig(t, "version = 99\n")
+	if _, err := LoadFile(); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("LoadFile() error = %v, want newer-version error", err)
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	writeConfig(t, `version = 2
+provider = "auto"
+colour = "red"
+
+[providers.together]
+url = "https://x"
+modle = "typo"
+
+[telemetry]
+enabled = true
+`)
+	cfg, err := LoadFile()
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	got := strings.Join(cfg.Validate(), "\n")
+	for _, want := range []string{
+		"unknown key colour (line 3)",
+		"unknown key providers.together.modle (line 7)",
+		"unknown key telemetry (line 9)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Validate() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "telemetry.enabled") {
+		t.Errorf("keys inside unknown tables should not be listed:\n%s", got)
+	}
+	if len(cfg.Errors()) != 0 {
+		t.Errorf("unknown keys should be warnings, got errors %v", cfg.Errors())
+	}
+}
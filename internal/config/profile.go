@@ -0,0 +1,239 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Profile overrides parts of the config for a named context, e.g. "work" or "home".
+type Profile struct {
+	Provider string                     `toml:"provider,omitempty"`
+	Models   map[string]string          `toml:"models,omitempty"`
+	Prompt   string                     `toml:"prompt,omitempty"`
+	Pricing  map[string]PricingOverride `toml:"pricing,omitempty"`
+	Push     *bool                      `toml:"push,omitempty"`
+	Keys     map[string]string          `toml:"keys,omitempty"`
+
+	// Remotes and Paths are glob patterns that activate the profile automatically
+	// when the repo's origin URL or root directory matches.
+	Remotes []string `toml:"remotes,omitempty"`
+	Paths   []string `toml:"paths,omitempty"`
+}
+
+// ProfileOverride is set from the --profile flag and takes precedence over
+// YEET_PROFILE, auto-matching and the default profile.
+var ProfileOverride string
+
+// Profile sources, reported by doctor and the TUI.
+const (
+	ProfileFromFlag    = "--profile"
+	ProfileFromEnv     = "YEET_PROFILE"
+	ProfileFromRemote  = "remote match"
+	ProfileFromPath    = "path match"
+	ProfileFromDefault = "default"
+)
+
+// repoInfo returns the origin remote URL and the repo root directory.
+// It is a variable so tests can stub out git.
+var repoInfo = func() (remote, root string) {
+	if out, err := exec.Command("git", "remote", "get-url", "origin").Output(); err == nil {
+		remote = strings.TrimSpace(string(out))
+	}
+	if out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		root = strings.TrimSpace(string(out))
+	}
+	return remote, root
+}
+
+// ProfileNames returns the configured profile names, sorted.
+func (c Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelectProfile picks the active profile name and why it was chosen.
+// Precedence: --profile flag → YEET_PROFILE → remote/path match → default profile key.
+func (c Config) SelectProfile() (name, source string) {
+	if ProfileOverride != "" {
+		return ProfileOverride, ProfileFromFlag
+	}
+	if env := os.Getenv("YEET_PROFILE"); env != "" {
+		return env, ProfileFromEnv
+	}
+	if len(c.Profiles) > 0 {
+		remote, root := repoInfo()
+		if name, source := c.matchProfile(remote, root); name != "" {
+			return name, source
+		}
+	}
+	if c.Profile != "" {
+		return c.Profile, ProfileFromDefault
+	}
+	return "", ""
+}
+
+// matchProfile returns the first profile (by name) whose remote or path patterns match.
+func (c Config) matchProfile(remote, root string) (string, string) {
+	for _, name := range c.ProfileNames() {
+		p := c.Profiles[name]
+		if remote != "" {
+			for _, pattern := range p.Remotes {
+				if matchRemote(pattern, remote) {
+					return name, ProfileFromRemote
+				}
+			}
+		}
+		if root != "" {
+			for _, pattern := range p.Paths {
+				if matchPath(pattern, root) {
+					return name, ProfileFromPath
+				}
+			}
+		}
+	}
+	return "", ""
+}
+
+// ApplyProfile returns a copy of the config with the named profile's overrides merged in.
+// Unknown profiles leave the config unchanged apart from ActiveProfile.
+func (c Config) ApplyProfile(name, source string) Config {
+	out := c
+	out.ActiveProfile = name
+	out.ProfileSource = source
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return out
+	}
+
+	// Copy maps before mutating so the base config stays untouched.
+	out.Providers = make(map[string]ProviderConfig, len(c.Providers))
+	for k, v := range c.Providers {
+		out.Providers[k] = v
+	}
+	out.Pricing = make(map[string]PricingOverride, len(c.Pricing)+len(p.Pricing))
+	for k, v := range c.Pricing {
+		out.Pricing[k] = v
+	}
+	out.Keys = make(map[string]string, len(c.Keys)+len(p.Keys))
+	for k, v := range c.Keys {
+		out.Keys[k] = v
+	}
+
+	if p.Provider != "" {
+		out.Provider = p.Provider
+	}
+	for provider, model := range p.Models {
+		out.SetModel(provider, model)
+	}
+	if p.Prompt != "" {
+		out.Prompt = p.Prompt
+	}
+	for model, price := range p.Pricing {
+		out.Pricing[model] = price
+	}
+	if p.Push != nil {
+		out.Push = p.Push
+	}
+	for provider, slot := range p.Keys {
+		out.Keys[provider] = slot
+	}
+	return out
+}
+
+// PushEnabled reports whether commits should be pushed after committing.
+func (c Config) PushEnabled() bool {
+	return c.Push == nil || *c.Push
+}
+
+// PushRemoteName returns the remote new branches are pushed to.
+func (c Config) PushRemoteName() string {
+	if c.PushRemote == "" {
+		return "origin"
+	}
+	return c.PushRemote
+}
+
+// PromptPath returns the prompt file override with ~ expanded, or "" when unset.
+func (c Config) PromptPath() string {
+	return expandHome(c.Prompt)
+}
+
+// matchRemote matches a glob against the raw remote URL and its normalized
+// host/owner/repo form, so "github.com/acme/*" matches both SSH and HTTPS remotes.
+func matchRemote(pattern, remote string) bool {
+	return globMatch(pattern, remote) || globMatch(pattern, normalizeRemote(remote))
+}
+
+// matchPath matches a glob against the repo root or any of its parent directories.
+func matchPath(pattern, root string) bool {
+	pattern = filepath.Clean(expandHome(pattern))
+	for dir := filepath.Clean(root); ; dir = filepath.Dir(dir) {
+		if globMatch(pattern, dir) {
+			return true
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return false
+		}
+	}
+}
+
+// normalizeRemote turns "git@github.com:acme/app.git" or
+// "https://github.com/acme/app.git" into "github.com/acme/app".
+func normalizeRemote(remote string) string {
+	s := remote
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	} else if i := strings.Index(s, ":"); i >= 0 {
+		s = s[:i] + "/" + s[i+1:]
+	}
+	if i := strings.Index(s, "@"); i >= 0 && i < strings.Index(s+"/", "/") {
+		s = s[i+1:]
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	return s
+}
+
+// globMatch supports "*" (anything except "/"), "**" (anything) and "?".
+func globMatch(pattern, s string) bool {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func stubRepoInfo(t *testing.T, remote, root string) {
+	t.Helper()
+	orig := repoInfo
+	repoInfo = func() (string, string) { return remote, root }
+	t.Cleanup(func() { repoInfo = orig })
+}
+
+func TestProfileDecode(t *testing.T) {
+	input := `
+provider = "auto"
+profile = "home"
+
+[profiles.work]
+provider = "azure"
+prompt = "~/work-prompt.txt"
+push = false
+remotes = ["github.com/acme/*"]
+
+[profiles.work.models]
+anthropic = "claude-sonnet-4-6"
+
+[profiles.home]
+provider = "groq"
+`
+	var cfg Config
+	if _, err := toml.NewDecoder(strings.NewReader(input)).Decode(&cfg); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got := cfg.ProfileNames(); len(got) != 2 || got[0] != "home" || got[1] != "work" {
+		t.Fatalf("ProfileNames() = %v", got)
+	}
+	work := cfg.Profiles["work"]
+	if work.Push == nil || *work.Push {
+		t.Errorf("work.Push = %v, want false", work.Push)
+	}
+	if work.Models["anthropic"] != "claude-sonnet-4-6" {
+		t.Errorf("work.Models = %v", work.Models)
+	}
+}
+
+func TestSelectProfile(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Profile = "home"
+	cfg.Profiles = map[string]Profile{
+		"home": {Provider: "groq"},
+		"work": {Provider: "anthropic", Remotes: []string{"github.com/acme/*"}, Paths: []string{"/src/work"}},
+	}
+
+	t.Run("flag wins", func(t *testing.T) {
+		t.Setenv("YEET_PROFILE", "home")
+		ProfileOverride = "work"
+		defer func() { ProfileOverride = "" }()
+		if name, source := cfg.SelectProfile(); name != "work" || source != ProfileFromFlag {
+			t.Errorf("SelectProfile() = %q, %q", name, source)
+		}
+	})
+
+	t.Run("env before matching", func(t *testing.T) {
+		t.Setenv("YEET_PROFILE", "home")
+		stubRepoInfo(t, "git@github.com:acme/app.git", "")
+		if name, source := cfg.SelectProfile(); name != "home" || source != ProfileFromEnv {
+			t.Errorf("SelectProfile() = %q, %q", name, source)
+		}
+	})
+
+	t.Run("remote match", func(t *testing.T) {
+		t.Setenv("YEET_PROFILE", "")
+		stubRepoInfo(t, "git@github.com:acme/app.git", "/tmp/app")
+		if name, source := cfg.SelectProfile(); name != "work" || source != ProfileFromRemote {
+			t.Errorf("SelectProfile() = %q, %q", name, source)
+		}
+	})
+
+	t.Run("path match on parent dir", func(t *testing.T) {
+		t.Setenv("YEET_PROFILE", "")
+		stubRepoInfo(t, "https://gitlab.com/me/dotfiles", "/src/work/api")
+		if name, source := cfg.SelectProfile(); name != "work" || source != ProfileFromPath {
+			t.Errorf("SelectProfile() = %q, %q", name, source)
+		}
+	})
+
+	t.Run("falls back to default", func(t *testing.T) {
+		t.Setenv("YEET_PROFILE", "")
+		stubRepoInfo(t, "https://gitlab.com/me/dotfiles", "/home/me/dotfiles")
+		if name, source := cfg.SelectProfile(); name != "home" || source != ProfileFromDefault {
+			t.Errorf("SelectProfile() = %q, %q", name, source)
+		}
+	})
+}
+
+func TestApplyProfile(t *testing.T) {
+	no := false
+	cfg := DefaultConfig()
+	cfg.Pricing = map[string]PricingOverride{"base-model": {Input: 1, Output: 2}}
+	cfg.Keys = map[string]string{"groq": "personal"}
+	cfg.Profiles = map[string]Profile{
+		"work": {
+			Provider: "groq",
+			Models:   map[string]string{"groq": "llama-3.1-8b-instant", "anthropic": "claude-opus-4-6"},
+			Prompt:   "/tmp/work.txt",
+			Pricing:  map[string]PricingOverride{"work-model": {Input: 3, Output: 4}},
+			Push:     &no,
+			Keys:     map[string]string{"anthropic": "work"},
+		},
+	}
+
+	out := cfg.ApplyProfile("work", ProfileFromFlag)
+
+	if out.Provider != "groq" {
+		t.Errorf("Provider = %q", out.Provider)
+	}
+	if rp, _ := out.ResolveProviderFull("groq"); rp.Model != "llama-3.1-8b-instant" {
+		t.Errorf("groq model = %q", rp.Model)
+	}
+	if out.Providers["anthropic"].Model != "claude-opus-4-6" {
+		t.Errorf("anthropic model = %q", out.Providers["anthropic"].Model)
+	}
+	if out.PromptPath() != "/tmp/work.txt" {
+		t.Errorf("PromptPath() = %q", out.PromptPath())
+	}
+	if len(out.Pricing) != 2 {
+		t.Errorf("Pricing = %v, want base + profile entries", out.Pricing)
+	}
+	if out.PushEnabled() {
+		t.Error("PushEnabled() = true, want false")
+	}
+	if rp, _ := out.ResolveProviderFull("anthropic"); rp.KeySlot != "work" || out.Keys["groq"] != "personal" {
+		t.Errorf("Keys = %v", out.Keys)
+	}
+	if out.ActiveProfile != "work" || out.ProfileSource != ProfileFromFlag {
+		t.Errorf("ActiveProfile = %q (%s)", out.ActiveProfile, out.ProfileSource)
+	}
+
+	// The base config must not be mutated through shared maps.
+	if _, ok := cfg.Providers["groq"]; ok {
+		t.Error("base Providers was mutated")
+	}
+	if len(cfg.Pricing) != 1 {
+		t.Error("base Pricing was mutated")
+	}
+	if !cfg.PushEnabled() {
+		t.Error("base PushEnabled() = false")
+	}
+	if len(cfg.Keys) != 1 {
+		t.Error("base Keys was mutated")
+	}
+}
+
+func TestValidateUnknownProfile(t *testing.T) {
+	cfg := DefaultConfig().ApplyProfile("missing", ProfileFromEnv)
+	problems := cfg.Validate()
+	if len(problems) != 1 || !strings.Contains(problems[0], "unknown profile") {
+		t.Errorf("Validate() = %v", problems)
+	}
+}
+
+func TestNormalizeRemote(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"git@github.com:acme/app.git", "github.com/acme/app"},
+		{"https://github.com/acme/app.git", "github.com/acme/app"},
+		{"https://user@gitlab.example.com/group/sub/app", "gitlab.example.com/group/sub/app"},
+		{"ssh://git@git.company.io/team/app.git", "git.company.io/team/app"},
+	}
+	for _, tt := range tests {
+		if got := normalizeRemote(tt.in); got != tt.want {
+			t.Errorf("normalizeRemote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"github.com/acme/*", "github.com/acme/app", true},
+		{"github.com/acme/*", "github.com/acme/sub/app", false},
+		{"github.com/acme/**", "github.com/acme/sub/app", true},
+		{"*.company.io/**", "git.company.io/team/app", true},
+		{"/src/work", "/src/work", true},
+		{"/src/w?rk", "/src/work", true},
+		{"/src/work", "/src/workshop", false},
+	}
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
@@ -1,5 +1,11 @@
 package config
 
+import (
+	"time"
+
+	"github.com/rasalas/yeet/internal/keyring"
+)
+
 // Protocol identifies the API protocol a provider uses.
 type Protocol string
 
@@ -78,6 +84,26 @@ type ResolvedProvider struct {
 	Model     string
 	URL       string
 	Env       string
+	KeyCmd    string
+	KeySlot   string
 	Protocol  Protocol
 	NeedsAuth bool
+
+	Headers       map[string]string
+	Temperature   *float64
+	MaxTokens     int
+	Timeout       time.Duration
+	ContextWindow int
+
+	Proxy         string
+	CAFile        string
+	ClientCert    string
+	ClientKey     string
+	DialTimeout   time.Duration
+	HeaderTimeout time.Duration
+}
+
+// KeyLookup returns the provider's key sources for the keyring lookup chain.
+func (rp ResolvedProvider) KeyLookup() keyring.Lookup {
+	return keyring.Lookup{Env: rp.Env, Cmd: rp.KeyCmd, Slot: rp.KeySlot}
 }
@@ -35,7 +35,8 @@ CREATE TABLE IF NOT EXISTS runs (
 	output_tokens INTEGER NOT NULL DEFAULT 0,
 	cost_usd REAL NOT NULL DEFAULT 0,
 	latency_ms INTEGER NOT NULL DEFAULT 0,
-	local_only INTEGER NOT NULL DEFAULT 0
+	local_only INTEGER NOT NULL DEFAULT 0,
+	key_slot TEXT NOT NULL DEFAULT ''
 );
 CREATE INDEX IF NOT EXISTS idx_runs_created_at ON runs(created_at DESC);
 CREATE INDEX IF NOT EXISTS idx_runs_command ON runs(command);
@@ -109,6 +110,7 @@ type RunRecord struct {
 	CostUSD       float64
 	LatencyMS     int64
 	LocalOnly     bool
+	KeySlot       string
 }
 
 type Run struct {
@@ -218,9 +220,27 @@ func Open() (*Store, error) {
 	if err := s.exec(schemaSQL); err != nil {
 		return nil, err
 	}
+	if err := s.ensureColumn("runs", "key_slot", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return nil, err
+	}
 	return s, nil
 }
 
+// ensureColumn adds a column that databases created by older versions lack.
+func (s *Store) ensureColumn(table, column, def string) error {
+	var rows []struct {
+		N int `json:"n"`
+	}
+	sql := fmt.Sprintf("SELECT COUNT(*) AS n FROM pragma_table_info(%s) WHERE name = %s;", sqlText(table), sqlText(column))
+	if err := s.query(sql, &rows); err != nil {
+		return err
+	}
+	if len(rows) > 0 && rows[0].N > 0 {
+		return nil
+	}
+	return s.exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table, column, def))
+}
+
 func (s *Store) Path() string {
 	return s.path
 }
@@ -236,12 +256,12 @@ INSERT INTO runs (
 	created_at, repo_path, command, branch, status, recent_commits, diff,
 	provider, model, prompt_hash, prompt_text,
 	ai_message, final_message, user_action,
-	input_tokens, output_tokens, cost_usd, latency_ms, local_only
+	input_tokens, output_tokens, cost_usd, latency_ms, local_only, key_slot
 ) VALUES (
 	%s, %s, %s, %s, %s, %s, %s,
 	%s, %s, %s, %s,
 	%s, %s, %s,
-	%d, %d, %s, %d, %d
+	%d, %d, %s, %d, %d, %s
 );`,
 		sqlText(createdAt.Format(time.RFC3339Nano)),
 		sqlText(r.RepoPath),
@@ -262,6 +282,7 @@ INSERT INTO runs (
 		sqlFloat(r.CostUSD),
 		r.LatencyMS,
 		boolAsInt(r.LocalOnly),
+		sqlText(r.KeySlot),
 	)
 
 	return s.exec(sql)
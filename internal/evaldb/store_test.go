@@ -1,6 +1,11 @@
 package evaldb
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestInsertAndSelectEligibleRuns(t *testing.T) {
 	t.Setenv("XDG_DATA_HOME", t.TempDir())
@@ -160,3 +165,41 @@ func TestVariantAndVotingFlow(t *testing.T) {
 		t.Fatalf("report.CandidateWins = %d, want 1", report.CandidateWins)
 	}
 }
+
+func TestOpenAddsKeySlotColumn(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	path, err := DBPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A runs table from before key slots were recorded.
+	old := strings.Replace(schemaSQL, ",\n\tkey_slot TEXT NOT NULL DEFAULT ''", "", 1)
+	if err := runSQLite(path, false, old, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Open()
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := store.InsertRun(RunRecord{Command: "commit", Provider: "anthropic", KeySlot: "work"}); err != nil {
+		t.Fatalf("InsertRun() error = %v", err)
+	}
+	var rows []struct {
+		KeySlot string `json:"key_slot"`
+	}
+	if err := store.query("SELECT key_slot FROM runs;", &rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].KeySlot != "work" {
+		t.Errorf("key_slot rows = %+v", rows)
+	}
+
+	// Opening again must not try to add the column twice.
+	if _, err := Open(); err != nil {
+		t.Fatalf("second Open() error = %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package forge
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Bitbucket implements Forge with the Bitbucket Cloud REST API (2.0).
+type Bitbucket struct {
+	Remote  Remote
+	Token   string // access token, or "username:app-password"
+	BaseURL string // API root, e.g. "https://api.bitbucket.org/2.0"
+	Fork    string // "workspace/repo" branches are pushed to, when not Remote
+}
+
+// NewBitbucket returns a Bitbucket Cloud client for the remote's repository.
+func NewBitbucket(remote Remote, token, apiURL string) *Bitbucket {
+	if apiURL == "" {
+		apiURL = "https://api.bitbucket.org/2.0"
+	}
+	return &Bitbucket{Remote: remote, Token: token, BaseURL: apiURL}
+}
+
+func (*Bitbucket) Name() string { return "Bitbucket" }
+
+// bitbucketPR is the pull request object of the REST API.
+type bitbucketPR struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	Draft       bool   `json:"draft"`
+	Links       struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+	Source      bitbucketRef `json:"source"`
+	Destination bitbucketRef `json:"destination"`
+}
+
+type bitbucketRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+func (p bitbucketPR) toPR() *PR {
+	state := strings.ToLower(p.State)
+	if state != "open" && state != "merged" {
+		state = "closed" // DECLINED, SUPERSEDED
+	}
+	return &PR{
+		Number: p.ID,
+		URL:    p.Links.HTML.Href,
+		Title:  p.Title,
+		Body:   p.Description,
+		Head:   p.Source.Branch.Name,
+		Base:   p.Destination.Branch.Name,
+		State:  state,
+		Draft:  p.Draft,
+	}
+}
+
+func (b *Bitbucket) do(method, path string, body, result any) error {
+	auth := "Bearer " + b.Token
+	if strings.Contains(b.Token, ":") {
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(b.Token))
+	}
+	return doJSON(method, b.BaseURL+path, map[string]string{"Authorization": auth}, body, result)
+}
+
+func (b *Bitbucket) repoPath() string {
+	return "/repositories/" + b.Remote.Owner() + "/" + b.Remote.Repo()
+}
+
+func (b *Bitbucket) ExistingPR(branch string) (*PR, error) {
+	q := fmt.Sprintf(`source.branch.name = %q AND state = "OPEN"`, branch)
+	if b.Fork != "" {
+		q += fmt.Sprintf(` AND source.repository.full_name = %q`, b.Fork)
+	}
+	query := url.Values{"q": {q}}
+	var page struct {
+		Values []bitbucketPR `json:"values"`
+	}
+	if err := b.do("GET", b.repoPath()+"/pullrequests?"+query.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	if len(page.Values) == 0 {
+		return nil, nil
+	}
+	return page.Values[0].toPR(), nil
+}
+
+func (b *Bitbucket) CreatePR(pr NewPR) (*PR, error) {
+	source := map[string]any{"branch": map[string]string{"name": pr.Head}}
+	if b.Fork != "" {
+		source["repository"] = map[string]string{"full_name": b.Fork}
+	}
+	req := map[string]any{
+		"title":       pr.Title,
+		"description": pr.Body,
+		"source":      source,
+	}
+	// Without a destination Bitbucket targets the repository's main branch.
+	if pr.Base != "" {
+		req["destination"] = map[string]any{"branch": map[string]string{"name": pr.Base}}
+	}
+	if pr.Draft {
+		req["draft"] = true
+	}
+	// Reviewers are referenced by UUID ("{…}") or Atlassian account ID.
+	if len(pr.Reviewers) > 0 {
+		var reviewers []map[string]string
+		for _, r := range pr.Reviewers {
+			if strings.HasPrefix(r, "{") {
+				reviewers = append(reviewers, map[string]string{"uuid": r})
+			} else {
+				reviewers = append(reviewers, map[string]string{"account_id": r})
+			}
+		}
+		req["reviewers"] = reviewers
+	}
+	var created bitbucketPR
+	if err := b.do("POST", b.repoPath()+"/pullrequests", req, &created); err != nil {
+		return nil, fmt.Errorf("create pull request: %w", err)
+	}
+
+	var labelErr, assigneeErr error
+	if len(pr.Labels) > 0 {
+		labelErr = fmt.Errorf("labels: not supported by Bitbucket")
+	}
+	if len(pr.Assignees) > 0 {
+		assigneeErr = fmt.Errorf("assignees: not supported by Bitbucket")
+	}
+	return created.toPR(), metadataErr(labelErr, assigneeErr)
+}
+
+func (b *Bitbucket) UpdatePR(number int, body string) error {
+	path := fmt.Sprintf("%s/pullrequests/%d", b.repoPath(), number)
+	if err := b.do("PUT", path, map[string]any{"description": body}, nil); err != nil {
+		return fmt.Errorf("update pull request: %w", err)
+	}
+	return nil
+}
+
+func (b *Bitbucket) RetargetPR(number int, base string) error {
+	path := fmt.Sprintf("%s/pullrequests/%d", b.repoPath(), number)
+	req := map[string]any{"destination": map[string]any{"branch": map[string]string{"name": base}}}
+	if err := b.do("PUT", path, req, nil); err != nil {
+		return fmt.Errorf("retarget pull request: %w", err)
+	}
+	return nil
+}
+
+func (b *Bitbucket) GetPR(number int) (*PR, error) {
+	var pr bitbucketPR
+	if err := b.do("GET", fmt.Sprintf("%s/pullrequests/%d", b.repoPath(), number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toPR(), nil
+}
+
+// Checks returns the build statuses reported on the PR's commits, which
+// include Bitbucket Pipelines steps.
+func (b *Bitbucket) Checks(number int) ([]Check, error) {
+	var page struct {
+		Values []struct {
+			Key   string `json:"key"`
+			Name  string `json:"name"`
+			State string `json:"state"`
+			URL   string `json:"url"`
+		} `json:"values"`
+	}
+	if err := b.do("GET", fmt.Sprintf("%s/pullrequests/%d/statuses?pagelen=100", b.repoPath(), number), nil, &page); err != nil {
+		return nil, err
+	}
+	var checks []Check
+	for _, s := range page.Values {
+		state := CheckPending
+		switch s.State {
+		case "SUCCESSFUL":
+			state = CheckSuccess
+		case "FAILED", "STOPPED":
+			state = CheckFailure
+		}
+		name := s.Name
+		if name == "" {
+			name = s.Key
+		}
+		checks = append(checks, Check{Name: name, State: state, URL: s.URL})
+	}
+	return checks, nil
+}
+
+// bitbucketStrategies maps merge strategies to Bitbucket's names.
+var bitbucketStrategies = map[string]string{
+	MergeCommit: "merge_commit",
+	MergeSquash: "squash",
+	MergeRebase: "rebase_fast_forward",
+}
+
+// MergePR merges right away; Bitbucket has no auto-merge in its API.
+func (b *Bitbucket) MergePR(number int, strategy string) error {
+	name, ok := bitbucketStrategies[strategy]
+	if !ok {
+		return unsupportedStrategy("Bitbucket", strategy)
+	}
+	path := fmt.Sprintf("%s/pullrequests/%d/merge", b.repoPath(), number)
+	if err := b.do("POST", path, map[string]any{"merge_strategy": name}, nil); err != nil {
+		return fmt.Errorf("merge pull request: %w", err)
+	}
+	return nil
+}
+
+// SubmitReview posts the comments as pending comments, which stay
+// private until published on the PR.
+func (b *Bitbucket) SubmitReview(number int, body string, comments []ReviewComment) (string, error) {
+	path := fmt.Sprintf("%s/pullrequests/%d", b.repoPath(), number)
+	for i, c := range comments {
+		req := map[string]any{
+			"content": map[string]string{"raw": c.Body},
+			"inline":  map[string]any{"path": c.Path, "to": c.Line},
+			"pending": true,
+		}
+		if err := b.do("POST", path+"/comments", req, nil); err != nil {
+			return "", partialReviewErr(fmt.Errorf("comment on %s:%d: %w", c.Path, c.Line, err), i, "pending comment(s)")
+		}
+	}
+	if body != "" {
+		req := map[string]any{"content": map[string]string{"raw": body}, "pending": true}
+		if err := b.do("POST", path+"/comments", req, nil); err != nil {
+			return "", partialReviewErr(fmt.Errorf("comment: %w", err), len(comments), "pending comment(s)")
+		}
+	}
+	// The comments are in; the link is only a nicety.
+	var pr bitbucketPR
+	if err := b.do("GET", path, nil, &pr); err != nil {
+		return "", nil
+	}
+	return pr.Links.HTML.Href, nil
+}
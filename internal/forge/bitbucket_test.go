@@ -0,0 +1,52 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBitbucket(t *testing.T) {
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "jane" || pass != "app-pass" {
+			t.Errorf("basic auth = %q %q %v", user, pass, ok)
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repositories/ws/app/pullrequests":
+			if r.URL.Query().Get("q") == `source.branch.name = "feature" AND state = "OPEN"` {
+				w.Write([]byte(`{"values": [{"id": 5, "state": "OPEN", "links": {"html": {"href": "https://bitbucket.org/ws/app/pull-requests/5"}}, "source": {"branch": {"name": "feature"}}, "destination": {"branch": {"name": "main"}}}]}`))
+				return
+			}
+			w.Write([]byte(`{"values": []}`))
+		case r.Method == "POST" && r.URL.Path == "/repositories/ws/app/pullrequests":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"type": "error", "error": {"message": "There are no changes to be pulled"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	b := NewBitbucket(Remote{Host: "bitbucket.org", Path: "ws/app"}, "jane:app-pass", server.URL)
+
+	pr, err := b.ExistingPR("feature")
+	if err != nil || pr == nil || pr.Number != 5 || pr.State != "open" || pr.Base != "main" {
+		t.Fatalf("ExistingPR(feature) = %+v, %v", pr, err)
+	}
+	if pr, err := b.ExistingPR("other"); err != nil || pr != nil {
+		t.Errorf("ExistingPR(other) = %+v, %v", pr, err)
+	}
+
+	_, err = b.CreatePR(NewPR{Title: "Add x", Head: "feature", Base: "develop"})
+	if err == nil || err.Error() != "create pull request: There are no changes to be pulled (status 400)" {
+		t.Errorf("CreatePR error = %v", err)
+	}
+	if dest, _ := created["destination"].(map[string]any); dest == nil {
+		t.Errorf("create request without destination: %v", created)
+	}
+}
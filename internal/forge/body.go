@@ -0,0 +1,32 @@
+package forge
+
+import "strings"
+
+// Markers around the generated part of a PR description. Text outside them
+// is left alone when yeet pr update regenerates the description.
+const (
+	BodyStart = "<!-- yeet:start -->"
+	BodyEnd   = "<!-- yeet:end -->"
+)
+
+// MarkBody wraps a generated description in the markers.
+func MarkBody(body string) string {
+	return BodyStart + "\n" + strings.TrimSpace(body) + "\n" + BodyEnd
+}
+
+// SpliceBody replaces the marked part of current with generated. When
+// current has no (complete) marked region the whole description is
+// replaced, and marked is false.
+func SpliceBody(current, generated string) (body string, marked bool) {
+	current = strings.ReplaceAll(current, "\r\n", "\n")
+	start := strings.Index(current, BodyStart)
+	if start < 0 {
+		return MarkBody(generated), false
+	}
+	end := strings.Index(current[start:], BodyEnd)
+	if end < 0 {
+		return MarkBody(generated), false
+	}
+	end += start + len(BodyEnd)
+	return current[:start] + MarkBody(generated) + current[end:], true
+}
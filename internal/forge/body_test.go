@@ -0,0 +1,26 @@
+package forge
+
+import "testing"
+
+func TestSpliceBody(t *testing.T) {
+	tests := []struct {
+		name, current, want string
+		marked              bool
+	}{
+		{"empty", "", MarkBody("new"), false},
+		{"unmarked", "written by hand", MarkBody("new"), false},
+		{
+			"keeps text around markers",
+			"Fixes #12\r\n\r\n" + BodyStart + "\r\nold\r\n" + BodyEnd + "\r\n\r\n## Notes\r\nby a human",
+			"Fixes #12\n\n" + BodyStart + "\nnew\n" + BodyEnd + "\n\n## Notes\nby a human",
+			true,
+		},
+		{"unterminated", BodyStart + "\nold", MarkBody("new"), false},
+	}
+	for _, tt := range tests {
+		got, marked := SpliceBody(tt.current, "new\n")
+		if got != tt.want || marked != tt.marked {
+			t.Errorf("%s: SpliceBody = %q, %v; want %q, %v", tt.name, got, marked, tt.want, tt.marked)
+		}
+	}
+}
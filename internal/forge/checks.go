@@ -0,0 +1,67 @@
+package forge
+
+import "fmt"
+
+// Check states, normalized across forges.
+const (
+	CheckPending = "pending"
+	CheckSuccess = "success"
+	CheckFailure = "failure"
+	CheckSkipped = "skipped"
+)
+
+// Check is one CI check, job or commit status on a PR's head commit.
+type Check struct {
+	Name  string
+	State string // one of the Check* states
+	URL   string // details page or job log
+}
+
+// Checker is implemented by forges that report CI status for a PR.
+type Checker interface {
+	Checks(number int) ([]Check, error)
+}
+
+// Merge strategies for Merger and AutoMerger.
+const (
+	MergeCommit = "merge"
+	MergeSquash = "squash"
+	MergeRebase = "rebase"
+)
+
+// Merger is implemented by forges that can merge a PR right away.
+type Merger interface {
+	MergePR(number int, strategy string) error
+}
+
+// AutoMerger is implemented by forges that can merge a PR on their own
+// once its checks pass.
+type AutoMerger interface {
+	EnableAutoMerge(number int, strategy string) error
+}
+
+// ChecksDone reports whether no check is pending.
+func ChecksDone(checks []Check) bool {
+	for _, c := range checks {
+		if c.State == CheckPending {
+			return false
+		}
+	}
+	return true
+}
+
+// FailedChecks returns the checks that failed.
+func FailedChecks(checks []Check) []Check {
+	var failed []Check
+	for _, c := range checks {
+		if c.State == CheckFailure {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// unsupportedStrategy is returned for strategies a forge can't do.
+func unsupportedStrategy(forge, strategy string) error {
+	return fmt.Errorf("%s does not support the %q merge strategy", forge, strategy)
+}
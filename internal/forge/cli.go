@@ -0,0 +1,346 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// --- GitHub ---
+
+// GitHubCLI implements Forge using the gh CLI. Detect falls back to it when
+// no GitHub token is available.
+type GitHubCLI struct {
+	Remote Remote // repository PRs are opened against; zero lets gh decide
+	Fork   string // "owner/repo" branches are pushed to, when not Remote
+}
+
+// repoArgs points gh at the target repository and returns the head
+// reference of branch ("owner:branch" for forks).
+func (c GitHubCLI) repoArgs(branch string) (head string, args []string) {
+	head = branch
+	if c.Fork != "" {
+		head = Remote{Path: c.Fork}.Owner() + ":" + branch
+	}
+	if c.Remote.Path != "" {
+		args = []string{"--repo", c.Remote.Host + "/" + c.Remote.Path}
+	}
+	return head, args
+}
+
+func (GitHubCLI) Name() string { return "GitHub" }
+
+func (c GitHubCLI) ExistingPR(branch string) (*PR, error) {
+	head, repo := c.repoArgs(branch)
+	pr, err := c.view(head, repo)
+	if err != nil || pr == nil || pr.State != "open" {
+		return nil, err
+	}
+	return pr, nil
+}
+
+func (c GitHubCLI) GetPR(number int) (*PR, error) {
+	_, repo := c.repoArgs("")
+	pr, err := c.view(strconv.Itoa(number), repo)
+	if err == nil && pr == nil {
+		return nil, fmt.Errorf("no pull request #%d", number)
+	}
+	return pr, err
+}
+
+// view runs gh pr view for a branch or PR number. It returns nil when gh
+// finds no pull request.
+func (c GitHubCLI) view(ref string, repo []string) (*PR, error) {
+	args := append([]string{"pr", "view", ref, "--json", "number,url,title,body,state,isDraft,headRefName,baseRefName"}, repo...)
+	out, err := exec.Command("gh", args...).Output()
+	if err != nil {
+		// gh exits non-zero when there is no such pull request.
+		return nil, nil
+	}
+	var pr struct {
+		Number      int    `json:"number"`
+		URL         string `json:"url"`
+		Title       string `json:"title"`
+		Body        string `json:"body"`
+		State       string `json:"state"`
+		IsDraft     bool   `json:"isDraft"`
+		HeadRefName string `json:"headRefName"`
+		BaseRefName string `json:"baseRefName"`
+	}
+	if err := json.Unmarshal(out, &pr); err != nil {
+		return nil, fmt.Errorf("cannot parse gh output: %w", err)
+	}
+	return &PR{
+		Number: pr.Number,
+		URL:    pr.URL,
+		Title:  pr.Title,
+		Body:   pr.Body,
+		Head:   pr.HeadRefName,
+		Base:   pr.BaseRefName,
+		State:  strings.ToLower(pr.State),
+		Draft:  pr.IsDraft,
+	}, nil
+}
+
+func (c GitHubCLI) CreatePR(pr NewPR) (*PR, error) {
+	head, repo := c.repoArgs(pr.Head)
+	args := append([]string{"pr", "create", "--title", pr.Title, "--body", pr.Body}, repo...)
+	if c.Fork != "" {
+		args = append(args, "--head", head)
+	}
+	if pr.Base != "" {
+		args = append(args, "--base", pr.Base)
+	}
+	args = append(args, metadataFlags(pr)...)
+	out, err := exec.Command("gh", args...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	url := strings.TrimSpace(string(out))
+	return &PR{Number: numberFromURL(url), URL: url, Title: pr.Title, Body: pr.Body, Head: pr.Head, Base: pr.Base, State: "open"}, nil
+}
+
+func (c GitHubCLI) UpdatePR(number int, body string) error {
+	_, repo := c.repoArgs("")
+	args := append([]string{"pr", "edit", strconv.Itoa(number), "--body", body}, repo...)
+	out, err := exec.Command("gh", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (c GitHubCLI) RetargetPR(number int, base string) error {
+	_, repo := c.repoArgs("")
+	args := append([]string{"pr", "edit", strconv.Itoa(number), "--base", base}, repo...)
+	out, err := exec.Command("gh", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Checks lists the PR's checks with gh pr checks, which exits non-zero
+// while checks are pending or failing but still prints them.
+func (c GitHubCLI) Checks(number int) ([]Check, error) {
+	_, repo := c.repoArgs("")
+	args := append([]string{"pr", "checks", strconv.Itoa(number), "--json", "name,bucket,link"}, repo...)
+	cmd := exec.Command("gh", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil && len(out) == 0 {
+		if strings.Contains(stderr.String(), "no checks reported") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+	}
+	var runs []struct {
+		Name   string `json:"name"`
+		Bucket string `json:"bucket"`
+		Link   string `json:"link"`
+	}
+	if err := json.Unmarshal(out, &runs); err != nil {
+		return nil, fmt.Errorf("cannot parse gh output: %w", err)
+	}
+	var checks []Check
+	for _, r := range runs {
+		state := CheckPending
+		switch r.Bucket {
+		case "pass":
+			state = CheckSuccess
+		case "fail", "cancel":
+			state = CheckFailure
+		case "skipping":
+			state = CheckSkipped
+		}
+		checks = append(checks, Check{Name: r.Name, State: state, URL: r.Link})
+	}
+	return checks, nil
+}
+
+func (c GitHubCLI) MergePR(number int, strategy string) error {
+	return c.merge(number, strategy)
+}
+
+func (c GitHubCLI) EnableAutoMerge(number int, strategy string) error {
+	return c.merge(number, strategy, "--auto")
+}
+
+func (c GitHubCLI) merge(number int, strategy string, flags ...string) error {
+	_, repo := c.repoArgs("")
+	args := append([]string{"pr", "merge", strconv.Itoa(number), "--" + strategy}, repo...)
+	out, err := exec.Command("gh", append(args, flags...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// metadataFlags returns the draft/label/reviewer/assignee flags, which
+// gh and glab spell the same way.
+func metadataFlags(pr NewPR) []string {
+	var args []string
+	if pr.Draft {
+		args = append(args, "--draft")
+	}
+	for _, flag := range []struct {
+		name   string
+		values []string
+	}{{"--label", pr.Labels}, {"--reviewer", pr.Reviewers}, {"--assignee", pr.Assignees}} {
+		for _, v := range flag.values {
+			args = append(args, flag.name, v)
+		}
+	}
+	return args
+}
+
+// SubmitReview creates a pending review through gh api, since gh pr review
+// can't comment on lines.
+func (c GitHubCLI) SubmitReview(number int, body string, comments []ReviewComment) (string, error) {
+	cs := []map[string]any{}
+	for _, rc := range comments {
+		cs = append(cs, map[string]any{"path": rc.Path, "line": rc.Line, "side": "RIGHT", "body": rc.Body})
+	}
+	req, err := json.Marshal(map[string]any{"body": body, "comments": cs})
+	if err != nil {
+		return "", err
+	}
+	// gh fills in {owner}/{repo} from the current repository.
+	repo, args := "{owner}/{repo}", []string{}
+	if c.Remote.Path != "" {
+		repo, args = c.Remote.Path, []string{"--hostname", c.Remote.Host}
+	}
+	args = append([]string{"api", "--method", "POST", fmt.Sprintf("repos/%s/pulls/%d/reviews", repo, number), "--input", "-"}, args...)
+	cmd := exec.Command("gh", args...)
+	cmd.Stdin = strings.NewReader(string(req))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	json.Unmarshal(out, &created)
+	return created.HTMLURL, nil
+}
+
+// numberFromURL returns the number a PR or MR URL ends in, or 0.
+func numberFromURL(url string) int {
+	n, _ := strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+	return n
+}
+
+// CreateRelease runs gh release create for an existing tag.
+func (c GitHubCLI) CreateRelease(r Release) (string, error) {
+	_, repo := c.repoArgs("")
+	args := append([]string{"release", "create", r.Tag, "--verify-tag", "--title", r.Name, "--notes", r.Body}, repo...)
+	if r.Prerelease {
+		args = append(args, "--prerelease")
+	}
+	out, err := exec.Command("gh", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return lastLine(string(out)), nil
+}
+
+// lastLine returns the last non-empty line of CLI output, where gh and
+// glab print the URL of what they created.
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// --- GitLab ---
+
+// GitLabCLI implements Forge using the glab CLI.
+type GitLabCLI struct {
+	Remote Remote // project MRs are opened against; zero lets glab decide
+	Fork   string // project path branches are pushed to, when not Remote
+}
+
+// repoArgs points glab at the target project.
+func (c GitLabCLI) repoArgs() []string {
+	if c.Remote.Path == "" {
+		return nil
+	}
+	return []string{"--repo", "https://" + c.Remote.Host + "/" + c.Remote.Path}
+}
+
+func (GitLabCLI) Name() string { return "GitLab" }
+
+func (c GitLabCLI) ExistingPR(branch string) (*PR, error) {
+	args := append([]string{"mr", "view", branch, "--output", "json"}, c.repoArgs()...)
+	out, err := exec.Command("glab", args...).Output()
+	if err != nil {
+		// glab exits non-zero when the branch has no open merge request.
+		return nil, nil
+	}
+	var mr gitlabMR
+	if err := json.Unmarshal(out, &mr); err != nil {
+		return nil, fmt.Errorf("cannot parse glab output: %w", err)
+	}
+	if mr.State != "opened" {
+		return nil, nil
+	}
+	return mr.toPR(), nil
+}
+
+func (c GitLabCLI) CreatePR(pr NewPR) (*PR, error) {
+	args := append([]string{"mr", "create", "--fill", "--title", pr.Title, "--description", pr.Body}, c.repoArgs()...)
+	if c.Fork != "" {
+		args = append(args, "--head", c.Fork)
+	}
+	if pr.Base != "" {
+		args = append(args, "--target-branch", pr.Base)
+	}
+	args = append(args, metadataFlags(pr)...)
+	out, err := exec.Command("glab", args...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	created := &PR{Title: pr.Title, Body: pr.Body, Head: pr.Head, Base: pr.Base, State: "open"}
+	// Extract URL from glab output
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "http") {
+			created.URL = line
+			created.Number = numberFromURL(line)
+			return created, nil
+		}
+	}
+	created.URL = strings.TrimSpace(string(out))
+	created.Number = numberFromURL(created.URL)
+	return created, nil
+}
+
+func (c GitLabCLI) UpdatePR(number int, body string) error {
+	args := append([]string{"mr", "update", strconv.Itoa(number), "--description", body}, c.repoArgs()...)
+	out, err := exec.Command("glab", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// CreateRelease runs glab release create for an existing tag.
+func (c GitLabCLI) CreateRelease(r Release) (string, error) {
+	args := append([]string{"release", "create", r.Tag, "--name", r.Name, "--notes", r.Body}, c.repoArgs()...)
+	out, err := exec.Command("glab", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return lastLine(string(out)), nil
+}
+
+func (c GitLabCLI) RetargetPR(number int, base string) error {
+	args := append([]string{"mr", "update", strconv.Itoa(number), "--target-branch", base}, c.repoArgs()...)
+	out, err := exec.Command("glab", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/xdg"
+)
+
+// probeTimeout bounds the probes of a host, which run concurrently, so an
+// unreachable host doesn't stall yeet pr.
+var probeTimeout = 4 * time.Second
+
+// detected caches the probed forge type of each host for this run; "" means
+// the probes found nothing and GitHub is assumed.
+var detected = struct {
+	sync.Mutex
+	types map[string]string
+}{types: map[string]string{}}
+
+// detectType guesses the forge type of a host that has no [forges] entry:
+// well-known names first, then a probe of the host's API endpoints.
+// Hosts that do not answer are treated as GitHub.
+func detectType(host string) string {
+	switch {
+	case host == "github.com" || strings.HasPrefix(host, "github."):
+		return config.ForgeGitHub
+	case host == "gitlab.com" || strings.Contains(host, "gitlab"):
+		return config.ForgeGitLab
+	case host == "bitbucket.org":
+		return config.ForgeBitbucket
+	case host == "codeberg.org":
+		return config.ForgeForgejo
+	}
+	if kind := probedType(host); kind != "" {
+		return kind
+	}
+	return config.ForgeGitHub
+}
+
+// Guessed reports whether host was probed without finding out which forge
+// it runs, so GitHub was assumed. A [forges] entry for it avoids that.
+func Guessed(host string) bool {
+	detected.Lock()
+	defer detected.Unlock()
+	kind, ok := detected.types[host]
+	return ok && kind == ""
+}
+
+// probedType returns the forge type of host, probing it once per run.
+// Successful probes are remembered in forge-types.json, so later runs
+// don't probe again.
+func probedType(host string) string {
+	detected.Lock()
+	defer detected.Unlock()
+	if kind, ok := detected.types[host]; ok {
+		return kind
+	}
+	types := loadForgeTypes()
+	kind := types[host]
+	if kind == "" {
+		if kind = probeType("https://" + host); kind != "" {
+			types[host] = kind
+			saveForgeTypes(types)
+		}
+	}
+	detected.types[host] = kind
+	return kind
+}
+
+func forgeTypesPath() string {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "yeet", "forge-types.json")
+}
+
+func loadForgeTypes() map[string]string {
+	types := map[string]string{}
+	if data, err := os.ReadFile(forgeTypesPath()); err == nil {
+		json.Unmarshal(data, &types)
+	}
+	return types
+}
+
+// saveForgeTypes stores the probed types. It is only a cache, so failures
+// are ignored.
+func saveForgeTypes(types map[string]string) {
+	path := forgeTypesPath()
+	if path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(types, "", "  ")
+	if err != nil || os.MkdirAll(filepath.Dir(path), 0o755) != nil {
+		return
+	}
+	os.WriteFile(path, data, 0o644)
+}
+
+// probeType asks a self-hosted instance which forge it runs, using
+// endpoints that answer without credentials. All endpoints are asked at
+// once; the first match in the order below wins.
+func probeType(base string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	probes := []struct {
+		kind  string
+		match func() bool
+	}{
+		// GitHub Enterprise Server: public metadata with the installed version.
+		{config.ForgeGitHub, func() bool {
+			var meta struct {
+				InstalledVersion string `json:"installed_version"`
+			}
+			return probeJSON(ctx, base+"/api/v3/meta", &meta) == http.StatusOK && meta.InstalledVersion != ""
+		}},
+		// GitLab: the version endpoint exists but requires a token.
+		{config.ForgeGitLab, func() bool {
+			status := probeJSON(ctx, base+"/api/v4/version", nil)
+			return status == http.StatusOK || status == http.StatusUnauthorized
+		}},
+		// Forgejo adds its own version endpoint next to the Gitea-compatible one.
+		{config.ForgeForgejo, func() bool { return probeVersion(ctx, base+"/api/forgejo/v1/version") }},
+		{config.ForgeGitea, func() bool { return probeVersion(ctx, base+"/api/v1/version") }},
+	}
+
+	matched := make([]bool, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Go(func() { matched[i] = p.match() })
+	}
+	wg.Wait()
+	for i, p := range probes {
+		if matched[i] {
+			return p.kind
+		}
+	}
+	return ""
+}
+
+// probeVersion reports whether url answers with a Gitea-style version.
+func probeVersion(ctx context.Context, url string) bool {
+	var version struct {
+		Version string `json:"version"`
+	}
+	return probeJSON(ctx, url, &version) == http.StatusOK && version.Version != ""
+}
+
+// probeJSON fetches url and decodes a JSON body into result (if non-nil).
+// It returns the HTTP status, or 0 when the request failed.
+func probeJSON(ctx context.Context, url string, result any) int {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0
+	}
+	defer resp.Body.Close()
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return 0
+	}
+	if result != nil && json.NewDecoder(resp.Body).Decode(result) != nil {
+		return 0
+	}
+	return resp.StatusCode
+}
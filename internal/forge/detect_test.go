@@ -0,0 +1,131 @@
+package forge
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rasalas/yeet/internal/config"
+)
+
+func TestDetectTypeByName(t *testing.T) {
+	tests := map[string]string{
+		"github.com":        config.ForgeGitHub,
+		"github.corp.com":   config.ForgeGitHub,
+		"gitlab.com":        config.ForgeGitLab,
+		"gitlab.company.io": config.ForgeGitLab,
+		"bitbucket.org":     config.ForgeBitbucket,
+		"codeberg.org":      config.ForgeForgejo,
+	}
+	for host, want := range tests {
+		if got := detectType(host); got != want {
+			t.Errorf("detectType(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestProbeType(t *testing.T) {
+	gitlab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v4/version" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "401 Unauthorized"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "404 Not Found"}`))
+	}))
+	defer gitlab.Close()
+	if got := probeType(gitlab.URL); got != config.ForgeGitLab {
+		t.Errorf("probeType(gitlab) = %q", got)
+	}
+
+	ghe := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v3/meta" {
+			w.Write([]byte(`{"installed_version": "3.14.2"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ghe.Close()
+	if got := probeType(ghe.URL); got != config.ForgeGitHub {
+		t.Errorf("probeType(ghe) = %q", got)
+	}
+
+	gitea := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/version" {
+			w.Write([]byte(`{"version": "1.22.0"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "not found"}`))
+	}))
+	defer gitea.Close()
+	if got := probeType(gitea.URL); got != config.ForgeGitea {
+		t.Errorf("probeType(gitea) = %q", got)
+	}
+
+	html := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>login</html>"))
+	}))
+	defer html.Close()
+	if got := probeType(html.URL); got != "" {
+		t.Errorf("probeType(html) = %q, want none", got)
+	}
+}
+
+func TestProbeTypeDeadline(t *testing.T) {
+	defer func(d time.Duration) { probeTimeout = d }(probeTimeout)
+	probeTimeout = 200 * time.Millisecond
+
+	// A hanging endpoint costs one deadline, not one per probe.
+	release := make(chan struct{})
+	gitea := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/version" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"version": "1.22.0"}`))
+			return
+		}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer gitea.Close()
+	defer close(release)
+
+	start := time.Now()
+	if got := probeType(gitea.URL); got != config.ForgeGitea {
+		t.Errorf("probeType = %q, want gitea", got)
+	}
+	if d := time.Since(start); d > 2*probeTimeout {
+		t.Errorf("probeType took %s with a %s deadline", d, probeTimeout)
+	}
+}
+
+func TestProbedTypeIsCached(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	defer func(d time.Duration) { probeTimeout = d }(probeTimeout)
+	probeTimeout = 200 * time.Millisecond
+
+	saveForgeTypes(map[string]string{"git.example.com": config.ForgeGitea})
+	if got := detectType("git.example.com"); got != config.ForgeGitea || Guessed("git.example.com") {
+		t.Errorf("detectType = %q, want the stored gitea", got)
+	}
+
+	// Nothing listens here: GitHub is assumed, and the guess is neither
+	// probed again nor stored.
+	closed := httptest.NewServer(nil)
+	closed.Close()
+	host := strings.TrimPrefix(closed.URL, "http://")
+	if got := detectType(host); got != config.ForgeGitHub || !Guessed(host) {
+		t.Errorf("detectType(%s) = %q, guessed %t", host, got, Guessed(host))
+	}
+	if _, ok := loadForgeTypes()[host]; ok {
+		t.Errorf("failed probe of %s was stored", host)
+	}
+}
@@ -4,119 +4,225 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/rasalas/yeet/internal/config"
+	"github.com/rasalas/yeet/internal/git"
 )
 
-// Forge abstracts GitHub and GitLab PR/MR operations.
+// PR is a pull request (merge request on GitLab) as reported by the forge.
+// CLI-backed forges may only fill in URL.
+type PR struct {
+	Number int
+	URL    string
+	Title  string
+	Body   string
+	Head   string // source branch
+	Base   string // target branch
+	State  string // "open", "closed" or "merged"
+	Draft  bool
+}
+
+// NewPR describes a pull request to create.
+type NewPR struct {
+	Title string
+	Body  string
+	Head  string // source branch
+	Base  string // target branch; "" uses the repository default
+	Draft bool
+
+	// Usernames (teams as "org/team" where the forge supports them) and label names.
+	Labels    []string
+	Reviewers []string
+	Assignees []string
+}
+
+// Forge abstracts PR/MR operations of a code hosting service.
 type Forge interface {
 	Name() string
-	CLIName() string
-	ExistingPR(branch string) (url string, exists bool)
-	CreatePR(title, body, base string) (url string, err error)
+	// ExistingPR returns the open PR for branch, or nil if there is none.
+	ExistingPR(branch string) (*PR, error)
+	// CreatePR opens a pull request. When the PR was created but labels,
+	// reviewers or assignees could not be applied, it returns the PR
+	// together with a *MetadataError.
+	CreatePR(pr NewPR) (*PR, error)
 }
 
-// Detect returns the appropriate Forge for the current repository.
-// It inspects the origin remote URL and checks for the corresponding CLI tool.
-func Detect() (Forge, error) {
-	out, err := exec.Command("git", "remote", "get-url", "origin").CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("no git remote 'origin' found")
-	}
+// Updater is implemented by forges that can replace the description of
+// an existing PR.
+type Updater interface {
+	UpdatePR(number int, body string) error
+}
 
-	remote := strings.TrimSpace(string(out))
+// Retargeter is implemented by forges that can change the base branch of
+// an existing PR, e.g. to move a stacked PR down after its parent merged.
+type Retargeter interface {
+	RetargetPR(number int, base string) error
+}
 
-	if strings.Contains(remote, "gitlab") {
-		if _, err := exec.LookPath("glab"); err != nil {
-			return nil, fmt.Errorf("GitLab remote detected but 'glab' CLI is not installed")
-		}
-		return GitLab{}, nil
-	}
+// Templater is implemented by forges that read PR description templates
+// from the repository. Paths are globs relative to the repository root,
+// most specific first.
+type Templater interface {
+	TemplatePaths() []string
+}
 
-	if _, err := exec.LookPath("gh"); err != nil {
-		return nil, fmt.Errorf("GitHub remote detected but 'gh' CLI is not installed")
-	}
-	return GitHub{}, nil
+// UserResolver is implemented by forges that can map a commit email to a
+// username, for suggesting reviewers from git history.
+type UserResolver interface {
+	UserForEmail(email string) (string, error)
 }
 
-// --- GitHub ---
+// Getter is implemented by forges that can look up a PR by number, e.g.
+// to title changelog entries with the PRs they came from.
+type Getter interface {
+	GetPR(number int) (*PR, error)
+}
 
-// GitHub implements Forge using the gh CLI.
-type GitHub struct{}
+// Release describes a forge release of a pushed tag.
+type Release struct {
+	Tag        string
+	Name       string
+	Body       string // Markdown release notes
+	Prerelease bool
+}
 
-func (GitHub) Name() string    { return "GitHub" }
-func (GitHub) CLIName() string { return "gh" }
+// Releaser is implemented by forges that can publish a release for a tag.
+// CreateRelease returns the release's URL.
+type Releaser interface {
+	CreateRelease(r Release) (string, error)
+}
 
-func (GitHub) ExistingPR(branch string) (string, bool) {
-	out, err := exec.Command("gh", "pr", "view", branch, "--json", "url", "--jq", ".url").CombinedOutput()
-	if err != nil {
-		return "", false
-	}
-	url := strings.TrimSpace(string(out))
-	if url == "" {
-		return "", false
-	}
-	return url, true
+// ReviewComment is a review comment on a line of a PR's new version.
+type ReviewComment struct {
+	Path    string
+	Line    int // line in the new version
+	OldLine int // line in the old version for unchanged lines, else 0
+	Body    string
 }
 
-func (GitHub) CreatePR(title, body, base string) (string, error) {
-	args := []string{"pr", "create", "--title", title, "--body", body}
-	if base != "" {
-		args = append(args, "--base", base)
-	}
-	out, err := exec.Command("gh", args...).CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("%s", strings.TrimSpace(string(out)))
-	}
-	return strings.TrimSpace(string(out)), nil
+// Reviewer is implemented by forges that can add line comments to a PR
+// as a pending review, which its author then submits on the forge. It
+// returns a URL to view the review at.
+type Reviewer interface {
+	SubmitReview(number int, body string, comments []ReviewComment) (string, error)
 }
 
-// --- GitLab ---
+// partialReviewErr adds to err how many comments of a review were already
+// posted as drafts, which stay on the PR until submitted or deleted.
+func partialReviewErr(err error, posted int, drafts string) error {
+	if posted == 0 {
+		return err
+	}
+	return fmt.Errorf("%w (%d %s already added — submit or delete them on the PR)", err, posted, drafts)
+}
 
-// GitLab implements Forge using the glab CLI.
-type GitLab struct{}
+// MetadataError reports PR metadata that could not be applied after the
+// PR itself was created.
+type MetadataError struct {
+	Errs []error
+}
 
-func (GitLab) Name() string    { return "GitLab" }
-func (GitLab) CLIName() string { return "glab" }
+func (e *MetadataError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
 
-func (GitLab) ExistingPR(branch string) (string, bool) {
-	out, err := exec.Command("glab", "mr", "view", branch, "--output", "json").CombinedOutput()
-	if err != nil {
-		return "", false
+// metadataErr returns a *MetadataError for the non-nil errs, or nil.
+func metadataErr(errs ...error) error {
+	var out []error
+	for _, err := range errs {
+		if err != nil {
+			out = append(out, err)
+		}
 	}
-	// glab mr view exits 0 and returns JSON when MR exists
-	text := strings.TrimSpace(string(out))
-	if text == "" || strings.Contains(text, "no open merge request") {
-		return "", false
+	if len(out) == 0 {
+		return nil
 	}
-	// Extract web_url from output — glab prints it on a "URL:" line in default mode
-	// Use --output json not available on all versions, fall back to plain view
-	viewOut, err := exec.Command("glab", "mr", "view", branch).CombinedOutput()
+	return &MetadataError{Errs: out}
+}
+
+// Detect returns the Forge for PRs against the target remote. It inspects
+// the remote URL and prefers the native API clients, falling back to the
+// forge's CLI when no token is available. When branches are pushed to
+// another repository on the same host (a fork), PR heads refer to it.
+func Detect(cfg config.Config, target, push string) (Forge, error) {
+	remote, err := RemoteNamed(target)
 	if err != nil {
-		return "", false
+		return nil, err
 	}
-	for _, line := range strings.Split(string(viewOut), "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "url:") || strings.HasPrefix(line, "URL:") {
-			return strings.TrimSpace(strings.SplitN(line, ":", 2)[1]), true
+	var fork string
+	if push != target {
+		if p, err := RemoteNamed(push); err == nil && p.Host == remote.Host && p.Path != remote.Path {
+			fork = p.Path
 		}
 	}
-	return "", true
+	return forRemote(remote, fork, cfg.Forges[remote.Host])
 }
 
-func (GitLab) CreatePR(title, body, base string) (string, error) {
-	args := []string{"mr", "create", "--fill", "--title", title, "--description", body}
-	if base != "" {
-		args = append(args, "--target-branch", base)
-	}
-	out, err := exec.Command("glab", args...).CombinedOutput()
+// RemoteNamed parses the URL of a git remote.
+func RemoteNamed(name string) (Remote, error) {
+	url, err := git.RemoteURL(name)
 	if err != nil {
-		return "", fmt.Errorf("%s", strings.TrimSpace(string(out)))
+		return Remote{}, err
+	}
+	return ParseRemote(url)
+}
+
+// forRemote builds the Forge for a parsed remote. fork is the path of the
+// repository PR branches live in, or "" when it is the remote itself.
+func forRemote(remote Remote, fork string, fc config.ForgeConfig) (Forge, error) {
+	kind := fc.Type
+	if kind == "" {
+		kind = detectType(remote.Host)
 	}
-	// Extract URL from glab output
-	for _, line := range strings.Split(string(out), "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "http") {
-			return line, nil
+
+	switch kind {
+	case config.ForgeGitLab:
+		if token := gitlabToken(remote.Host); token != "" {
+			g := NewGitLab(remote, token, fc.APIURL)
+			g.Fork = fork
+			return g, nil
+		}
+		if _, err := exec.LookPath("glab"); err == nil {
+			return GitLabCLI{Remote: remote, Fork: fork}, nil
+		}
+		return nil, fmt.Errorf("no GitLab token for %s — run: yeet auth forge %s (or install the glab CLI)", remote.Host, remote.Host)
+
+	case config.ForgeGitea, config.ForgeForgejo:
+		flavor := "Gitea"
+		if kind == config.ForgeForgejo {
+			flavor = "Forgejo"
+		}
+		if token := Token(remote.Host, "FORGEJO_TOKEN", "GITEA_TOKEN"); token != "" {
+			g := NewGitea(remote, token, fc.APIURL, flavor)
+			g.Fork = fork
+			return g, nil
+		}
+		return nil, fmt.Errorf("no %s token for %s — run: yeet auth forge %s", flavor, remote.Host, remote.Host)
+
+	case config.ForgeBitbucket:
+		if token := bitbucketToken(remote.Host); token != "" {
+			b := NewBitbucket(remote, token, fc.APIURL)
+			b.Fork = fork
+			return b, nil
+		}
+		return nil, fmt.Errorf("no Bitbucket token for %s — run: yeet auth forge %s (access token, or username:app-password)", remote.Host, remote.Host)
+
+	default:
+		if token := githubToken(remote.Host); token != "" {
+			g := NewGitHub(remote, token)
+			g.Fork = fork
+			if fc.APIURL != "" {
+				g.BaseURL = fc.APIURL
+			}
+			return g, nil
+		}
+		if _, err := exec.LookPath("gh"); err == nil {
+			return GitHubCLI{Remote: remote, Fork: fork}, nil
 		}
+		return nil, fmt.Errorf("no GitHub token for %s — run: yeet auth forge %s (or install the gh CLI)", remote.Host, remote.Host)
 	}
-	return strings.TrimSpace(string(out)), nil
 }
@@ -0,0 +1,315 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Gitea implements Forge with the Gitea REST API, which Forgejo
+// (e.g. codeberg.org) shares.
+type Gitea struct {
+	Remote  Remote
+	Token   string
+	BaseURL string // API root, e.g. "https://codeberg.org/api/v1"
+	Flavor  string // "Gitea" or "Forgejo", for messages
+	Fork    string // "owner/repo" branches are pushed to, when not Remote
+}
+
+// NewGitea returns a Gitea/Forgejo client for the remote's repository. An
+// empty apiURL uses /api/v1 on the remote's host.
+func NewGitea(remote Remote, token, apiURL, flavor string) *Gitea {
+	if apiURL == "" {
+		apiURL = "https://" + remote.Host + "/api/v1"
+	}
+	return &Gitea{Remote: remote, Token: token, BaseURL: apiURL, Flavor: flavor}
+}
+
+func (g *Gitea) Name() string { return g.Flavor }
+
+// giteaPR is the pull request object of the REST API.
+type giteaPR struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	State   string `json:"state"`
+	Merged  bool   `json:"merged"`
+	Draft   bool   `json:"draft"`
+	Head    struct {
+		Ref  string `json:"ref"`
+		SHA  string `json:"sha"`
+		Repo struct {
+			FullName string `json:"full_name"`
+		} `json:"repo"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p giteaPR) toPR() *PR {
+	state := p.State
+	if p.Merged {
+		state = "merged"
+	}
+	return &PR{
+		Number: p.Number,
+		URL:    p.HTMLURL,
+		Title:  p.Title,
+		Body:   p.Body,
+		Head:   p.Head.Ref,
+		Base:   p.Base.Ref,
+		State:  state,
+		Draft:  p.Draft,
+	}
+}
+
+func (g *Gitea) do(method, path string, body, result any) error {
+	headers := map[string]string{"Authorization": "token " + g.Token}
+	return doJSON(method, g.BaseURL+path, headers, body, result)
+}
+
+func (g *Gitea) repoPath() string {
+	return "/repos/" + g.Remote.Owner() + "/" + g.Remote.Repo()
+}
+
+// giteaPageSize is the page size for PR listings (the API's default maximum).
+const giteaPageSize = 50
+
+func (g *Gitea) ExistingPR(branch string) (*PR, error) {
+	// The list endpoint cannot filter by head branch, so page through open PRs.
+	for page := 1; ; page++ {
+		query := url.Values{
+			"state": {"open"},
+			"limit": {strconv.Itoa(giteaPageSize)},
+			"page":  {strconv.Itoa(page)},
+		}
+		var prs []giteaPR
+		if err := g.do("GET", g.repoPath()+"/pulls?"+query.Encode(), nil, &prs); err != nil {
+			return nil, err
+		}
+		for _, p := range prs {
+			if p.Head.Ref == branch && (g.Fork == "" || strings.EqualFold(p.Head.Repo.FullName, g.Fork)) {
+				return p.toPR(), nil
+			}
+		}
+		if len(prs) < giteaPageSize {
+			return nil, nil
+		}
+	}
+}
+
+func (g *Gitea) CreatePR(pr NewPR) (*PR, error) {
+	base := pr.Base
+	if base == "" {
+		var repo struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := g.do("GET", g.repoPath(), nil, &repo); err != nil {
+			return nil, err
+		}
+		base = repo.DefaultBranch
+	}
+
+	title := pr.Title
+	if pr.Draft && !strings.HasPrefix(strings.ToUpper(title), "WIP:") {
+		title = "WIP: " + title
+	}
+	head := pr.Head
+	if g.Fork != "" {
+		head = Remote{Path: g.Fork}.Owner() + ":" + head
+	}
+	req := map[string]any{
+		"title": title,
+		"body":  pr.Body,
+		"head":  head,
+		"base":  base,
+	}
+	if len(pr.Assignees) > 0 {
+		req["assignees"] = pr.Assignees
+	}
+	labels, labelErr := g.labelIDs(pr.Labels)
+	if len(labels) > 0 {
+		req["labels"] = labels
+	}
+
+	var created giteaPR
+	if err := g.do("POST", g.repoPath()+"/pulls", req, &created); err != nil {
+		return nil, fmt.Errorf("create pull request: %w", err)
+	}
+
+	if labelErr != nil {
+		labelErr = fmt.Errorf("labels: %w", labelErr)
+	}
+	var reviewerErr error
+	if len(pr.Reviewers) > 0 {
+		users, teams := splitTeams(pr.Reviewers)
+		req := map[string]any{"reviewers": users, "team_reviewers": teams}
+		if err := g.do("POST", fmt.Sprintf("%s/pulls/%d/requested_reviewers", g.repoPath(), created.Number), req, nil); err != nil {
+			reviewerErr = fmt.Errorf("reviewers: %w", err)
+		}
+	}
+	return created.toPR(), metadataErr(labelErr, reviewerErr)
+}
+
+func (g *Gitea) UpdatePR(number int, body string) error {
+	path := fmt.Sprintf("%s/pulls/%d", g.repoPath(), number)
+	if err := g.do("PATCH", path, map[string]any{"body": body}, nil); err != nil {
+		return fmt.Errorf("update pull request: %w", err)
+	}
+	return nil
+}
+
+func (g *Gitea) RetargetPR(number int, base string) error {
+	path := fmt.Sprintf("%s/pulls/%d", g.repoPath(), number)
+	if err := g.do("PATCH", path, map[string]any{"base": base}, nil); err != nil {
+		return fmt.Errorf("retarget pull request: %w", err)
+	}
+	return nil
+}
+
+func (g *Gitea) GetPR(number int) (*PR, error) {
+	var pr giteaPR
+	if err := g.do("GET", fmt.Sprintf("%s/pulls/%d", g.repoPath(), number), nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toPR(), nil
+}
+
+// Checks returns the commit statuses of the PR's head commit, which is
+// also where Gitea and Forgejo Actions report their jobs.
+func (g *Gitea) Checks(number int) ([]Check, error) {
+	var pr giteaPR
+	if err := g.do("GET", fmt.Sprintf("%s/pulls/%d", g.repoPath(), number), nil, &pr); err != nil {
+		return nil, err
+	}
+	var status struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			Status    string `json:"status"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := g.do("GET", g.repoPath()+"/commits/"+pr.Head.SHA+"/status", nil, &status); err != nil {
+		return nil, err
+	}
+	var checks []Check
+	for _, s := range status.Statuses {
+		state := s.Status
+		switch state {
+		case "error":
+			state = CheckFailure
+		case "warning":
+			state = CheckSuccess
+		}
+		checks = append(checks, Check{Name: s.Context, State: state, URL: s.TargetURL})
+	}
+	return checks, nil
+}
+
+func (g *Gitea) MergePR(number int, strategy string) error {
+	return g.merge(number, strategy, false)
+}
+
+// EnableAutoMerge schedules the PR to merge once its checks succeed.
+func (g *Gitea) EnableAutoMerge(number int, strategy string) error {
+	return g.merge(number, strategy, true)
+}
+
+func (g *Gitea) merge(number int, strategy string, auto bool) error {
+	req := map[string]any{"Do": strategy}
+	if auto {
+		req["merge_when_checks_succeed"] = true
+	}
+	path := fmt.Sprintf("%s/pulls/%d/merge", g.repoPath(), number)
+	if err := g.do("POST", path, req, nil); err != nil {
+		return fmt.Errorf("merge pull request: %w", err)
+	}
+	return nil
+}
+
+// SubmitReview creates a review in the PENDING state, which its author
+// submits from the PR's "Files changed" tab.
+func (g *Gitea) SubmitReview(number int, body string, comments []ReviewComment) (string, error) {
+	cs := []map[string]any{}
+	for _, c := range comments {
+		cs = append(cs, map[string]any{"path": c.Path, "new_position": c.Line, "body": c.Body})
+	}
+	req := map[string]any{"body": body, "event": "PENDING", "comments": cs}
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := g.do("POST", fmt.Sprintf("%s/pulls/%d/reviews", g.repoPath(), number), req, &created); err != nil {
+		return "", fmt.Errorf("create review: %w", err)
+	}
+	return created.HTMLURL, nil
+}
+
+// labelIDs resolves label names (case-insensitively) to the repository's
+// label IDs, returning the IDs found and an error naming the rest.
+func (g *Gitea) labelIDs(names []string) ([]int, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	var labels []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := g.do("GET", g.repoPath()+"/labels?limit=50", nil, &labels); err != nil {
+		return nil, err
+	}
+	var ids []int
+	var missing []string
+	for _, name := range names {
+		found := false
+		for _, l := range labels {
+			if strings.EqualFold(l.Name, name) {
+				ids = append(ids, l.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return ids, fmt.Errorf("unknown label(s) %s", strings.Join(missing, ", "))
+	}
+	return ids, nil
+}
+
+// CreateRelease publishes a release, marked as a pre-release when the tag
+// is one.
+func (g *Gitea) CreateRelease(r Release) (string, error) {
+	req := map[string]any{"tag_name": r.Tag, "name": r.Name, "body": r.Body, "prerelease": r.Prerelease}
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := g.do("POST", g.repoPath()+"/releases", req, &created); err != nil {
+		return "", fmt.Errorf("create release: %w", err)
+	}
+	return created.HTMLURL, nil
+}
+
+// UserForEmail finds the user whose visible email matches.
+func (g *Gitea) UserForEmail(email string) (string, error) {
+	var result struct {
+		Data []struct {
+			Login string `json:"login"`
+			Email string `json:"email"`
+		} `json:"data"`
+	}
+	query := url.Values{"q": {email}}
+	if err := g.do("GET", "/users/search?"+query.Encode(), nil, &result); err != nil {
+		return "", err
+	}
+	for _, u := range result.Data {
+		if strings.EqualFold(u.Email, email) {
+			return u.Login, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,63 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitea(t *testing.T) {
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token gt-test" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/acme/app/pulls":
+			// A full first page without the branch, then the match on page 2.
+			if r.URL.Query().Get("page") == "1" {
+				var prs []string
+				for i := range giteaPageSize {
+					prs = append(prs, fmt.Sprintf(`{"number": %d, "head": {"ref": "b%d"}}`, i+1, i))
+				}
+				fmt.Fprintf(w, "[%s]", strings.Join(prs, ","))
+				return
+			}
+			w.Write([]byte(`[{"number": 99, "html_url": "https://codeberg.org/acme/app/pulls/99", "state": "open", "head": {"ref": "feature"}, "base": {"ref": "main"}}]`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/acme/app":
+			w.Write([]byte(`{"default_branch": "main"}`))
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/acme/app/pulls":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 100, "html_url": "https://codeberg.org/acme/app/pulls/100", "state": "open"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g := NewGitea(Remote{Host: "codeberg.org", Path: "acme/app"}, "gt-test", server.URL+"/api/v1", "Forgejo")
+	if g.Name() != "Forgejo" {
+		t.Errorf("Name() = %q", g.Name())
+	}
+
+	pr, err := g.ExistingPR("feature")
+	if err != nil || pr == nil || pr.Number != 99 {
+		t.Fatalf("ExistingPR(feature) = %+v, %v", pr, err)
+	}
+	if pr, err := g.ExistingPR("missing"); err != nil || pr != nil {
+		t.Errorf("ExistingPR(missing) = %+v, %v", pr, err)
+	}
+
+	pr, err = g.CreatePR(NewPR{Title: "Add x", Body: "body", Head: "feature"})
+	if err != nil || pr.Number != 100 {
+		t.Fatalf("CreatePR = %+v, %v", pr, err)
+	}
+	if created["base"] != "main" || created["head"] != "feature" {
+		t.Errorf("create request = %v", created)
+	}
+}
@@ -0,0 +1,363 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GitHub implements Forge with the GitHub REST API. It works with
+// github.com and GitHub Enterprise Server.
+type GitHub struct {
+	Remote  Remote
+	Token   string
+	BaseURL string // API root, e.g. "https://api.github.com"
+	Fork    string // "owner/repo" branches are pushed to, when not Remote
+}
+
+// NewGitHub returns a GitHub client for the remote's repository, using the
+// API root that belongs to its host.
+func NewGitHub(remote Remote, token string) *GitHub {
+	return &GitHub{Remote: remote, Token: token, BaseURL: githubAPIURL(remote.Host)}
+}
+
+// githubAPIURL returns the REST API root of a GitHub host. Enterprise Server
+// serves the API under /api/v3 on the instance's own host.
+func githubAPIURL(host string) string {
+	if host == "github.com" {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+func (*GitHub) Name() string { return "GitHub" }
+
+// githubPR is the pull request object of the REST API.
+type githubPR struct {
+	Number   int    `json:"number"`
+	HTMLURL  string `json:"html_url"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	State    string `json:"state"`
+	Draft    bool   `json:"draft"`
+	MergedAt string `json:"merged_at"`
+	NodeID   string `json:"node_id"`
+	Head     struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+func (p githubPR) toPR() *PR {
+	state := p.State
+	if p.MergedAt != "" {
+		state = "merged"
+	}
+	return &PR{
+		Number: p.Number,
+		URL:    p.HTMLURL,
+		Title:  p.Title,
+		Body:   p.Body,
+		Head:   p.Head.Ref,
+		Base:   p.Base.Ref,
+		State:  state,
+		Draft:  p.Draft,
+	}
+}
+
+func (g *GitHub) do(method, path string, body, result any) error {
+	return doJSON(method, g.BaseURL+path, g.headers(), body, result)
+}
+
+func (g *GitHub) headers() map[string]string {
+	return map[string]string{
+		"Accept":               "application/vnd.github+json",
+		"Authorization":        "Bearer " + g.Token,
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+}
+
+// graphQL runs a GraphQL query. The endpoint sits next to the REST root:
+// /graphql on api.github.com, /api/graphql on Enterprise Server.
+func (g *GitHub) graphQL(query string, variables map[string]any) error {
+	endpoint := strings.TrimSuffix(g.BaseURL, "/v3") + "/graphql"
+	var resp struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	req := map[string]any{"query": query, "variables": variables}
+	if err := doJSON("POST", endpoint, g.headers(), req, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("%s", resp.Errors[0].Message)
+	}
+	return nil
+}
+
+func (g *GitHub) repoPath() string {
+	return "/repos/" + g.Remote.Owner() + "/" + g.Remote.Repo()
+}
+
+// headOwner returns the owner of the repository PR branches live in.
+func (g *GitHub) headOwner() string {
+	if g.Fork != "" {
+		return Remote{Path: g.Fork}.Owner()
+	}
+	return g.Remote.Owner()
+}
+
+func (g *GitHub) ExistingPR(branch string) (*PR, error) {
+	query := url.Values{
+		"head":  {g.headOwner() + ":" + branch},
+		"state": {"open"},
+	}
+	var prs []githubPR
+	if err := g.do("GET", g.repoPath()+"/pulls?"+query.Encode(), nil, &prs); err != nil {
+		return nil, err
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return prs[0].toPR(), nil
+}
+
+func (g *GitHub) CreatePR(pr NewPR) (*PR, error) {
+	base := pr.Base
+	if base == "" {
+		var repo struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := g.do("GET", g.repoPath(), nil, &repo); err != nil {
+			return nil, err
+		}
+		base = repo.DefaultBranch
+	}
+
+	head := pr.Head
+	if g.Fork != "" {
+		head = g.headOwner() + ":" + head
+	}
+	req := map[string]any{
+		"title": pr.Title,
+		"body":  pr.Body,
+		"head":  head,
+		"base":  base,
+		"draft": pr.Draft,
+	}
+	var created githubPR
+	if err := g.do("POST", g.repoPath()+"/pulls", req, &created); err != nil {
+		return nil, fmt.Errorf("create pull request: %w", err)
+	}
+	return created.toPR(), g.applyMetadata(created.Number, pr)
+}
+
+func (g *GitHub) UpdatePR(number int, body string) error {
+	path := fmt.Sprintf("%s/pulls/%d", g.repoPath(), number)
+	if err := g.do("PATCH", path, map[string]any{"body": body}, nil); err != nil {
+		return fmt.Errorf("update pull request: %w", err)
+	}
+	return nil
+}
+
+func (g *GitHub) RetargetPR(number int, base string) error {
+	path := fmt.Sprintf("%s/pulls/%d", g.repoPath(), number)
+	if err := g.do("PATCH", path, map[string]any{"base": base}, nil); err != nil {
+		return fmt.Errorf("retarget pull request: %w", err)
+	}
+	return nil
+}
+
+func (g *GitHub) GetPR(number int) (*PR, error) {
+	pr, err := g.pull(number)
+	if err != nil {
+		return nil, err
+	}
+	return pr.toPR(), nil
+}
+
+func (g *GitHub) pull(number int) (githubPR, error) {
+	var pr githubPR
+	err := g.do("GET", fmt.Sprintf("%s/pulls/%d", g.repoPath(), number), nil, &pr)
+	return pr, err
+}
+
+// Checks returns the check runs and commit statuses of the PR's head
+// commit. GitHub Actions jobs are check runs; older integrations report
+// commit statuses.
+func (g *GitHub) Checks(number int) ([]Check, error) {
+	pr, err := g.pull(number)
+	if err != nil {
+		return nil, err
+	}
+	commit := g.repoPath() + "/commits/" + pr.Head.SHA
+
+	var runs struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			HTMLURL    string `json:"html_url"`
+		} `json:"check_runs"`
+	}
+	if err := g.do("GET", commit+"/check-runs?per_page=100", nil, &runs); err != nil {
+		return nil, err
+	}
+	var checks []Check
+	for _, r := range runs.CheckRuns {
+		state := CheckPending
+		if r.Status == "completed" {
+			switch r.Conclusion {
+			case "success", "neutral":
+				state = CheckSuccess
+			case "skipped", "stale":
+				state = CheckSkipped
+			default: // failure, cancelled, timed_out, action_required
+				state = CheckFailure
+			}
+		}
+		checks = append(checks, Check{Name: r.Name, State: state, URL: r.HTMLURL})
+	}
+
+	var status struct {
+		Statuses []struct {
+			Context   string `json:"context"`
+			State     string `json:"state"`
+			TargetURL string `json:"target_url"`
+		} `json:"statuses"`
+	}
+	if err := g.do("GET", commit+"/status", nil, &status); err != nil {
+		return nil, err
+	}
+	for _, s := range status.Statuses {
+		state := s.State
+		if state == "error" {
+			state = CheckFailure
+		}
+		checks = append(checks, Check{Name: s.Context, State: state, URL: s.TargetURL})
+	}
+	return checks, nil
+}
+
+func (g *GitHub) MergePR(number int, strategy string) error {
+	path := fmt.Sprintf("%s/pulls/%d/merge", g.repoPath(), number)
+	if err := g.do("PUT", path, map[string]any{"merge_method": strategy}, nil); err != nil {
+		return fmt.Errorf("merge pull request: %w", err)
+	}
+	return nil
+}
+
+// EnableAutoMerge turns on auto-merge, which only the GraphQL API offers.
+// The repository must allow auto-merge and the PR must have pending
+// requirements; GitHub rejects it for PRs that could merge right away.
+func (g *GitHub) EnableAutoMerge(number int, strategy string) error {
+	pr, err := g.pull(number)
+	if err != nil {
+		return err
+	}
+	const mutation = `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) { clientMutationId }
+}`
+	vars := map[string]any{"id": pr.NodeID, "method": strings.ToUpper(strategy)}
+	if err := g.graphQL(mutation, vars); err != nil {
+		return fmt.Errorf("enable auto-merge: %w", err)
+	}
+	return nil
+}
+
+// SubmitReview creates a pending review: without an "event" it stays a
+// draft until submitted on GitHub.
+func (g *GitHub) SubmitReview(number int, body string, comments []ReviewComment) (string, error) {
+	cs := []map[string]any{}
+	for _, c := range comments {
+		cs = append(cs, map[string]any{"path": c.Path, "line": c.Line, "side": "RIGHT", "body": c.Body})
+	}
+	req := map[string]any{"body": body, "comments": cs}
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := g.do("POST", fmt.Sprintf("%s/pulls/%d/reviews", g.repoPath(), number), req, &created); err != nil {
+		return "", fmt.Errorf("create review: %w", err)
+	}
+	return created.HTMLURL, nil
+}
+
+// CreateRelease publishes a release for an existing tag.
+func (g *GitHub) CreateRelease(r Release) (string, error) {
+	req := map[string]any{"tag_name": r.Tag, "name": r.Name, "body": r.Body, "prerelease": r.Prerelease}
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := g.do("POST", g.repoPath()+"/releases", req, &created); err != nil {
+		return "", fmt.Errorf("create release: %w", err)
+	}
+	return created.HTMLURL, nil
+}
+
+// applyMetadata adds labels, assignees and review requests to a new PR.
+// PRs are issues in the REST API, so labels and assignees go through /issues.
+func (g *GitHub) applyMetadata(number int, pr NewPR) error {
+	issue := fmt.Sprintf("%s/issues/%d", g.repoPath(), number)
+	var labelErr, assigneeErr, reviewerErr error
+	if len(pr.Labels) > 0 {
+		if err := g.do("POST", issue+"/labels", map[string]any{"labels": pr.Labels}, nil); err != nil {
+			labelErr = fmt.Errorf("labels: %w", err)
+		}
+	}
+	if len(pr.Assignees) > 0 {
+		if err := g.do("POST", issue+"/assignees", map[string]any{"assignees": pr.Assignees}, nil); err != nil {
+			assigneeErr = fmt.Errorf("assignees: %w", err)
+		}
+	}
+	if len(pr.Reviewers) > 0 {
+		users, teams := splitTeams(pr.Reviewers)
+		req := map[string]any{"reviewers": users, "team_reviewers": teams}
+		if err := g.do("POST", fmt.Sprintf("%s/pulls/%d/requested_reviewers", g.repoPath(), number), req, nil); err != nil {
+			reviewerErr = fmt.Errorf("reviewers: %w", err)
+		}
+	}
+	return metadataErr(labelErr, assigneeErr, reviewerErr)
+}
+
+// UserForEmail finds the GitHub login of a commit email: noreply addresses
+// carry it directly, others are looked up among public profile emails.
+func (g *GitHub) UserForEmail(email string) (string, error) {
+	if local, ok := strings.CutSuffix(strings.ToLower(email), "@users.noreply.github.com"); ok {
+		if _, login, found := strings.Cut(local, "+"); found {
+			return login, nil
+		}
+		return local, nil
+	}
+	var result struct {
+		Items []struct {
+			Login string `json:"login"`
+		} `json:"items"`
+	}
+	query := url.Values{"q": {email + " in:email"}}
+	if err := g.do("GET", "/search/users?"+query.Encode(), nil, &result); err != nil {
+		return "", err
+	}
+	if len(result.Items) == 0 {
+		return "", nil
+	}
+	return result.Items[0].Login, nil
+}
+
+// splitTeams separates "org/team" (or "@org/team") reviewers into team
+// slugs from plain usernames.
+func splitTeams(reviewers []string) (users, teams []string) {
+	users, teams = []string{}, []string{}
+	for _, r := range reviewers {
+		r = strings.TrimPrefix(r, "@")
+		if _, team, ok := strings.Cut(r, "/"); ok {
+			teams = append(teams, team)
+		} else {
+			users = append(users, r)
+		}
+	}
+	return users, teams
+}
@@ -0,0 +1,302 @@
+package forge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitHub(t *testing.T) {
+	var created, updated map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ghp-test" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/acme/app/pulls":
+			if r.URL.Query().Get("head") == "acme:feature" {
+				w.Write([]byte(`[{"number": 7, "html_url": "https://github.com/acme/app/pull/7", "state": "open", "head": {"ref": "feature"}, "base": {"ref": "main"}}]`))
+				return
+			}
+			w.Write([]byte(`[]`))
+		case r.Method == "PATCH" && r.URL.Path == "/repos/acme/app/pulls/7":
+			json.NewDecoder(r.Body).Decode(&updated)
+			w.Write([]byte(`{"number": 7}`))
+		case r.Method == "GET" && r.URL.Path == "/repos/acme/app":
+			w.Write([]byte(`{"default_branch": "trunk"}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/acme/app/pulls":
+			json.NewDecoder(r.Body).Decode(&created)
+			if created["head"] == "taken" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(`{"message": "Validation Failed", "errors": [{"message": "A pull request already exists for acme:taken."}]}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 8, "html_url": "https://github.com/acme/app/pull/8", "title": "Add x", "state": "open", "head": {"ref": "other"}, "base": {"ref": "trunk"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g := &GitHub{Remote: Remote{Host: "github.com", Path: "acme/app"}, Token: "ghp-test", BaseURL: server.URL}
+
+	pr, err := g.ExistingPR("feature")
+	if err != nil || pr == nil || pr.Number != 7 || pr.Base != "main" {
+		t.Fatalf("ExistingPR(feature) = %+v, %v", pr, err)
+	}
+	if pr, err := g.ExistingPR("nothing"); err != nil || pr != nil {
+		t.Errorf("ExistingPR(nothing) = %+v, %v", pr, err)
+	}
+
+	pr, err = g.CreatePR(NewPR{Title: "Add x", Body: "body", Head: "other"})
+	if err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+	if pr.URL != "https://github.com/acme/app/pull/8" || created["base"] != "trunk" {
+		t.Errorf("CreatePR = %+v, request %v", pr, created)
+	}
+
+	if err := g.UpdatePR(7, "new body"); err != nil || updated["body"] != "new body" {
+		t.Errorf("UpdatePR = %v, request %v", err, updated)
+	}
+
+	_, err = g.CreatePR(NewPR{Title: "Dup", Head: "taken", Base: "main"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != 422 || apiErr.Message != "Validation Failed: A pull request already exists for acme:taken." {
+		t.Errorf("CreatePR duplicate error = %v", err)
+	}
+}
+
+func TestGitHubAPIURL(t *testing.T) {
+	if got := githubAPIURL("github.com"); got != "https://api.github.com" {
+		t.Errorf("github.com = %q", got)
+	}
+	if got := githubAPIURL("github.corp.com"); got != "https://github.corp.com/api/v3" {
+		t.Errorf("enterprise = %q", got)
+	}
+}
+
+func TestGitHubMetadata(t *testing.T) {
+	requests := map[string]map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		requests[r.Method+" "+r.URL.Path] = body
+		switch r.URL.Path {
+		case "/repos/acme/app/pulls":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 9, "html_url": "https://github.com/acme/app/pull/9", "state": "open", "draft": true}`))
+		case "/repos/acme/app/issues/9/labels", "/repos/acme/app/pulls/9/requested_reviewers":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		case "/repos/acme/app/issues/9/assignees":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Must have push access"}`))
+		case "/search/users":
+			w.Write([]byte(`{"items": [{"login": "octo"}]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g := &GitHub{Remote: Remote{Host: "github.com", Path: "acme/app"}, Token: "t", BaseURL: server.URL}
+	pr, err := g.CreatePR(NewPR{
+		Title: "Add x", Head: "feature", Base: "main", Draft: true,
+		Labels: []string{"bug"}, Reviewers: []string{"alice", "@acme/core"}, Assignees: []string{"bob"},
+	})
+	var metaErr *MetadataError
+	if pr == nil || !pr.Draft || !errors.As(err, &metaErr) || len(metaErr.Errs) != 1 {
+		t.Fatalf("CreatePR = %+v, %v", pr, err)
+	}
+	if requests["POST /repos/acme/app/pulls"]["draft"] != true {
+		t.Errorf("draft not requested: %v", requests["POST /repos/acme/app/pulls"])
+	}
+	reviewers := requests["POST /repos/acme/app/pulls/9/requested_reviewers"]
+	if fmt.Sprint(reviewers["reviewers"]) != "[alice]" || fmt.Sprint(reviewers["team_reviewers"]) != "[core]" {
+		t.Errorf("requested_reviewers = %v", reviewers)
+	}
+	if fmt.Sprint(requests["POST /repos/acme/app/issues/9/labels"]["labels"]) != "[bug]" {
+		t.Errorf("labels = %v", requests["POST /repos/acme/app/issues/9/labels"])
+	}
+
+	for email, want := range map[string]string{
+		"123+Octo@users.noreply.github.com": "octo",
+		"octo@users.noreply.github.com":     "octo",
+		"octo@example.com":                  "octo",
+	} {
+		if got, err := g.UserForEmail(email); err != nil || got != want {
+			t.Errorf("UserForEmail(%q) = %q, %v", email, got, err)
+		}
+	}
+}
+
+func TestGitHubFork(t *testing.T) {
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/acme/app/pulls":
+			if got := r.URL.Query().Get("head"); got != "me:feature" {
+				t.Errorf("head = %q, want me:feature", got)
+			}
+			w.Write([]byte(`[]`))
+		case r.Method == "POST" && r.URL.Path == "/repos/acme/app/pulls":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 1}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g := &GitHub{Remote: Remote{Host: "github.com", Path: "acme/app"}, BaseURL: server.URL, Fork: "me/app"}
+	if pr, err := g.ExistingPR("feature"); err != nil || pr != nil {
+		t.Errorf("ExistingPR = %+v, %v", pr, err)
+	}
+	if _, err := g.CreatePR(NewPR{Title: "x", Head: "feature", Base: "main"}); err != nil || created["head"] != "me:feature" {
+		t.Errorf("CreatePR = %v, request %v", err, created)
+	}
+}
+
+func TestGitHubChecksAndMerge(t *testing.T) {
+	requests := map[string]map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		requests[r.Method+" "+r.URL.Path] = body
+		switch r.URL.Path {
+		case "/repos/acme/app/pulls/9":
+			w.Write([]byte(`{"number": 9, "node_id": "PR_kw9", "head": {"ref": "feature", "sha": "abc123"}}`))
+		case "/repos/acme/app/commits/abc123/check-runs":
+			w.Write([]byte(`{"check_runs": [
+				{"name": "build", "status": "completed", "conclusion": "success", "html_url": "https://github.com/acme/app/actions/runs/1/job/1"},
+				{"name": "lint", "status": "completed", "conclusion": "failure", "html_url": "https://github.com/acme/app/actions/runs/1/job/2"},
+				{"name": "e2e", "status": "completed", "conclusion": "skipped"},
+				{"name": "test", "status": "in_progress"}
+			]}`))
+		case "/repos/acme/app/commits/abc123/status":
+			w.Write([]byte(`{"statuses": [{"context": "ci/legacy", "state": "error", "target_url": "https://ci.example.com/7"}]}`))
+		case "/repos/acme/app/pulls/9/merge":
+			w.Write([]byte(`{"merged": true}`))
+		case "/graphql":
+			w.Write([]byte(`{"errors": [{"message": "Pull request is in clean status"}]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g := &GitHub{Remote: Remote{Host: "github.com", Path: "acme/app"}, Token: "t", BaseURL: server.URL}
+	checks, err := g.Checks(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Check{
+		{Name: "build", State: CheckSuccess, URL: "https://github.com/acme/app/actions/runs/1/job/1"},
+		{Name: "lint", State: CheckFailure, URL: "https://github.com/acme/app/actions/runs/1/job/2"},
+		{Name: "e2e", State: CheckSkipped},
+		{Name: "test", State: CheckPending},
+		{Name: "ci/legacy", State: CheckFailure, URL: "https://ci.example.com/7"},
+	}
+	if fmt.Sprint(checks) != fmt.Sprint(want) {
+		t.Errorf("Checks = %v, want %v", checks, want)
+	}
+
+	if err := g.MergePR(9, MergeSquash); err != nil {
+		t.Fatal(err)
+	}
+	if requests["PUT /repos/acme/app/pulls/9/merge"]["merge_method"] != "squash" {
+		t.Errorf("merge request = %v", requests["PUT /repos/acme/app/pulls/9/merge"])
+	}
+
+	err = g.EnableAutoMerge(9, MergeRebase)
+	if err == nil || !strings.Contains(err.Error(), "clean status") {
+		t.Errorf("EnableAutoMerge error = %v", err)
+	}
+	vars, _ := requests["POST /graphql"]["variables"].(map[string]any)
+	if vars["id"] != "PR_kw9" || vars["method"] != "REBASE" {
+		t.Errorf("graphql variables = %v", vars)
+	}
+}
+
+func TestGitHubSubmitReview(t *testing.T) {
+	var review map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/repos/acme/app/pulls/9/reviews" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&review)
+		w.Write([]byte(`{"id": 1, "state": "PENDING", "html_url": "https://github.com/acme/app/pull/9#pullrequestreview-1"}`))
+	}))
+	defer server.Close()
+
+	g := &GitHub{Remote: Remote{Host: "github.com", Path: "acme/app"}, Token: "t", BaseURL: server.URL}
+	url, err := g.SubmitReview(9, "summary", []ReviewComment{{Path: "a.go", Line: 12, Body: "nil check"}})
+	if err != nil || url != "https://github.com/acme/app/pull/9#pullrequestreview-1" {
+		t.Fatalf("SubmitReview = %q, %v", url, err)
+	}
+	// A review without an event stays pending.
+	if _, ok := review["event"]; ok || review["body"] != "summary" {
+		t.Errorf("review = %v", review)
+	}
+	if fmt.Sprint(review["comments"]) != "[map[body:nil check line:12 path:a.go side:RIGHT]]" {
+		t.Errorf("comments = %v", review["comments"])
+	}
+}
+
+func TestGitHubGetPR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/app/pulls/12" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+			return
+		}
+		w.Write([]byte(`{"number": 12, "html_url": "https://github.com/acme/app/pull/12", "title": "feat: add changelog", "state": "closed", "merged_at": "2024-05-01T10:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	g := &GitHub{Remote: Remote{Host: "github.com", Path: "acme/app"}, Token: "t", BaseURL: server.URL}
+	pr, err := g.GetPR(12)
+	if err != nil || pr.Title != "feat: add changelog" || pr.Number != 12 {
+		t.Fatalf("GetPR = %+v, %v", pr, err)
+	}
+	if _, err := g.GetPR(13); err == nil {
+		t.Error("GetPR(13) succeeded, want error")
+	}
+}
+
+func TestGitHubCreateRelease(t *testing.T) {
+	var release map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/repos/acme/app/releases" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&release)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1, "html_url": "https://github.com/acme/app/releases/tag/v1.3.0-rc.1"}`))
+	}))
+	defer server.Close()
+
+	g := &GitHub{Remote: Remote{Host: "github.com", Path: "acme/app"}, Token: "t", BaseURL: server.URL}
+	url, err := g.CreateRelease(Release{Tag: "v1.3.0-rc.1", Name: "v1.3.0-rc.1", Body: "### Added\n\n- x", Prerelease: true})
+	if err != nil || url != "https://github.com/acme/app/releases/tag/v1.3.0-rc.1" {
+		t.Fatalf("CreateRelease = %q, %v", url, err)
+	}
+	if release["tag_name"] != "v1.3.0-rc.1" || release["prerelease"] != true || release["body"] != "### Added\n\n- x" {
+		t.Errorf("release = %v", release)
+	}
+}
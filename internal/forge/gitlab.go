@@ -0,0 +1,351 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// GitLab implements Forge with the GitLab REST API (v4). It works with
+// gitlab.com and self-hosted instances; projects may live in nested groups.
+type GitLab struct {
+	Remote  Remote
+	Token   string
+	BaseURL string // API root, e.g. "https://gitlab.com/api/v4"
+	Fork    string // project path branches are pushed to, when not Remote
+}
+
+// NewGitLab returns a GitLab client for the remote's project. An empty
+// apiURL uses /api/v4 on the remote's host.
+func NewGitLab(remote Remote, token, apiURL string) *GitLab {
+	if apiURL == "" {
+		apiURL = "https://" + remote.Host + "/api/v4"
+	}
+	return &GitLab{Remote: remote, Token: token, BaseURL: apiURL}
+}
+
+func (*GitLab) Name() string { return "GitLab" }
+
+// gitlabMR is the merge request object of the REST API.
+type gitlabMR struct {
+	IID             int    `json:"iid"`
+	SourceProjectID int    `json:"source_project_id"`
+	WebURL          string `json:"web_url"`
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	State           string `json:"state"`
+	Draft           bool   `json:"draft"`
+	SourceBranch    string `json:"source_branch"`
+	TargetBranch    string `json:"target_branch"`
+}
+
+func (m gitlabMR) toPR() *PR {
+	state := m.State
+	if state == "opened" {
+		state = "open"
+	}
+	return &PR{
+		Number: m.IID,
+		URL:    m.WebURL,
+		Title:  m.Title,
+		Body:   m.Description,
+		Head:   m.SourceBranch,
+		Base:   m.TargetBranch,
+		State:  state,
+		Draft:  m.Draft,
+	}
+}
+
+func (g *GitLab) do(method, path string, body, result any) error {
+	headers := map[string]string{"PRIVATE-TOKEN": g.Token}
+	return doJSON(method, g.BaseURL+path, headers, body, result)
+}
+
+// projectPath returns the API path of the project; the full path is used
+// as its ID, URL-encoded so subgroups stay one path segment.
+func (g *GitLab) projectPath() string {
+	return projectPath(g.Remote.Path)
+}
+
+func projectPath(path string) string {
+	return "/projects/" + url.QueryEscape(path)
+}
+
+// gitlabProject is the subset of the project object yeet uses.
+type gitlabProject struct {
+	ID            int    `json:"id"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+func (g *GitLab) project(path string) (gitlabProject, error) {
+	var p gitlabProject
+	err := g.do("GET", projectPath(path), nil, &p)
+	return p, err
+}
+
+func (g *GitLab) ExistingPR(branch string) (*PR, error) {
+	query := url.Values{
+		"source_branch": {branch},
+		"state":         {"opened"},
+	}
+	var mrs []gitlabMR
+	if err := g.do("GET", g.projectPath()+"/merge_requests?"+query.Encode(), nil, &mrs); err != nil {
+		return nil, err
+	}
+	if g.Fork != "" {
+		fork, err := g.project(g.Fork)
+		if err != nil {
+			return nil, err
+		}
+		mrs = slices.DeleteFunc(mrs, func(mr gitlabMR) bool { return mr.SourceProjectID != fork.ID })
+	}
+	if len(mrs) == 0 {
+		return nil, nil
+	}
+	return mrs[0].toPR(), nil
+}
+
+func (g *GitLab) CreatePR(pr NewPR) (*PR, error) {
+	// Merge requests from a fork are created in the fork, pointing at the
+	// target project by ID.
+	source := g.projectPath()
+	var target gitlabProject
+	if pr.Base == "" || g.Fork != "" {
+		var err error
+		if target, err = g.project(g.Remote.Path); err != nil {
+			return nil, err
+		}
+	}
+	base := pr.Base
+	if base == "" {
+		base = target.DefaultBranch
+	}
+
+	title := pr.Title
+	if pr.Draft && !strings.HasPrefix(strings.ToLower(title), "draft:") {
+		title = "Draft: " + title
+	}
+	req := map[string]any{
+		"title":         title,
+		"description":   pr.Body,
+		"source_branch": pr.Head,
+		"target_branch": base,
+	}
+	if g.Fork != "" {
+		source = projectPath(g.Fork)
+		req["target_project_id"] = target.ID
+	}
+	if len(pr.Labels) > 0 {
+		req["labels"] = strings.Join(pr.Labels, ",")
+	}
+	// Users are referenced by ID; names that don't resolve are reported
+	// after the MR is created.
+	assignees, assigneeErr := g.userIDs(pr.Assignees)
+	if len(assignees) > 0 {
+		req["assignee_ids"] = assignees
+	}
+	reviewers, reviewerErr := g.userIDs(pr.Reviewers)
+	if len(reviewers) > 0 {
+		req["reviewer_ids"] = reviewers
+	}
+
+	var created gitlabMR
+	if err := g.do("POST", source+"/merge_requests", req, &created); err != nil {
+		return nil, fmt.Errorf("create merge request: %w", err)
+	}
+	if assigneeErr != nil {
+		assigneeErr = fmt.Errorf("assignees: %w", assigneeErr)
+	}
+	if reviewerErr != nil {
+		reviewerErr = fmt.Errorf("reviewers: %w", reviewerErr)
+	}
+	return created.toPR(), metadataErr(assigneeErr, reviewerErr)
+}
+
+func (g *GitLab) UpdatePR(number int, body string) error {
+	path := fmt.Sprintf("%s/merge_requests/%d", g.projectPath(), number)
+	if err := g.do("PUT", path, map[string]any{"description": body}, nil); err != nil {
+		return fmt.Errorf("update merge request: %w", err)
+	}
+	return nil
+}
+
+func (g *GitLab) RetargetPR(number int, base string) error {
+	path := fmt.Sprintf("%s/merge_requests/%d", g.projectPath(), number)
+	if err := g.do("PUT", path, map[string]any{"target_branch": base}, nil); err != nil {
+		return fmt.Errorf("retarget merge request: %w", err)
+	}
+	return nil
+}
+
+func (g *GitLab) GetPR(number int) (*PR, error) {
+	var mr gitlabMR
+	if err := g.do("GET", fmt.Sprintf("%s/merge_requests/%d", g.projectPath(), number), nil, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toPR(), nil
+}
+
+// Checks returns the jobs of the MR's latest pipeline. Failed jobs that
+// are allowed to fail count as skipped.
+func (g *GitLab) Checks(number int) ([]Check, error) {
+	var pipelines []struct {
+		ID        int `json:"id"`
+		ProjectID int `json:"project_id"`
+	}
+	if err := g.do("GET", fmt.Sprintf("%s/merge_requests/%d/pipelines", g.projectPath(), number), nil, &pipelines); err != nil {
+		return nil, err
+	}
+	if len(pipelines) == 0 {
+		return nil, nil
+	}
+	// Pipelines of fork MRs may run in the fork, so use the pipeline's project.
+	p := pipelines[0]
+	var jobs []struct {
+		Name         string `json:"name"`
+		Status       string `json:"status"`
+		WebURL       string `json:"web_url"`
+		AllowFailure bool   `json:"allow_failure"`
+	}
+	if err := g.do("GET", fmt.Sprintf("/projects/%d/pipelines/%d/jobs?per_page=100", p.ProjectID, p.ID), nil, &jobs); err != nil {
+		return nil, err
+	}
+	var checks []Check
+	for _, j := range jobs {
+		state := CheckPending
+		switch j.Status {
+		case "success":
+			state = CheckSuccess
+		case "failed", "canceled":
+			state = CheckFailure
+			if j.AllowFailure {
+				state = CheckSkipped
+			}
+		case "skipped", "manual":
+			state = CheckSkipped
+		}
+		checks = append(checks, Check{Name: j.Name, State: state, URL: j.WebURL})
+	}
+	return checks, nil
+}
+
+// MergePR merges right away. GitLab merges with the project's merge
+// method, so only squashing can be chosen per MR.
+func (g *GitLab) MergePR(number int, strategy string) error {
+	return g.merge(number, strategy, false)
+}
+
+// EnableAutoMerge sets the MR to merge when its pipeline succeeds.
+func (g *GitLab) EnableAutoMerge(number int, strategy string) error {
+	return g.merge(number, strategy, true)
+}
+
+func (g *GitLab) merge(number int, strategy string, auto bool) error {
+	if strategy == MergeRebase {
+		return unsupportedStrategy("GitLab", strategy)
+	}
+	req := map[string]any{"squash": strategy == MergeSquash}
+	if auto {
+		req["merge_when_pipeline_succeeds"] = true
+	}
+	path := fmt.Sprintf("%s/merge_requests/%d/merge", g.projectPath(), number)
+	if err := g.do("PUT", path, req, nil); err != nil {
+		return fmt.Errorf("merge merge request: %w", err)
+	}
+	return nil
+}
+
+// SubmitReview adds the comments as draft notes, which stay private until
+// "Submit review" is clicked on the MR.
+func (g *GitLab) SubmitReview(number int, body string, comments []ReviewComment) (string, error) {
+	mrPath := fmt.Sprintf("%s/merge_requests/%d", g.projectPath(), number)
+	var mr struct {
+		WebURL   string `json:"web_url"`
+		DiffRefs struct {
+			BaseSHA  string `json:"base_sha"`
+			HeadSHA  string `json:"head_sha"`
+			StartSHA string `json:"start_sha"`
+		} `json:"diff_refs"`
+	}
+	if err := g.do("GET", mrPath, nil, &mr); err != nil {
+		return "", err
+	}
+	for i, c := range comments {
+		position := map[string]any{
+			"position_type": "text",
+			"base_sha":      mr.DiffRefs.BaseSHA,
+			"head_sha":      mr.DiffRefs.HeadSHA,
+			"start_sha":     mr.DiffRefs.StartSHA,
+			"old_path":      c.Path,
+			"new_path":      c.Path,
+			"new_line":      c.Line,
+		}
+		// Unchanged lines are addressed by both line numbers.
+		if c.OldLine > 0 {
+			position["old_line"] = c.OldLine
+		}
+		req := map[string]any{"note": c.Body, "position": position}
+		if err := g.do("POST", mrPath+"/draft_notes", req, nil); err != nil {
+			return "", partialReviewErr(fmt.Errorf("draft note on %s:%d: %w", c.Path, c.Line, err), i, "draft note(s)")
+		}
+	}
+	if body != "" {
+		if err := g.do("POST", mrPath+"/draft_notes", map[string]any{"note": body}, nil); err != nil {
+			return "", partialReviewErr(fmt.Errorf("draft note: %w", err), len(comments), "draft note(s)")
+		}
+	}
+	return mr.WebURL, nil
+}
+
+// userIDs resolves usernames to user IDs, returning the IDs found and an
+// error naming the rest.
+func (g *GitLab) userIDs(usernames []string) ([]int, error) {
+	var ids []int
+	var missing []string
+	for _, name := range usernames {
+		var users []struct {
+			ID int `json:"id"`
+		}
+		query := url.Values{"username": {strings.TrimPrefix(name, "@")}}
+		if err := g.do("GET", "/users?"+query.Encode(), nil, &users); err != nil || len(users) == 0 {
+			missing = append(missing, name)
+			continue
+		}
+		ids = append(ids, users[0].ID)
+	}
+	if len(missing) > 0 {
+		return ids, fmt.Errorf("unknown user(s) %s", strings.Join(missing, ", "))
+	}
+	return ids, nil
+}
+
+// CreateRelease publishes a release. GitLab has no pre-release flag;
+// releases of pre-release tags are marked as such by their tag name.
+func (g *GitLab) CreateRelease(r Release) (string, error) {
+	req := map[string]any{"tag_name": r.Tag, "name": r.Name, "description": r.Body}
+	var created struct {
+		Links struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+	}
+	if err := g.do("POST", g.projectPath()+"/releases", req, &created); err != nil {
+		return "", fmt.Errorf("create release: %w", err)
+	}
+	return created.Links.Self, nil
+}
+
+// UserForEmail finds the GitLab username whose public email matches.
+func (g *GitLab) UserForEmail(email string) (string, error) {
+	var users []struct {
+		Username string `json:"username"`
+	}
+	query := url.Values{"search": {email}}
+	if err := g.do("GET", "/users?"+query.Encode(), nil, &users); err != nil {
+		return "", err
+	}
+	if len(users) == 0 {
+		return "", nil
+	}
+	return users[0].Username, nil
+}
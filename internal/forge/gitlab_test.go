@@ -0,0 +1,206 @@
+package forge
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGitLab(t *testing.T) {
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "glpat-test" {
+			t.Errorf("PRIVATE-TOKEN = %q", r.Header.Get("PRIVATE-TOKEN"))
+		}
+		// Subgroups must stay one encoded path segment.
+		const project = "/api/v4/projects/group%2Fsub%2Fapp"
+		switch {
+		case r.Method == "GET" && r.URL.EscapedPath() == project+"/merge_requests":
+			if r.URL.Query().Get("source_branch") == "feature" && r.URL.Query().Get("state") == "opened" {
+				w.Write([]byte(`[{"iid": 3, "web_url": "https://git.company.io/group/sub/app/-/merge_requests/3", "state": "opened", "source_branch": "feature", "target_branch": "main", "description": "old"}]`))
+				return
+			}
+			w.Write([]byte(`[]`))
+		case r.Method == "GET" && r.URL.EscapedPath() == project:
+			w.Write([]byte(`{"default_branch": "develop"}`))
+		case r.Method == "POST" && r.URL.EscapedPath() == project+"/merge_requests":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"iid": 4, "web_url": "https://git.company.io/group/sub/app/-/merge_requests/4", "state": "opened"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g := NewGitLab(Remote{Host: "git.company.io", Path: "group/sub/app"}, "glpat-test", server.URL+"/api/v4")
+
+	pr, err := g.ExistingPR("feature")
+	if err != nil || pr == nil || pr.Number != 3 || pr.State != "open" || pr.Body != "old" {
+		t.Fatalf("ExistingPR(feature) = %+v, %v", pr, err)
+	}
+	if pr, err := g.ExistingPR("other"); err != nil || pr != nil {
+		t.Errorf("ExistingPR(other) = %+v, %v", pr, err)
+	}
+
+	pr, err = g.CreatePR(NewPR{Title: "Add x", Body: "body", Head: "other"})
+	if err != nil || pr.Number != 4 {
+		t.Fatalf("CreatePR = %+v, %v", pr, err)
+	}
+	if created["target_branch"] != "develop" || created["source_branch"] != "other" || created["description"] != "body" {
+		t.Errorf("create request = %v", created)
+	}
+}
+
+func TestGitLabFork(t *testing.T) {
+	var created map[string]any
+	var createdIn string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path := r.URL.EscapedPath(); {
+		case r.Method == "GET" && path == "/projects/acme%2Fapp":
+			w.Write([]byte(`{"id": 1, "default_branch": "main"}`))
+		case r.Method == "GET" && path == "/projects/me%2Fapp":
+			w.Write([]byte(`{"id": 2}`))
+		case r.Method == "GET" && path == "/projects/acme%2Fapp/merge_requests":
+			// Another contributor's fork uses the same branch name.
+			w.Write([]byte(`[{"iid": 5, "source_project_id": 3, "state": "opened"}, {"iid": 6, "source_project_id": 2, "state": "opened"}]`))
+		case r.Method == "POST":
+			createdIn = path
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"iid": 7, "state": "opened"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g := &GitLab{Remote: Remote{Host: "gitlab.com", Path: "acme/app"}, BaseURL: server.URL, Fork: "me/app"}
+
+	if pr, err := g.ExistingPR("feature"); err != nil || pr == nil || pr.Number != 6 {
+		t.Errorf("ExistingPR = %+v, %v; want !6", pr, err)
+	}
+	if _, err := g.CreatePR(NewPR{Title: "x", Head: "feature"}); err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+	if createdIn != "/projects/me%2Fapp/merge_requests" || created["target_project_id"] != float64(1) || created["target_branch"] != "main" {
+		t.Errorf("CreatePR posted to %s: %v", createdIn, created)
+	}
+}
+
+func TestGitLabChecks(t *testing.T) {
+	var merge map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /api/v4/projects/group%2Fapp/merge_requests/3/pipelines":
+			// Fork MR pipelines run in the fork's project.
+			w.Write([]byte(`[{"id": 70, "project_id": 12}, {"id": 60, "project_id": 12}]`))
+		case "GET /api/v4/projects/12/pipelines/70/jobs":
+			w.Write([]byte(`[
+				{"name": "build", "status": "success", "web_url": "https://gitlab.com/me/app/-/jobs/1"},
+				{"name": "lint", "status": "failed", "web_url": "https://gitlab.com/me/app/-/jobs/2", "allow_failure": true},
+				{"name": "test", "status": "failed", "web_url": "https://gitlab.com/me/app/-/jobs/3"},
+				{"name": "deploy", "status": "manual"},
+				{"name": "e2e", "status": "running"}
+			]`))
+		case "PUT /api/v4/projects/group%2Fapp/merge_requests/3/merge":
+			json.NewDecoder(r.Body).Decode(&merge)
+			w.Write([]byte(`{"iid": 3, "state": "opened"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g := NewGitLab(Remote{Host: "gitlab.com", Path: "group/app"}, "t", server.URL+"/api/v4")
+	checks, err := g.Checks(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var states []string
+	for _, c := range checks {
+		states = append(states, c.Name+"="+c.State)
+	}
+	if got := strings.Join(states, " "); got != "build=success lint=skipped test=failure deploy=skipped e2e=pending" {
+		t.Errorf("Checks = %s", got)
+	}
+
+	if err := g.EnableAutoMerge(3, MergeSquash); err != nil {
+		t.Fatal(err)
+	}
+	if merge["merge_when_pipeline_succeeds"] != true || merge["squash"] != true {
+		t.Errorf("merge request = %v", merge)
+	}
+	if err := g.MergePR(3, MergeRebase); err == nil {
+		t.Error("MergePR(rebase) succeeded, want unsupported strategy")
+	}
+}
+
+func TestGitLabSubmitReview(t *testing.T) {
+	var notes []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /api/v4/projects/group%2Fapp/merge_requests/3":
+			w.Write([]byte(`{"iid": 3, "web_url": "https://gitlab.com/group/app/-/merge_requests/3",
+				"diff_refs": {"base_sha": "b", "head_sha": "h", "start_sha": "s"}}`))
+		case "POST /api/v4/projects/group%2Fapp/merge_requests/3/draft_notes":
+			var note map[string]any
+			json.NewDecoder(r.Body).Decode(&note)
+			notes = append(notes, note)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	g := NewGitLab(Remote{Host: "gitlab.com", Path: "group/app"}, "t", server.URL+"/api/v4")
+	url, err := g.SubmitReview(3, "summary", []ReviewComment{
+		{Path: "a.go", Line: 12, Body: "added line"},
+		{Path: "a.go", Line: 20, OldLine: 18, Body: "context line"},
+	})
+	if err != nil || url != "https://gitlab.com/group/app/-/merge_requests/3" {
+		t.Fatalf("SubmitReview = %q, %v", url, err)
+	}
+	if len(notes) != 3 || notes[2]["note"] != "summary" || notes[2]["position"] != nil {
+		t.Fatalf("draft notes = %v", notes)
+	}
+	added, context := notes[0]["position"].(map[string]any), notes[1]["position"].(map[string]any)
+	if added["new_line"] != float64(12) || added["old_line"] != nil || added["head_sha"] != "h" || added["new_path"] != "a.go" {
+		t.Errorf("added-line position = %v", added)
+	}
+	if context["new_line"] != float64(20) || context["old_line"] != float64(18) {
+		t.Errorf("context-line position = %v", context)
+	}
+}
+
+func TestGitLabSubmitReviewReportsDrafts(t *testing.T) {
+	posted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"iid": 3, "web_url": "https://gitlab.com/group/app/-/merge_requests/3"}`))
+			return
+		}
+		if posted++; posted > 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message": "line_code can't be blank"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	g := NewGitLab(Remote{Host: "gitlab.com", Path: "group/app"}, "t", server.URL+"/api/v4")
+	_, err := g.SubmitReview(3, "", []ReviewComment{{Path: "a.go", Line: 12, Body: "one"}, {Path: "a.go", Line: 30, Body: "two"}})
+	if err == nil || !strings.Contains(err.Error(), "a.go:30") || !strings.Contains(err.Error(), "1 draft note(s) already added") {
+		t.Errorf("SubmitReview = %v, want the failed comment and the drafts already added", err)
+	}
+}
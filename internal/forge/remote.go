@@ -0,0 +1,56 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Remote is a git remote URL split into the forge host and repository path.
+type Remote struct {
+	Host string // e.g. "github.com", or "git.company.io:8443" for HTTPS remotes on a custom port
+	Path string // e.g. "acme/app", or "group/subgroup/app" on GitLab
+}
+
+// ParseRemote parses SSH ("git@host:owner/repo.git", "ssh://git@host:22/owner/repo")
+// and HTTP(S) remote URLs. SSH ports are dropped since they say nothing about
+// the web host; HTTP(S) ports are kept.
+func ParseRemote(raw string) (Remote, error) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return Remote{}, fmt.Errorf("invalid remote URL %q: %w", raw, err)
+		}
+		host = u.Host
+		if u.Scheme != "http" && u.Scheme != "https" {
+			host = u.Hostname()
+		}
+		path = u.Path
+	} else if i := strings.Index(raw, ":"); i > 0 {
+		// scp-like syntax: [user@]host:path
+		host, path = raw[:i], raw[i+1:]
+		if j := strings.LastIndex(host, "@"); j >= 0 {
+			host = host[j+1:]
+		}
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return Remote{}, fmt.Errorf("cannot parse remote URL %q (expected host and owner/repo)", raw)
+	}
+	return Remote{Host: strings.ToLower(host), Path: path}, nil
+}
+
+// Owner returns everything before the repository name: the user or
+// organization, or the full group path on GitLab.
+func (r Remote) Owner() string {
+	return r.Path[:strings.LastIndex(r.Path, "/")]
+}
+
+// Repo returns the repository name.
+func (r Remote) Repo() string {
+	return r.Path[strings.LastIndex(r.Path, "/")+1:]
+}
@@ -0,0 +1,65 @@
+package forge
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Template locations per forge, most specific first. GitHub matches file
+// names case-insensitively, so both common spellings are listed.
+var (
+	githubTemplatePaths = []string{
+		".github/pull_request_template.md",
+		".github/PULL_REQUEST_TEMPLATE.md",
+		"pull_request_template.md",
+		"PULL_REQUEST_TEMPLATE.md",
+		"docs/pull_request_template.md",
+		"docs/PULL_REQUEST_TEMPLATE.md",
+		".github/PULL_REQUEST_TEMPLATE/*.md",
+		".github/pull_request_template/*.md",
+	}
+	gitlabTemplatePaths = []string{
+		".gitlab/merge_request_templates/Default.md",
+		".gitlab/merge_request_templates/default.md",
+		".gitlab/merge_request_templates/*.md",
+	}
+	giteaTemplatePaths = []string{
+		".gitea/pull_request_template.md",
+		".gitea/PULL_REQUEST_TEMPLATE.md",
+		".forgejo/pull_request_template.md",
+		".forgejo/PULL_REQUEST_TEMPLATE.md",
+		".github/pull_request_template.md",
+		".github/PULL_REQUEST_TEMPLATE.md",
+	}
+)
+
+func (*GitHub) TemplatePaths() []string   { return githubTemplatePaths }
+func (GitHubCLI) TemplatePaths() []string { return githubTemplatePaths }
+func (*GitLab) TemplatePaths() []string   { return gitlabTemplatePaths }
+func (GitLabCLI) TemplatePaths() []string { return gitlabTemplatePaths }
+func (*Gitea) TemplatePaths() []string    { return giteaTemplatePaths }
+
+// FindTemplate returns the forge's PR template in the repository at root,
+// or "" when the forge has none. For template directories the first file
+// by name wins.
+func FindTemplate(f Forge, root string) (path, content string) {
+	t, ok := f.(Templater)
+	if !ok {
+		return "", ""
+	}
+	for _, pattern := range t.TemplatePaths() {
+		matches, _ := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		sort.Strings(matches)
+		for _, m := range matches {
+			data, err := os.ReadFile(m)
+			if err != nil || strings.TrimSpace(string(data)) == "" {
+				continue
+			}
+			rel, _ := filepath.Rel(root, m)
+			return filepath.ToSlash(rel), string(data)
+		}
+	}
+	return "", ""
+}
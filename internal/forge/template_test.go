@@ -0,0 +1,44 @@
+package forge
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindTemplate(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte(content), 0o644)
+	}
+
+	if path, _ := FindTemplate(&GitHub{}, root); path != "" {
+		t.Errorf("empty repo: got %q", path)
+	}
+
+	write(".github/PULL_REQUEST_TEMPLATE/feature.md", "## Feature")
+	write(".github/PULL_REQUEST_TEMPLATE/bugfix.md", "## Bugfix")
+	if path, content := FindTemplate(&GitHub{}, root); path != ".github/PULL_REQUEST_TEMPLATE/bugfix.md" || content != "## Bugfix" {
+		t.Errorf("template dir: got %q %q", path, content)
+	}
+
+	write(".github/pull_request_template.md", "## Summary")
+	if path, _ := FindTemplate(GitHubCLI{}, root); path != ".github/pull_request_template.md" {
+		t.Errorf("single template: got %q", path)
+	}
+
+	write(".gitlab/merge_request_templates/Default.md", "## MR")
+	if path, _ := FindTemplate(&GitLab{}, root); path != ".gitlab/merge_request_templates/Default.md" {
+		t.Errorf("GitLab: got %q", path)
+	}
+
+	// Gitea falls back to the GitHub location; Bitbucket has no templates.
+	if path, _ := FindTemplate(&Gitea{}, root); path != ".github/pull_request_template.md" {
+		t.Errorf("Gitea: got %q", path)
+	}
+	if path, _ := FindTemplate(&Bitbucket{}, root); path != "" {
+		t.Errorf("Bitbucket: got %q", path)
+	}
+}
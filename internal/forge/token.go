@@ -0,0 +1,72 @@
+package forge
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rasalas/yeet/internal/keyring"
+)
+
+// Token finds an API token for a forge host: the keyring entry stored by
+// `yeet auth forge <host>` first, then the given env vars in order.
+func Token(host string, envs ...string) string {
+	if key, err := keyring.Get(host, keyring.Lookup{}); err == nil {
+		return key
+	}
+	for _, env := range envs {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// githubToken looks up a GitHub token for host, finally asking the gh CLI
+// for the token it is logged in with.
+func githubToken(host string) string {
+	envs := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if host != "github.com" {
+		envs = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	if token := Token(host, envs...); token != "" {
+		return token
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return ""
+	}
+	out, err := exec.Command("gh", "auth", "token", "--hostname", host).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gitlabToken looks up a GitLab token for host, finally asking the glab CLI
+// for the token it is logged in with.
+func gitlabToken(host string) string {
+	if token := Token(host, "GITLAB_TOKEN", "GL_TOKEN"); token != "" {
+		return token
+	}
+	if _, err := exec.LookPath("glab"); err != nil {
+		return ""
+	}
+	out, err := exec.Command("glab", "config", "get", "token", "--host", host).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// bitbucketToken looks up a Bitbucket token for host. An app password is
+// combined with BITBUCKET_USERNAME into "username:app-password".
+func bitbucketToken(host string) string {
+	if token := Token(host, "BITBUCKET_TOKEN"); token != "" {
+		return token
+	}
+	user, pass := os.Getenv("BITBUCKET_USERNAME"), os.Getenv("BITBUCKET_APP_PASSWORD")
+	if user != "" && pass != "" {
+		return user + ":" + pass
+	}
+	return ""
+}
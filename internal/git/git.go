@@ -2,7 +2,10 @@ package git
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -14,16 +17,51 @@ type Git interface {
 	DiffCached() (string, error)
 	Commit(message string) (string, error)
 	Push() (string, error)
-	PushSetUpstream() (string, error)
+	PushSetUpstream(remote string) (string, error)
 	Reset() error
 	LogOneline() (string, error)
 	StatusShort() (string, error)
 	CurrentBranch() (string, error)
 	DefaultBranch() (string, error)
+	RemoteDefaultBranch(remote string) (string, error)
+	Remotes() ([]string, error)
+	RemoteURL(remote string) (string, error)
+	Fetch(remote, branch string) (string, error)
 	LogRange(base string) (string, error)
+	LogBetween(from, to string) (string, error)
+	LatestTag() (string, error)
+	LatestReleaseTag() (string, error)
+	TagDate(tag string) (string, error)
+	CreateTag(name, message string) error
+	PushTag(remote, name string) (string, error)
 	DiffRange(base string) (string, error)
 	DiffStatRange(base string) (string, error)
 	HasUpstream() bool
+	RepoRoot() (string, error)
+	ChangedFilesRange(base string) ([]string, error)
+	FileAuthors(base string, files []string) ([]Author, error)
+	LocalBranches() ([]string, error)
+	IsAncestor(ancestor, ref string) bool
+	CommitCount(base, ref string) (int, error)
+	ResolveCommit(ref string) (string, error)
+	CommitMessage(sha string) (string, error)
+	CommitDiff(sha string) (string, error)
+	CommitStat(sha string) (string, error)
+	DiffCachedFrom(ref string) (string, error)
+	DiffStatCachedFrom(ref string) (string, error)
+	RemoteBranchesContaining(sha string) ([]string, error)
+	Amend(message string) (string, error)
+	Reword(sha, message string) error
+	MergeBase(a, b string) (string, error)
+	UpdateRef(ref, sha string) error
+	ResetSoft(ref string) error
+}
+
+// Author is a commit author with the number of commits counted for them.
+type Author struct {
+	Name    string
+	Email   string
+	Commits int
 }
 
 // Default is the package-level Git implementation used by free functions.
@@ -88,25 +126,48 @@ func (ExecGit) CurrentBranch() (string, error) {
 	return run("rev-parse", "--abbrev-ref", "HEAD")
 }
 
-func (ExecGit) PushSetUpstream() (string, error) {
+func (ExecGit) PushSetUpstream(remote string) (string, error) {
 	branch, err := run("rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
-	return run("push", "--set-upstream", "origin", branch)
+	return run("push", "--set-upstream", remote, branch)
 }
 
 // DefaultBranch detects the default branch (main/master) of the repository.
-func (ExecGit) DefaultBranch() (string, error) {
-	// Try symbolic-ref first (works when origin/HEAD is set)
-	if out, err := run("symbolic-ref", "refs/remotes/origin/HEAD"); err == nil {
-		parts := strings.SplitN(out, "/", 4)
-		if len(parts) == 4 {
-			return parts[3], nil
+func (g ExecGit) DefaultBranch() (string, error) {
+	return g.RemoteDefaultBranch("origin")
+}
+
+// RemoteDefaultBranch detects the default branch of a remote. Remotes added
+// with `git remote add` have no <remote>/HEAD, so the remote is asked
+// directly before falling back to common branch names.
+func (ExecGit) RemoteDefaultBranch(remote string) (string, error) {
+	// Try symbolic-ref first (works when <remote>/HEAD is set)
+	if out, err := run("symbolic-ref", "refs/remotes/"+remote+"/HEAD"); err == nil {
+		if branch, ok := strings.CutPrefix(out, "refs/remotes/"+remote+"/"); ok {
+			return branch, nil
 		}
 	}
 
-	// Fallback: check which common branch exists locally
+	if remote != "origin" {
+		// "ref: refs/heads/main\tHEAD"
+		if out, err := run("ls-remote", "--symref", remote, "HEAD"); err == nil {
+			for _, line := range strings.Split(out, "\n") {
+				if ref, ok := strings.CutPrefix(line, "ref: refs/heads/"); ok {
+					branch, _, _ := strings.Cut(ref, "\t")
+					return branch, nil
+				}
+			}
+		}
+	}
+
+	// Fallback: check which common branch exists on the remote, then locally
+	for _, name := range []string{"main", "master"} {
+		if err := exec.Command("git", "rev-parse", "--verify", "refs/remotes/"+remote+"/"+name).Run(); err == nil {
+			return name, nil
+		}
+	}
 	for _, name := range []string{"main", "master"} {
 		if err := exec.Command("git", "rev-parse", "--verify", name).Run(); err == nil {
 			return name, nil
@@ -116,9 +177,86 @@ func (ExecGit) DefaultBranch() (string, error) {
 	return "", fmt.Errorf("could not detect default branch")
 }
 
+// Remotes lists the configured remote names.
+func (ExecGit) Remotes() ([]string, error) {
+	out, err := run("remote")
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// RemoteURL returns the fetch URL of a remote.
+func (ExecGit) RemoteURL(remote string) (string, error) {
+	out, err := run("remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("no git remote %q", remote)
+	}
+	return out, nil
+}
+
+// Fetch updates the remote-tracking ref of one branch.
+func (ExecGit) Fetch(remote, branch string) (string, error) {
+	return run("fetch", remote, branch)
+}
+
 // LogRange returns one-line log entries between base and HEAD.
-func (ExecGit) LogRange(base string) (string, error) {
-	return run("log", "--oneline", base+"..HEAD")
+func (g ExecGit) LogRange(base string) (string, error) {
+	return g.LogBetween(base, "HEAD")
+}
+
+// LogBetween returns one-line log entries reachable from to but not from.
+// An empty from lists the whole history of to.
+func (ExecGit) LogBetween(from, to string) (string, error) {
+	if from == "" {
+		return run("log", "--oneline", to)
+	}
+	return run("log", "--oneline", from+".."+to)
+}
+
+// LatestTag returns the most recent version tag (v1.2.3 or 1.2.3)
+// reachable from HEAD.
+func (ExecGit) LatestTag() (string, error) {
+	out, err := run("describe", "--tags", "--abbrev=0", "--match", "v[0-9]*", "--match", "[0-9]*")
+	if err != nil {
+		return "", fmt.Errorf("no version tag found")
+	}
+	return out, nil
+}
+
+// LatestReleaseTag returns the most recent version tag reachable from HEAD
+// that is not a pre-release (v1.2.3, but not v1.3.0-rc.1).
+func (ExecGit) LatestReleaseTag() (string, error) {
+	out, err := run("describe", "--tags", "--abbrev=0", "--match", "v[0-9]*", "--match", "[0-9]*", "--exclude", "*-*")
+	if err != nil {
+		return "", fmt.Errorf("no release tag found")
+	}
+	return out, nil
+}
+
+// TagDate returns the date (YYYY-MM-DD) a tag was made: the tagging date of
+// an annotated tag, the commit date of a lightweight one.
+func (ExecGit) TagDate(tag string) (string, error) {
+	out, err := run("for-each-ref", "--format=%(creatordate:short)", "refs/tags/"+tag)
+	if err != nil || out == "" {
+		return "", fmt.Errorf("no tag %s", tag)
+	}
+	return out, nil
+}
+
+// CreateTag creates an annotated tag on HEAD. The message is kept as it
+// is, so Markdown headings are not stripped as comments.
+func (ExecGit) CreateTag(name, message string) error {
+	out, err := run("tag", "--annotate", "--cleanup=verbatim", "--message", message, name)
+	if err != nil {
+		return fmt.Errorf("%s", out)
+	}
+	return nil
+}
+
+// PushTag pushes one tag to a remote.
+func (ExecGit) PushTag(remote, name string) (string, error) {
+	return run("push", remote, "refs/tags/"+name)
 }
 
 // DiffRange returns the diff between the merge-base of base and HEAD.
@@ -137,21 +275,267 @@ func (ExecGit) HasUpstream() bool {
 	return err == nil
 }
 
+// RepoRoot returns the top-level directory of the working tree.
+func (ExecGit) RepoRoot() (string, error) {
+	return run("rev-parse", "--show-toplevel")
+}
+
+// ChangedFilesRange lists the files changed between the merge-base of base and HEAD.
+func (ExecGit) ChangedFilesRange(base string) ([]string, error) {
+	out, err := run("diff", "--name-only", base+"...HEAD")
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// LocalBranches lists the names of all local branches.
+func (ExecGit) LocalBranches() ([]string, error) {
+	out, err := run("for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// IsAncestor reports whether ancestor is reachable from ref.
+func (ExecGit) IsAncestor(ancestor, ref string) bool {
+	return exec.Command("git", "merge-base", "--is-ancestor", ancestor, ref).Run() == nil
+}
+
+// CommitCount returns the number of commits on ref that are not on base.
+func (ExecGit) CommitCount(base, ref string) (int, error) {
+	out, err := run("rev-list", "--count", base+".."+ref)
+	if err != nil {
+		return 0, fmt.Errorf("%s", out)
+	}
+	return strconv.Atoi(out)
+}
+
+// EmptyTree is the hash of git's empty tree, the base to diff a root
+// commit against.
+const EmptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// ResolveCommit returns the full hash of the commit ref points to.
+func (ExecGit) ResolveCommit(ref string) (string, error) {
+	out, err := run("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil || out == "" {
+		return "", fmt.Errorf("unknown commit %q", ref)
+	}
+	return out, nil
+}
+
+// CommitMessage returns the full message of a commit.
+func (ExecGit) CommitMessage(sha string) (string, error) {
+	return run("log", "-1", "--format=%B", sha)
+}
+
+// CommitDiff returns the changes a commit made.
+func (ExecGit) CommitDiff(sha string) (string, error) {
+	return run("show", "--format=", "--patch", sha)
+}
+
+// CommitStat returns the diff stat of a commit.
+func (ExecGit) CommitStat(sha string) (string, error) {
+	return run("show", "--format=", "--stat", sha)
+}
+
+// DiffCachedFrom returns the diff between ref and the index, e.g. what
+// HEAD would contain after amending it with the staged changes.
+func (ExecGit) DiffCachedFrom(ref string) (string, error) {
+	return run("diff", "--cached", ref)
+}
+
+// DiffStatCachedFrom returns the diff stat between ref and the index.
+func (ExecGit) DiffStatCachedFrom(ref string) (string, error) {
+	return run("diff", "--cached", "--stat", ref)
+}
+
+// RemoteBranchesContaining lists the remote-tracking branches a commit
+// has been pushed to.
+func (ExecGit) RemoteBranchesContaining(sha string) ([]string, error) {
+	out, err := run("branch", "--remotes", "--format=%(refname:short)", "--contains", sha)
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// Amend replaces HEAD with a commit of the staged changes and message.
+func (ExecGit) Amend(message string) (string, error) {
+	return run("commit", "--amend", "--allow-empty", "-m", message)
+}
+
+// Reword replaces the message of a commit on the current branch. HEAD is
+// amended in place; for older commits a copy with the new message is made
+// and the commits after it are rebased onto the copy, without an editor.
+// Uncommitted changes are stashed for the rebase and restored after it.
+func (ExecGit) Reword(sha, message string) error {
+	head, err := run("rev-parse", "HEAD")
+	if err != nil {
+		return fmt.Errorf("%s", head)
+	}
+	if sha == head {
+		if out, err := run("commit", "--amend", "--only", "--allow-empty", "-m", message); err != nil {
+			return fmt.Errorf("%s", out)
+		}
+		return nil
+	}
+
+	info, err := run("log", "-1", "--format=%T%x00%P%x00%an%x00%ae%x00%ad", "--date=raw", sha)
+	if err != nil {
+		return fmt.Errorf("%s", info)
+	}
+	fields := strings.Split(info, "\x00")
+	if len(fields) != 5 {
+		return fmt.Errorf("cannot read commit %s", sha)
+	}
+	args := []string{"commit-tree", fields[0]}
+	for _, parent := range strings.Fields(fields[1]) {
+		args = append(args, "-p", parent)
+	}
+	cmd := exec.Command("git", append(args, "-F", "-")...)
+	cmd.Stdin = strings.NewReader(message)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME="+fields[2],
+		"GIT_AUTHOR_EMAIL="+fields[3],
+		"GIT_AUTHOR_DATE="+fields[4],
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", normalizeOutput(out))
+	}
+	reworded := normalizeOutput(out)
+
+	if out, err := run("rebase", "--rebase-merges", "--autostash", "--onto", reworded, sha); err != nil {
+		run("rebase", "--abort")
+		return fmt.Errorf("%s", out)
+	}
+	return nil
+}
+
+// MergeBase returns the best common ancestor of two refs.
+func (ExecGit) MergeBase(a, b string) (string, error) {
+	out, err := run("merge-base", a, b)
+	if err != nil {
+		return "", fmt.Errorf("no common ancestor of %s and %s", a, b)
+	}
+	return out, nil
+}
+
+// UpdateRef points ref at sha, creating it if needed. An empty sha deletes
+// the ref.
+func (ExecGit) UpdateRef(ref, sha string) error {
+	args := []string{"update-ref", ref, sha}
+	if sha == "" {
+		args = []string{"update-ref", "-d", ref}
+	}
+	if out, err := run(args...); err != nil {
+		return fmt.Errorf("%s", out)
+	}
+	return nil
+}
+
+// ResetSoft moves the current branch to ref, keeping the index and the
+// working tree.
+func (ExecGit) ResetSoft(ref string) error {
+	if out, err := run("reset", "--soft", ref); err != nil {
+		return fmt.Errorf("%s", out)
+	}
+	return nil
+}
+
+// authorHistory bounds how far back FileAuthors looks.
+const authorHistory = 200
+
+// FileAuthors returns who committed to files on base most recently, most
+// active first. The current user (git config user.email) is left out.
+func (ExecGit) FileAuthors(base string, files []string) ([]Author, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	args := append([]string{"log", "--no-merges", "--format=%aN%x09%aE", fmt.Sprintf("-n%d", authorHistory), base, "--"}, files...)
+	out, err := run(args...)
+	if err != nil {
+		return nil, err
+	}
+	self, _ := run("config", "user.email")
+
+	var authors []Author
+	index := map[string]int{}
+	for _, line := range strings.Split(out, "\n") {
+		name, email, ok := strings.Cut(line, "\t")
+		if !ok || strings.EqualFold(email, self) {
+			continue
+		}
+		key := strings.ToLower(email)
+		if i, seen := index[key]; seen {
+			authors[i].Commits++
+			continue
+		}
+		index[key] = len(authors)
+		authors = append(authors, Author{Name: name, Email: email, Commits: 1})
+	}
+	// Stable, so ties keep the most recent author first.
+	sort.SliceStable(authors, func(i, j int) bool { return authors[i].Commits > authors[j].Commits })
+	return authors, nil
+}
+
 // Free functions delegate to Default for backward compatibility.
 
-func StageAll() error                           { return Default.StageAll() }
-func DiffStat() (string, error)                 { return Default.DiffStat() }
-func DiffCached() (string, error)               { return Default.DiffCached() }
-func Commit(message string) (string, error)     { return Default.Commit(message) }
-func Push() (string, error)                     { return Default.Push() }
-func PushSetUpstream() (string, error)          { return Default.PushSetUpstream() }
-func Reset() error                              { return Default.Reset() }
-func LogOneline() (string, error)               { return Default.LogOneline() }
-func HasStagedChanges() bool                    { return Default.HasStagedChanges() }
-func StatusShort() (string, error)              { return Default.StatusShort() }
-func CurrentBranch() (string, error)            { return Default.CurrentBranch() }
-func DefaultBranch() (string, error)            { return Default.DefaultBranch() }
-func LogRange(base string) (string, error)      { return Default.LogRange(base) }
-func DiffRange(base string) (string, error)     { return Default.DiffRange(base) }
-func DiffStatRange(base string) (string, error) { return Default.DiffStatRange(base) }
-func HasUpstream() bool                         { return Default.HasUpstream() }
+func StageAll() error                       { return Default.StageAll() }
+func DiffStat() (string, error)             { return Default.DiffStat() }
+func DiffCached() (string, error)           { return Default.DiffCached() }
+func Commit(message string) (string, error) { return Default.Commit(message) }
+func Push() (string, error)                 { return Default.Push() }
+func PushSetUpstream(remote string) (string, error) {
+	return Default.PushSetUpstream(remote)
+}
+func Reset() error                   { return Default.Reset() }
+func LogOneline() (string, error)    { return Default.LogOneline() }
+func HasStagedChanges() bool         { return Default.HasStagedChanges() }
+func StatusShort() (string, error)   { return Default.StatusShort() }
+func CurrentBranch() (string, error) { return Default.CurrentBranch() }
+func DefaultBranch() (string, error) { return Default.DefaultBranch() }
+func RemoteDefaultBranch(remote string) (string, error) {
+	return Default.RemoteDefaultBranch(remote)
+}
+func Remotes() ([]string, error)                  { return Default.Remotes() }
+func RemoteURL(remote string) (string, error)     { return Default.RemoteURL(remote) }
+func Fetch(remote, branch string) (string, error) { return Default.Fetch(remote, branch) }
+func LogRange(base string) (string, error)        { return Default.LogRange(base) }
+func LogBetween(from, to string) (string, error)  { return Default.LogBetween(from, to) }
+func LatestTag() (string, error)                  { return Default.LatestTag() }
+func LatestReleaseTag() (string, error)           { return Default.LatestReleaseTag() }
+func TagDate(tag string) (string, error)          { return Default.TagDate(tag) }
+func CreateTag(name, message string) error        { return Default.CreateTag(name, message) }
+func PushTag(remote, name string) (string, error) { return Default.PushTag(remote, name) }
+func DiffRange(base string) (string, error)       { return Default.DiffRange(base) }
+func DiffStatRange(base string) (string, error)   { return Default.DiffStatRange(base) }
+func HasUpstream() bool                           { return Default.HasUpstream() }
+func RepoRoot() (string, error)                   { return Default.RepoRoot() }
+func ChangedFilesRange(base string) ([]string, error) {
+	return Default.ChangedFilesRange(base)
+}
+func FileAuthors(base string, files []string) ([]Author, error) {
+	return Default.FileAuthors(base, files)
+}
+func LocalBranches() ([]string, error)          { return Default.LocalBranches() }
+func IsAncestor(ancestor, ref string) bool      { return Default.IsAncestor(ancestor, ref) }
+func CommitCount(base, ref string) (int, error) { return Default.CommitCount(base, ref) }
+func ResolveCommit(ref string) (string, error)  { return Default.ResolveCommit(ref) }
+func CommitMessage(sha string) (string, error)  { return Default.CommitMessage(sha) }
+func CommitDiff(sha string) (string, error)     { return Default.CommitDiff(sha) }
+func CommitStat(sha string) (string, error)     { return Default.CommitStat(sha) }
+func DiffCachedFrom(ref string) (string, error) { return Default.DiffCachedFrom(ref) }
+func DiffStatCachedFrom(ref string) (string, error) {
+	return Default.DiffStatCachedFrom(ref)
+}
+func RemoteBranchesContaining(sha string) ([]string, error) {
+	return Default.RemoteBranchesContaining(sha)
+}
+func Amend(message string) (string, error)  { return Default.Amend(message) }
+func Reword(sha, message string) error      { return Default.Reword(sha, message) }
+func MergeBase(a, b string) (string, error) { return Default.MergeBase(a, b) }
+func UpdateRef(ref, sha string) error       { return Default.UpdateRef(ref, sha) }
+func ResetSoft(ref string) error            { return Default.ResetSoft(ref) }
@@ -2,6 +2,8 @@ package git
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
 	"strings"
 	"testing"
 )
@@ -36,26 +38,70 @@ type mockGit struct {
 	diffStatRange    string
 	diffStatRangeErr error
 	hasUpstream      bool
+	repoRoot         string
+	changedFiles     []string
+	fileAuthors      []Author
+	localBranches    []string
+	remotes          []string
+	remoteURLs       map[string]string
+	commitCount      int
+	mergeBase        string
 }
 
-func (m mockGit) HasStagedChanges() bool                { return m.hasStagedChanges }
-func (m mockGit) StageAll() error                       { return m.stageAllErr }
-func (m mockGit) DiffStat() (string, error)             { return m.diffStat, m.diffStatErr }
-func (m mockGit) DiffCached() (string, error)           { return m.diffCached, m.diffCachedErr }
-func (m mockGit) Commit(msg string) (string, error)     { return m.commitOut, m.commitErr }
-func (m mockGit) Push() (string, error)                 { return m.pushOut, m.pushErr }
-func (m mockGit) PushSetUpstream() (string, error)      { return m.pushSetUp, m.pushSetUpErr }
-func (m mockGit) Reset() error                          { return m.resetErr }
-func (m mockGit) LogOneline() (string, error)           { return m.logOneline, m.logOnelineErr }
-func (m mockGit) StatusShort() (string, error)          { return m.statusShort, m.statusShortErr }
-func (m mockGit) CurrentBranch() (string, error)        { return m.currentBranch, m.currentBranchErr }
-func (m mockGit) DefaultBranch() (string, error)        { return m.defaultBranch, m.defaultBranchErr }
-func (m mockGit) LogRange(base string) (string, error)  { return m.logRange, m.logRangeErr }
-func (m mockGit) DiffRange(base string) (string, error) { return m.diffRange, m.diffRangeErr }
+func (m mockGit) HasStagedChanges() bool            { return m.hasStagedChanges }
+func (m mockGit) StageAll() error                   { return m.stageAllErr }
+func (m mockGit) DiffStat() (string, error)         { return m.diffStat, m.diffStatErr }
+func (m mockGit) DiffCached() (string, error)       { return m.diffCached, m.diffCachedErr }
+func (m mockGit) Commit(msg string) (string, error) { return m.commitOut, m.commitErr }
+func (m mockGit) Push() (string, error)             { return m.pushOut, m.pushErr }
+func (m mockGit) PushSetUpstream(remote string) (string, error) {
+	return m.pushSetUp, m.pushSetUpErr
+}
+func (m mockGit) Reset() error                   { return m.resetErr }
+func (m mockGit) LogOneline() (string, error)    { return m.logOneline, m.logOnelineErr }
+func (m mockGit) StatusShort() (string, error)   { return m.statusShort, m.statusShortErr }
+func (m mockGit) CurrentBranch() (string, error) { return m.currentBranch, m.currentBranchErr }
+func (m mockGit) DefaultBranch() (string, error) { return m.defaultBranch, m.defaultBranchErr }
+func (m mockGit) RemoteDefaultBranch(remote string) (string, error) {
+	return m.defaultBranch, m.defaultBranchErr
+}
+func (m mockGit) Remotes() ([]string, error)                  { return m.remotes, nil }
+func (m mockGit) RemoteURL(remote string) (string, error)     { return m.remoteURLs[remote], nil }
+func (m mockGit) Fetch(remote, branch string) (string, error) { return "", nil }
+func (m mockGit) LogRange(base string) (string, error)        { return m.logRange, m.logRangeErr }
+func (m mockGit) LogBetween(from, to string) (string, error)  { return m.logRange, m.logRangeErr }
+func (m mockGit) LatestTag() (string, error)                  { return "", nil }
+func (m mockGit) LatestReleaseTag() (string, error)           { return "", nil }
+func (m mockGit) TagDate(tag string) (string, error)          { return "", nil }
+func (m mockGit) CreateTag(name, message string) error        { return nil }
+func (m mockGit) PushTag(remote, name string) (string, error) { return "", nil }
+func (m mockGit) DiffRange(base string) (string, error)       { return m.diffRange, m.diffRangeErr }
 func (m mockGit) DiffStatRange(base string) (string, error) {
 	return m.diffStatRange, m.diffStatRangeErr
 }
-func (m mockGit) HasUpstream() bool { return m.hasUpstream }
+func (m mockGit) HasUpstream() bool                               { return m.hasUpstream }
+func (m mockGit) RepoRoot() (string, error)                       { return m.repoRoot, nil }
+func (m mockGit) ChangedFilesRange(base string) ([]string, error) { return m.changedFiles, nil }
+func (m mockGit) FileAuthors(base string, files []string) ([]Author, error) {
+	return m.fileAuthors, nil
+}
+func (m mockGit) LocalBranches() ([]string, error)          { return m.localBranches, nil }
+func (m mockGit) IsAncestor(ancestor, ref string) bool      { return false }
+func (m mockGit) CommitCount(base, ref string) (int, error) { return m.commitCount, nil }
+func (m mockGit) ResolveCommit(ref string) (string, error)  { return ref, nil }
+func (m mockGit) CommitMessage(sha string) (string, error)  { return "", nil }
+func (m mockGit) CommitDiff(sha string) (string, error)     { return m.diffRange, nil }
+func (m mockGit) CommitStat(sha string) (string, error)     { return m.diffStatRange, nil }
+func (m mockGit) DiffCachedFrom(ref string) (string, error) { return m.diffCached, nil }
+func (m mockGit) DiffStatCachedFrom(ref string) (string, error) {
+	return m.diffStat, nil
+}
+func (m mockGit) RemoteBranchesContaining(sha string) ([]string, error) { return nil, nil }
+func (m mockGit) Amend(message string) (string, error)                  { return m.commitOut, nil }
+func (m mockGit) Reword(sha, message string) error                      { return nil }
+func (m mockGit) MergeBase(a, b string) (string, error)                 { return m.mergeBase, nil }
+func (m mockGit) UpdateRef(ref, sha string) error                       { return nil }
+func (m mockGit) ResetSoft(ref string) error                            { return nil }
 
 func TestFreeFunctionsDelegateToDefault(t *testing.T) {
 	original := Default
@@ -71,6 +117,7 @@ func TestFreeFunctionsDelegateToDefault(t *testing.T) {
 		diffRange:        "diff --git a/foo.go b/foo.go",
 		diffStatRange:    " foo.go | 3 +++",
 		hasUpstream:      true,
+		mergeBase:        "0a1b2c3",
 	}
 	Default = mock
 
@@ -103,6 +150,14 @@ func TestFreeFunctionsDelegateToDefault(t *testing.T) {
 		t.Errorf("LogRange = %q, %v", lr, err)
 	}
 
+	if lb, err := LogBetween("v1.0.0", "v1.1.0"); err != nil || lb != "abc123 commit one\ndef456 commit two" {
+		t.Errorf("LogBetween = %q, %v", lb, err)
+	}
+
+	if mb, err := MergeBase("main", "HEAD"); err != nil || mb != "0a1b2c3" {
+		t.Errorf("MergeBase = %q, %v", mb, err)
+	}
+
 	dr, err := DiffRange("main")
 	if err != nil || dr != "diff --git a/foo.go b/foo.go" {
 		t.Errorf("DiffRange = %q, %v", dr, err)
@@ -170,3 +225,103 @@ func TestNormalizeOutputTrimsCRLF(t *testing.T) {
 		t.Fatalf("normalizeOutput() = %q, want %q", got, "value")
 	}
 }
+
+// tempRepo makes a new repository the working directory and returns a
+// helper that runs git in it, failing the test on errors.
+func tempRepo(t *testing.T) func(args ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Chdir(t.TempDir())
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	t.Setenv("GIT_AUTHOR_NAME", "Author")
+	t.Setenv("GIT_AUTHOR_EMAIL", "author@example.com")
+
+	must := func(args ...string) string {
+		t.Helper()
+		out, err := run(args...)
+		if err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+		return out
+	}
+	must("init", "-q", "-b", "main")
+	return must
+}
+
+func TestExecGitReword(t *testing.T) {
+	must := tempRepo(t)
+	for _, name := range []string{"a", "b", "c"} {
+		if err := os.WriteFile(name, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		must("add", name)
+		must("commit", "-q", "-m", "add "+name)
+	}
+	// Uncommitted changes survive the rebase.
+	os.WriteFile("a", []byte("changed"), 0644)
+
+	g := ExecGit{}
+	middle := must("rev-parse", "HEAD~1")
+	if err := g.Reword(middle, "feat: add b\n\nWith a body."); err != nil {
+		t.Fatal(err)
+	}
+	if got := must("log", "--format=%s|%an", "main"); got != "add c|Author\nfeat: add b|Author\nadd a|Author" {
+		t.Errorf("log after reword =\n%s", got)
+	}
+	if body := must("log", "-1", "--format=%b", "HEAD~1"); body != "With a body." {
+		t.Errorf("body = %q", body)
+	}
+	if status := must("status", "--short"); status != " M a" {
+		t.Errorf("status = %q", status)
+	}
+
+	head := must("rev-parse", "HEAD")
+	if err := g.Reword(head, "feat: add c"); err != nil {
+		t.Fatal(err)
+	}
+	if got := must("log", "-1", "--format=%s", "HEAD"); got != "feat: add c" {
+		t.Errorf("HEAD subject = %q", got)
+	}
+	if files := must("show", "--format=", "--name-only", "HEAD"); files != "c" {
+		t.Errorf("HEAD files = %q, want only c (the worktree change must not be amended in)", files)
+	}
+}
+
+func TestExecGitTagDate(t *testing.T) {
+	must := tempRepo(t)
+	t.Setenv("GIT_COMMITTER_DATE", "2024-03-01T12:00:00Z")
+	must("commit", "-q", "--allow-empty", "-m", "init")
+	must("tag", "v1.0.0")
+	t.Setenv("GIT_COMMITTER_DATE", "2024-05-02T12:00:00Z")
+	must("tag", "-a", "-m", "v1.1.0", "v1.1.0")
+
+	g := ExecGit{}
+	for tag, want := range map[string]string{"v1.0.0": "2024-03-01", "v1.1.0": "2024-05-02"} {
+		if got, err := g.TagDate(tag); err != nil || got != want {
+			t.Errorf("TagDate(%s) = %q, %v, want %s", tag, got, err, want)
+		}
+	}
+	if _, err := g.TagDate("HEAD"); err == nil {
+		t.Error("TagDate(HEAD) succeeded, want an error")
+	}
+}
+
+func TestExecGitLatestReleaseTag(t *testing.T) {
+	must := tempRepo(t)
+	must("commit", "-q", "--allow-empty", "-m", "init")
+	must("tag", "v1.2.0")
+	must("commit", "-q", "--allow-empty", "-m", "feat: next")
+	must("tag", "v1.3.0-rc.1")
+
+	g := ExecGit{}
+	if got, err := g.LatestTag(); err != nil || got != "v1.3.0-rc.1" {
+		t.Errorf("LatestTag = %q, %v, want v1.3.0-rc.1", got, err)
+	}
+	if got, err := g.LatestReleaseTag(); err != nil || got != "v1.2.0" {
+		t.Errorf("LatestReleaseTag = %q, %v, want v1.2.0", got, err)
+	}
+}
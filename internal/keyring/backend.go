@@ -0,0 +1,97 @@
+package keyring
+
+import (
+	"errors"
+	"os"
+	"sync"
+
+	gokeyring "github.com/zalando/go-keyring"
+)
+
+// Backend stores keys set with `yeet auth set`.
+type Backend interface {
+	Name() string
+	Location() string
+	Get(provider string) (string, error)
+	Set(provider, apiKey string) error
+	Delete(provider string) error
+}
+
+// Backend names, also accepted by YEET_KEYRING_BACKEND and `yeet auth migrate`.
+const (
+	BackendSystem = "system"
+	BackendFile   = "file"
+)
+
+// systemBackend is the OS keyring (macOS Keychain, Windows Credential Manager,
+// Linux Secret Service).
+type systemBackend struct{}
+
+func (systemBackend) Name() string     { return BackendSystem }
+func (systemBackend) Location() string { return "OS keyring" }
+
+func (systemBackend) Get(provider string) (string, error) {
+	return gokeyring.Get(serviceName, provider)
+}
+
+func (systemBackend) Set(provider, apiKey string) error {
+	return gokeyring.Set(serviceName, provider, apiKey)
+}
+
+func (systemBackend) Delete(provider string) error {
+	return gokeyring.Delete(serviceName, provider)
+}
+
+var (
+	systemOnce      sync.Once
+	systemAvailable bool
+)
+
+// SystemAvailable reports whether the OS keyring can be reached. Headless
+// Linux machines often have no D-Bus Secret Service.
+func SystemAvailable() bool {
+	systemOnce.Do(func() {
+		_, err := gokeyring.Get(serviceName, "yeet-probe")
+		systemAvailable = err == nil || errors.Is(err, gokeyring.ErrNotFound)
+	})
+	return systemAvailable
+}
+
+// Check reports whether b can be read, e.g. a missing passphrase for the file.
+func Check(b Backend) error {
+	if _, err := b.Get("yeet-probe"); err != nil && !errors.Is(err, gokeyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// BackendByName returns the named backend.
+func BackendByName(name string) (Backend, error) {
+	switch name {
+	case BackendSystem:
+		return systemBackend{}, nil
+	case BackendFile:
+		return newFileBackend()
+	}
+	return nil, errors.New("unknown keyring backend " + name + " (use system or file)")
+}
+
+// Active returns the backend keys are read from and written to:
+//  1. YEET_KEYRING_BACKEND, when set
+//  2. the encrypted file, once it exists (e.g. after `yeet auth migrate file`)
+//  3. the OS keyring, when available
+//  4. the encrypted file otherwise
+func Active() (Backend, error) {
+	if name := os.Getenv("YEET_KEYRING_BACKEND"); name != "" {
+		return BackendByName(name)
+	}
+	if path := filePath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return newFileBackend()
+		}
+	}
+	if SystemAvailable() {
+		return systemBackend{}, nil
+	}
+	return newFileBackend()
+}
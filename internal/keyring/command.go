@@ -0,0 +1,81 @@
+package keyring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keyCmdTimeout bounds a key_cmd run, leaving time for unlock prompts
+// (e.g. 1Password's biometric dialog or a gpg pinentry).
+const keyCmdTimeout = 20 * time.Second
+
+type cmdResult struct {
+	key string
+	err error
+}
+
+var (
+	cmdMu    sync.Mutex
+	cmdCache = map[string]cmdResult{}
+)
+
+// RunKeyCmd runs a key_cmd through the shell and returns its trimmed output.
+// Results, including failures, are cached for the lifetime of the process so
+// a password manager prompts at most once.
+func RunKeyCmd(command string) (string, error) {
+	cmdMu.Lock()
+	defer cmdMu.Unlock()
+	if r, ok := cmdCache[command]; ok {
+		return r.key, r.err
+	}
+	key, err := runKeyCmd(command)
+	cmdCache[command] = cmdResult{key, err}
+	return key, err
+}
+
+func runKeyCmd(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), keyCmdTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("key_cmd timed out after %s", keyCmdTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("key_cmd failed: %s", firstLine(msg))
+		}
+		return "", fmt.Errorf("key_cmd failed: %w", err)
+	}
+
+	// Password managers like pass print the secret on the first line,
+	// followed by optional metadata.
+	key := firstLine(strings.TrimSpace(stdout.String()))
+	if key == "" {
+		return "", fmt.Errorf("key_cmd printed no key")
+	}
+	return key, nil
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
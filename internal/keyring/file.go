@@ -0,0 +1,240 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rasalas/yeet/internal/xdg"
+	gokeyring "github.com/zalando/go-keyring"
+)
+
+// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256.
+const pbkdf2Iterations = 600_000
+
+// Key derivation methods of the encrypted file.
+const (
+	kdfPassphrase = "pbkdf2-sha256" // YEET_KEYRING_PASSPHRASE
+	kdfKeyFile    = "keyfile"       // random machine key next to the file
+)
+
+// fileBackend keeps keys in an AES-256-GCM encrypted JSON file. The key is
+// derived from YEET_KEYRING_PASSPHRASE when set, otherwise it is a random
+// machine key stored with mode 0600 next to the file.
+type fileBackend struct {
+	path string
+}
+
+// encryptedFile is the on-disk format of keys.enc.
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt,omitempty"`
+	Iterations int    `json:"iterations,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+func filePath() string {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "yeet", "keys.enc")
+}
+
+func newFileBackend() (Backend, error) {
+	path := filePath()
+	if path == "" {
+		return nil, errors.New("cannot determine data directory for the keyring file")
+	}
+	return fileBackend{path: path}, nil
+}
+
+func (f fileBackend) Name() string     { return BackendFile }
+func (f fileBackend) Location() string { return f.path }
+
+func (f fileBackend) keyFilePath() string {
+	return filepath.Join(filepath.Dir(f.path), "keys.key")
+}
+
+func (f fileBackend) Get(provider string) (string, error) {
+	keys, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	key, ok := keys[provider]
+	if !ok {
+		return "", gokeyring.ErrNotFound
+	}
+	return key, nil
+}
+
+func (f fileBackend) Set(provider, apiKey string) error {
+	keys, err := f.load()
+	if err != nil {
+		return err
+	}
+	keys[provider] = apiKey
+	return f.save(keys)
+}
+
+func (f fileBackend) Delete(provider string) error {
+	keys, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := keys[provider]; !ok {
+		return gokeyring.ErrNotFound
+	}
+	delete(keys, provider)
+	if len(keys) == 0 {
+		return os.Remove(f.path)
+	}
+	return f.save(keys)
+}
+
+// load decrypts the file. A missing file is an empty keyring.
+func (f fileBackend) load() (map[string]string, error) {
+	keys := map[string]string{}
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return keys, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ef encryptedFile
+	if err := json.Unmarshal(data, &ef); err != nil {
+		return nil, fmt.Errorf("corrupt keyring file %s: %w", f.path, err)
+	}
+	aead, err := f.cipher(ef.KDF, ef.Salt, ef.Iterations, false)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, ef.Nonce, ef.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt %s (wrong passphrase?)", f.path)
+	}
+	if err := json.Unmarshal(plain, &keys); err != nil {
+		return nil, fmt.Errorf("corrupt keyring file %s: %w", f.path, err)
+	}
+	return keys, nil
+}
+
+// save encrypts keys with a fresh salt and nonce and replaces the file atomically.
+func (f fileBackend) save(keys map[string]string) error {
+	ef := encryptedFile{Version: 1, KDF: kdfKeyFile}
+	if os.Getenv("YEET_KEYRING_PASSPHRASE") != "" {
+		ef.KDF = kdfPassphrase
+		ef.Iterations = pbkdf2Iterations
+		ef.Salt = make([]byte, 16)
+		if _, err := rand.Read(ef.Salt); err != nil {
+			return err
+		}
+	}
+	aead, err := f.cipher(ef.KDF, ef.Salt, ef.Iterations, true)
+	if err != nil {
+		return err
+	}
+	ef.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(ef.Nonce); err != nil {
+		return err
+	}
+	plain, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	ef.Data = aead.Seal(nil, ef.Nonce, plain, nil)
+
+	data, err := json.MarshalIndent(ef, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivate(f.path, data)
+}
+
+// cipher derives the file key for kdf. create allows generating a missing
+// machine key.
+func (f fileBackend) cipher(kdf string, salt []byte, iterations int, create bool) (cipher.AEAD, error) {
+	var key []byte
+	switch kdf {
+	case kdfPassphrase:
+		pass := os.Getenv("YEET_KEYRING_PASSPHRASE")
+		if pass == "" {
+			return nil, fmt.Errorf("%s is passphrase-protected — set YEET_KEYRING_PASSPHRASE", f.path)
+		}
+		var err error
+		if key, err = pbkdf2.Key(sha256.New, pass, salt, iterations, 32); err != nil {
+			return nil, err
+		}
+	case kdfKeyFile:
+		var err error
+		if key, err = f.machineKey(create); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key derivation %q in %s", kdf, f.path)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// machineKey reads the random 32-byte machine key, generating it on first use.
+func (f fileBackend) machineKey(create bool) ([]byte, error) {
+	path := f.keyFilePath()
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("invalid machine key %s", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) || !create {
+		return nil, fmt.Errorf("cannot read machine key: %w", err)
+	}
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := writePrivate(path, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// writePrivate writes data with mode 0600 via a temp file and rename.
+func writePrivate(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,51 @@
+package keyring
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFileBackend(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("YEET_KEYRING_PASSPHRASE", "")
+	b, err := newFileBackend()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := b.(fileBackend)
+
+	if err := b.Set("groq", "gsk-secret"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	data, _ := os.ReadFile(f.path)
+	if strings.Contains(string(data), "gsk-secret") {
+		t.Error("key stored in plain text")
+	}
+	if key, err := b.Get("groq"); err != nil || key != "gsk-secret" {
+		t.Errorf("Get = %q, %v", key, err)
+	}
+
+	// Switching to a passphrase re-encrypts on the next write.
+	t.Setenv("YEET_KEYRING_PASSPHRASE", "hunter2")
+	if err := b.Set("openai", "sk-other"); err != nil {
+		t.Fatalf("Set with passphrase: %v", err)
+	}
+	if key, err := b.Get("groq"); err != nil || key != "gsk-secret" {
+		t.Errorf("Get after re-encrypt = %q, %v", key, err)
+	}
+	t.Setenv("YEET_KEYRING_PASSPHRASE", "wrong")
+	if err := Check(b); err == nil {
+		t.Error("expected an error for a wrong passphrase")
+	}
+
+	t.Setenv("YEET_KEYRING_PASSPHRASE", "hunter2")
+	for _, p := range []string{"groq", "openai"} {
+		if err := b.Delete(p); err != nil {
+			t.Fatalf("Delete %s: %v", p, err)
+		}
+	}
+	if _, err := os.Stat(f.path); !os.IsNotExist(err) {
+		t.Error("empty keyring file not removed")
+	}
+}
@@ -0,0 +1,488 @@
+package keyring
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rasalas/yeet/internal/xdg"
+)
+
+// ImportedKey is an API key found in another tool's configuration.
+type ImportedKey struct {
+	Name string // yeet provider name, or env var name for dotenv-style files
+	Key  string
+	Path string // file the key was read from
+}
+
+// Importer reads the API keys another tool has stored.
+type Importer struct {
+	Source KeySource
+	Load   func() []ImportedKey
+}
+
+var (
+	importersMu sync.Mutex
+	importers   = []Importer{
+		{SourceOpenCode, loadOpenCode},
+		{SourceLLM, loadLLM},
+		{SourceAider, loadAider},
+		{SourceContinue, loadContinue},
+		{SourceShell, loadShellRC},
+	}
+	// imported caches what the importers found, in importer order. Other
+	// tools' files are read once per run, not once per provider lookup.
+	imported []importedKeys
+)
+
+type importedKeys struct {
+	source KeySource
+	keys   []ImportedKey
+}
+
+// RegisterImporter adds an importer after the builtin ones.
+func RegisterImporter(imp Importer) {
+	importersMu.Lock()
+	defer importersMu.Unlock()
+	importers = append(importers, imp)
+	imported = nil
+}
+
+// Importers returns the registered importers in lookup order.
+func Importers() []Importer {
+	importersMu.Lock()
+	defer importersMu.Unlock()
+	return append([]Importer(nil), importers...)
+}
+
+// importedByTools runs the importers on first use and returns their keys.
+func importedByTools() []importedKeys {
+	importersMu.Lock()
+	defer importersMu.Unlock()
+	if imported == nil {
+		for _, imp := range importers {
+			imported = append(imported, importedKeys{imp.Source, imp.Load()})
+		}
+	}
+	return imported
+}
+
+// resetImported drops the cached importer results.
+func resetImported() {
+	importersMu.Lock()
+	defer importersMu.Unlock()
+	imported = nil
+}
+
+// toolAliases maps provider names used by other tools to yeet's names.
+var toolAliases = map[string]string{
+	"claude": "anthropic",
+	"gemini": "google",
+}
+
+func providerName(tool string) string {
+	name := strings.ToLower(strings.TrimSpace(tool))
+	if alias, ok := toolAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// withPath sets Path on each key.
+func withPath(keys []ImportedKey, path string) []ImportedKey {
+	for i := range keys {
+		keys[i].Path = path
+	}
+	return keys
+}
+
+// --- OpenCode ---
+
+// openCodeAuth represents an entry in OpenCode's auth.json.
+type openCodeAuth struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+}
+
+func openCodeAuthPath() string {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "opencode", "auth.json")
+}
+
+func loadOpenCodeAuth() map[string]openCodeAuth {
+	path := openCodeAuthPath()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var auth map[string]openCodeAuth
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return nil
+	}
+	return auth
+}
+
+// loadOpenCode reads ~/.local/share/opencode/auth.json.
+// Only type:"api" keys are used, oauth tokens are ignored.
+func loadOpenCode() []ImportedKey {
+	auth := loadOpenCodeAuth()
+	var keys []ImportedKey
+	for name, entry := range auth {
+		if entry.Type == "api" && entry.Key != "" {
+			keys = append(keys, ImportedKey{Name: name, Key: entry.Key})
+		}
+	}
+	sortKeys(keys)
+	return withPath(keys, openCodeAuthPath())
+}
+
+// OpenCodeProviders returns provider names that have type:"api" keys
+// in OpenCode's auth.json.
+func OpenCodeProviders() []string {
+	auth := loadOpenCodeAuth()
+	var providers []string
+	for name, entry := range auth {
+		if entry.Type == "api" && entry.Key != "" {
+			providers = append(providers, name)
+		}
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// --- llm ---
+
+// llmKeysPath returns the keys.json of Simon Willison's llm CLI.
+func llmKeysPath() string {
+	if dir := os.Getenv("LLM_USER_PATH"); dir != "" {
+		return filepath.Join(dir, "keys.json")
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "io.datasette.llm", "keys.json")
+}
+
+func loadLLM() []ImportedKey {
+	path := llmKeysPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var stored map[string]any
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil
+	}
+	var keys []ImportedKey
+	for name, v := range stored {
+		key, ok := v.(string)
+		if !ok || strings.HasPrefix(name, "//") {
+			continue
+		}
+		keys = append(keys, ImportedKey{Name: providerName(name), Key: key})
+	}
+	sortKeys(keys)
+	return withPath(keys, path)
+}
+
+// --- aider ---
+
+// aiderFiles returns aider's .aider.conf.yml and .env in dir.
+func aiderFiles(dir string) []ImportedKey {
+	var keys []ImportedKey
+	path := filepath.Join(dir, ".aider.conf.yml")
+	if data, err := os.ReadFile(path); err == nil {
+		keys = append(keys, withPath(parseAiderConf(string(data)), path)...)
+	}
+	path = filepath.Join(dir, ".env")
+	if data, err := os.ReadFile(path); err == nil {
+		keys = append(keys, withPath(parseEnvFile(string(data)), path)...)
+	}
+	return keys
+}
+
+// loadAider reads aider's files in the home directory. Those in the
+// working directory belong to whatever repository yeet runs in, so they
+// are never used as keys — see ProjectKeys.
+func loadAider() []ImportedKey {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return aiderFiles(home)
+}
+
+// ProjectKeys returns the keys in .aider.conf.yml and .env of the working
+// directory. Lookups never use them; yeet auth import copies them into the
+// keyring only when asked to.
+func ProjectKeys() []ImportedKey {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	if home, err := os.UserHomeDir(); err == nil && filepath.Clean(wd) == filepath.Clean(home) {
+		return nil
+	}
+	return aiderFiles(wd)
+}
+
+// MatchImported returns the first key in keys for the provider or its env
+// var.
+func MatchImported(keys []ImportedKey, provider string, lookup Lookup) (ImportedKey, bool) {
+	for _, k := range keys {
+		if k.Key != "" && (k.Name == provider || lookup.Env != "" && k.Name == lookup.Env) {
+			return k, true
+		}
+	}
+	return ImportedKey{}, false
+}
+
+// parseAiderConf reads openai-api-key, anthropic-api-key and the
+// api-key list ("provider=key") from .aider.conf.yml.
+func parseAiderConf(text string) []ImportedKey {
+	var keys []ImportedKey
+	addPair := func(item string) {
+		if name, key, ok := strings.Cut(unquote(item), "="); ok {
+			keys = append(keys, ImportedKey{Name: providerName(name), Key: strings.TrimSpace(key)})
+		}
+	}
+
+	inList := false
+	for _, line := range strings.Split(text, "\n") {
+		line = stripYAMLComment(line)
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if inList && strings.HasPrefix(trimmed, "- ") {
+			addPair(trimmed[2:])
+			continue
+		}
+		inList = false
+		key, value, ok := yamlPair(trimmed)
+		if !ok || line != trimmed {
+			continue
+		}
+		switch key {
+		case "openai-api-key":
+			keys = append(keys, ImportedKey{Name: "openai", Key: value})
+		case "anthropic-api-key":
+			keys = append(keys, ImportedKey{Name: "anthropic", Key: value})
+		case "api-key":
+			if value == "" {
+				inList = true
+				continue
+			}
+			for _, item := range strings.Split(strings.Trim(value, "[]"), ",") {
+				addPair(item)
+			}
+		}
+	}
+	return keys
+}
+
+// --- Continue ---
+
+func continueDir() string {
+	if dir := os.Getenv("CONTINUE_GLOBAL_DIR"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".continue")
+}
+
+func loadContinue() []ImportedKey {
+	dir := continueDir()
+	if dir == "" {
+		return nil
+	}
+	var keys []ImportedKey
+	path := filepath.Join(dir, "config.yaml")
+	if data, err := os.ReadFile(path); err == nil {
+		keys = append(keys, withPath(parseContinueYAML(string(data)), path)...)
+	}
+	path = filepath.Join(dir, "config.json")
+	if data, err := os.ReadFile(path); err == nil {
+		keys = append(keys, withPath(parseContinueJSON(data), path)...)
+	}
+	return keys
+}
+
+// parseContinueJSON reads the models of the legacy config.json.
+func parseContinueJSON(data []byte) []ImportedKey {
+	var cfg struct {
+		Models []struct {
+			Provider string `json:"provider"`
+			APIKey   string `json:"apiKey"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil
+	}
+	var keys []ImportedKey
+	for _, m := range cfg.Models {
+		if m.APIKey != "" {
+			keys = append(keys, ImportedKey{Name: providerName(m.Provider), Key: m.APIKey})
+		}
+	}
+	return keys
+}
+
+// parseContinueYAML reads provider/apiKey pairs from the list items of
+// config.yaml. Each "- key: value" line starts a new item.
+func parseContinueYAML(text string) []ImportedKey {
+	var keys []ImportedKey
+	var provider, apiKey string
+	flush := func() {
+		if provider != "" && apiKey != "" {
+			keys = append(keys, ImportedKey{Name: providerName(provider), Key: apiKey})
+		}
+		provider, apiKey = "", ""
+	}
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(stripYAMLComment(line))
+		if rest, ok := strings.CutPrefix(trimmed, "- "); ok {
+			if _, _, isPair := yamlPair(rest); !isPair {
+				continue
+			}
+			flush()
+			trimmed = rest
+		}
+		switch key, value, _ := yamlPair(trimmed); key {
+		case "provider":
+			provider = value
+		case "apiKey":
+			apiKey = value
+		}
+	}
+	flush()
+	return keys
+}
+
+// --- shell rc files ---
+
+// shellRCFiles lists the startup files searched for exported API keys.
+func shellRCFiles() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, name := range []string{".zshenv", ".zprofile", ".zshrc", ".bash_profile", ".bashrc", ".profile", ".config/fish/config.fish"} {
+		files = append(files, filepath.Join(home, name))
+	}
+	return files
+}
+
+func loadShellRC() []ImportedKey {
+	var keys []ImportedKey
+	for _, path := range shellRCFiles() {
+		if data, err := os.ReadFile(path); err == nil {
+			keys = append(keys, withPath(parseEnvFile(string(data)), path)...)
+		}
+	}
+	return keys
+}
+
+// parseEnvFile reads NAME=value assignments from a dotenv file or shell
+// script: "NAME=v", "export NAME=v" and fish's "set -gx NAME v". Values
+// computed at runtime ($VAR, $(cmd), backticks) are skipped.
+func parseEnvFile(text string) []ImportedKey {
+	var keys []ImportedKey
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		var name, value string
+		if fields := strings.Fields(line); len(fields) >= 4 && fields[0] == "set" && strings.HasPrefix(fields[1], "-") && strings.Contains(fields[1], "x") {
+			name, value = fields[2], strings.Join(fields[3:], " ")
+		} else {
+			line = strings.TrimPrefix(line, "export ")
+			var ok bool
+			if name, value, ok = strings.Cut(line, "="); !ok {
+				continue
+			}
+		}
+		name = strings.TrimSpace(name)
+		value = unquote(stripShellComment(strings.TrimSpace(value)))
+		if !isEnvName(name) || value == "" || strings.ContainsAny(value, "$`") {
+			continue
+		}
+		keys = append(keys, ImportedKey{Name: name, Key: value})
+	}
+	return keys
+}
+
+func isEnvName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !(r == '_' || r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// --- parsing helpers ---
+
+// yamlPair splits a "key: value" line. The value is unquoted.
+func yamlPair(line string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(line, ":")
+	if !ok || strings.ContainsAny(key, " \t\"'") || value != "" && value[0] != ' ' {
+		return "", "", false
+	}
+	return key, unquote(strings.TrimSpace(value)), true
+}
+
+// stripYAMLComment drops a trailing " # comment".
+func stripYAMLComment(line string) string {
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return ""
+	}
+	if i := strings.Index(line, " #"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// stripShellComment drops a trailing comment after an unquoted value.
+func stripShellComment(value string) string {
+	if value == "" {
+		return value
+	}
+	if q := value[0]; q == '"' || q == '\'' {
+		if end := strings.LastIndexByte(value, q); end > 0 {
+			return value[:end+1]
+		}
+		return value
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		return strings.TrimSpace(value[:i])
+	}
+	return value
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func sortKeys(keys []ImportedKey) {
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+}
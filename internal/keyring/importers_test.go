@@ -0,0 +1,145 @@
+package keyring
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseAiderConf(t *testing.T) {
+	conf := `model: sonnet
+openai-api-key: sk-openai # personal
+anthropic-api-key: "sk-ant"
+api-key:
+  - gemini=AIza-key
+  - groq=gsk-key
+`
+	want := []ImportedKey{
+		{Name: "openai", Key: "sk-openai"},
+		{Name: "anthropic", Key: "sk-ant"},
+		{Name: "google", Key: "AIza-key"},
+		{Name: "groq", Key: "gsk-key"},
+	}
+	if got := parseAiderConf(conf); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAiderConf = %+v, want %+v", got, want)
+	}
+
+	inline := parseAiderConf("api-key: [mistral=m-key, openrouter=or-key]\n")
+	if len(inline) != 2 || inline[1].Name != "openrouter" || inline[1].Key != "or-key" {
+		t.Errorf("inline api-key list = %+v", inline)
+	}
+}
+
+func TestParseContinue(t *testing.T) {
+	yaml := `name: My Assistant
+models:
+  - name: Claude
+    provider: anthropic
+    model: claude-sonnet-4-6
+    apiKey: sk-ant-yaml
+    roles:
+      - chat
+  - name: Local
+    provider: ollama
+    model: llama3
+`
+	want := []ImportedKey{{Name: "anthropic", Key: "sk-ant-yaml"}}
+	if got := parseContinueYAML(yaml); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseContinueYAML = %+v, want %+v", got, want)
+	}
+
+	json := `{"models": [{"title": "Gemini", "provider": "gemini", "apiKey": "AIza-json"}]}`
+	if got := parseContinueJSON([]byte(json)); len(got) != 1 || got[0].Name != "google" || got[0].Key != "AIza-json" {
+		t.Errorf("parseContinueJSON = %+v", got)
+	}
+}
+
+func TestParseEnvFile(t *testing.T) {
+	rc := `# keys
+export OPENAI_API_KEY="sk-one" # work
+GROQ_API_KEY=gsk-two
+export HOME_COPY=$HOME
+export TOKEN=$(pass show token)
+set -gx MISTRAL_API_KEY 'm-three'
+alias ll='ls -l'
+`
+	want := []ImportedKey{
+		{Name: "OPENAI_API_KEY", Key: "sk-one"},
+		{Name: "GROQ_API_KEY", Key: "gsk-two"},
+		{Name: "MISTRAL_API_KEY", Key: "m-three"},
+	}
+	if got := parseEnvFile(rc); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseEnvFile = %+v, want %+v", got, want)
+	}
+}
+
+func TestLocateImported(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("YEET_KEYRING_BACKEND", BackendFile)
+	t.Setenv("XDG_DATA_HOME", dir)
+	t.Setenv("LLM_USER_PATH", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("CONTINUE_GLOBAL_DIR", dir)
+	t.Setenv("GOOGLE_TEST_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	resetImported()
+	t.Cleanup(resetImported)
+	llmKeys := filepath.Join(dir, "keys.json")
+	if err := os.WriteFile(llmKeys, []byte(`{"// Note": "This file stores secret API credentials. Do not share!", "gemini": "AIza-from-llm"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	o := Locate("google", Lookup{Env: "GOOGLE_TEST_KEY"})
+	if o.Key != "AIza-from-llm" || o.Source != SourceLLM || o.Where != llmKeys {
+		t.Errorf("Locate(google) = %+v", o)
+	}
+	if o := Locate("openai", Lookup{}); o.Source != SourceNone {
+		t.Errorf("Locate(openai) = %+v, want no key", o)
+	}
+
+	// A repository's .env is never a live key, only an explicit import.
+	project := t.TempDir()
+	t.Chdir(project)
+	env := filepath.Join(project, ".env")
+	if err := os.WriteFile(env, []byte("OPENAI_API_KEY=sk-from-repo\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	resetImported()
+	if o := Locate("openai", Lookup{Env: "OPENAI_API_KEY"}); o.Source != SourceNone {
+		t.Errorf("Locate(openai) = %+v, want the project .env ignored", o)
+	}
+	k, ok := MatchImported(ProjectKeys(), "openai", Lookup{Env: "OPENAI_API_KEY"})
+	if !ok || k.Key != "sk-from-repo" || k.Path != env {
+		t.Errorf("ProjectKeys match = %+v, %v", k, ok)
+	}
+}
+
+func TestImportersAreCached(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("YEET_KEYRING_BACKEND", BackendFile)
+	t.Setenv("XDG_DATA_HOME", dir)
+	t.Setenv("HOME", dir)
+	original := Importers()
+	t.Cleanup(func() {
+		importersMu.Lock()
+		importers = original
+		importersMu.Unlock()
+		resetImported()
+	})
+
+	loads := 0
+	RegisterImporter(Importer{Source: "test", Load: func() []ImportedKey {
+		loads++
+		return []ImportedKey{{Name: "cachetest", Key: "k"}}
+	}})
+	for range 3 {
+		if o := Locate("cachetest", Lookup{}); o.Key != "k" {
+			t.Fatalf("Locate = %+v", o)
+		}
+	}
+	Locate("othertest", Lookup{})
+	if loads != 1 {
+		t.Errorf("importer loaded %d times, want once", loads)
+	}
+}
@@ -1,11 +1,10 @@
 package keyring
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
-	"path/filepath"
 
-	"github.com/rasalas/yeet/internal/xdg"
 	gokeyring "github.com/zalando/go-keyring"
 )
 
@@ -15,131 +14,153 @@ const serviceName = "yeet"
 type KeySource string
 
 const (
+	SourceCommand  KeySource = "key_cmd"
 	SourceKeyring  KeySource = "keyring"
 	SourceEnv      KeySource = "env"
 	SourceOpenCode KeySource = "opencode"
+	SourceLLM      KeySource = "llm"
+	SourceAider    KeySource = "aider"
+	SourceContinue KeySource = "continue"
+	SourceShell    KeySource = "shell rc"
 	SourceNone     KeySource = ""
 )
 
+// Lookup holds a provider's configured key sources besides the keyring.
+type Lookup struct {
+	Env string // env var name (overrides the default mapping)
+	Cmd string // shell command printing the key, e.g. "pass show yeet/groq"
+	// Slot selects a named key (see SetSlot). A selected slot that is not
+	// stored ends the lookup, so another account's key is never used by accident.
+	Slot string
+}
+
 
-// Set stores a key in the OS keyring.
+// Set stores the provider's default key in the active backend.
 func Set(provider, apiKey string) error {
-	return gokeyring.Set(serviceName, provider, apiKey)
+	return SetSlot(provider, DefaultSlot, apiKey)
+}
+
+// Origin describes a resolved key and where it was found.
+type Origin struct {
+	Key    string
+	Source KeySource
+	Where  string // backend location, env var name or file path
+	Slot   string // key slot, for keys from the keyring
 }
 
 // Resolve finds a key for the provider using the lookup chain:
-//  1. OS Keyring
-//  2. Env var (customEnv overrides the default mapping)
-//  3. OpenCode auth.json (only type:"api" keys)
-func Resolve(provider, customEnv string) (string, KeySource) {
-	// 1. Keyring
-	key, err := gokeyring.Get(serviceName, provider)
-	if err == nil && key != "" {
-		return key, SourceKeyring
+//  1. key_cmd (explicitly configured, so it wins over stored keys)
+//  2. Keyring (the active backend: OS keyring or encrypted file)
+//  3. Env var
+//  4. Other tools' configs, in Importers order (OpenCode, llm, aider, …)
+func Resolve(provider string, lookup Lookup) (string, KeySource) {
+	o := Locate(provider, lookup)
+	return o.Key, o.Source
+}
+
+// Locate is Resolve with the location of the key, e.g. for import previews.
+func Locate(provider string, lookup Lookup) Origin {
+	// 1. key_cmd
+	if lookup.Cmd != "" {
+		if cmdKey, err := RunKeyCmd(lookup.Cmd); err == nil {
+			return Origin{Key: cmdKey, Source: SourceCommand, Where: lookup.Cmd}
+		}
+	}
+
+	// 2. Keyring
+	slot := lookup.Slot
+	if slot == "" {
+		slot = DefaultSlot
+	}
+	if b, err := Active(); err == nil {
+		if key, err := b.Get(account(provider, slot)); err == nil && key != "" {
+			return Origin{Key: key, Source: SourceKeyring, Where: b.Location(), Slot: slot}
+		}
+	}
+	if slot != DefaultSlot {
+		return Origin{}
 	}
 
-	// 2. Env var
-	if customEnv != "" {
-		if envKey := os.Getenv(customEnv); envKey != "" {
-			return envKey, SourceEnv
+	// 3. Env var
+	if lookup.Env != "" {
+		if envKey := os.Getenv(lookup.Env); envKey != "" {
+			return Origin{Key: envKey, Source: SourceEnv, Where: "$" + lookup.Env}
 		}
 	}
 
-	// 3. OpenCode auth.json
-	if ocKey := readOpenCodeKey(provider); ocKey != "" {
-		return ocKey, SourceOpenCode
+	// 4. Other tools
+	for _, imp := range importedByTools() {
+		if k, ok := MatchImported(imp.keys, provider, lookup); ok {
+			return Origin{Key: k.Key, Source: imp.source, Where: k.Path}
+		}
 	}
 
-	return "", SourceNone
+	return Origin{}
 }
 
-// GetWithEnv retrieves a key using a custom env var name.
-func GetWithEnv(provider, customEnv string) (string, error) {
-	key, source := Resolve(provider, customEnv)
+// Get retrieves a key through the lookup chain.
+func Get(provider string, lookup Lookup) (string, error) {
+	key, source := Resolve(provider, lookup)
 	if source == SourceNone {
 		return "", gokeyring.ErrNotFound
 	}
 	return key, nil
 }
 
-// Delete removes a key from the OS keyring.
+// Delete removes the provider's default key from the active backend.
 func Delete(provider string) error {
-	return gokeyring.Delete(serviceName, provider)
+	return DeleteSlot(provider, DefaultSlot)
+}
+
+// Migrate moves the given keyring accounts (see Accounts) from one backend
+// to another and returns the accounts that were moved. Keys already in to
+// are overwritten.
+func Migrate(from, to Backend, accounts []string) ([]string, error) {
+	var moved []string
+	for _, p := range accounts {
+		key, err := from.Get(p)
+		if errors.Is(err, gokeyring.ErrNotFound) || key == "" && err == nil {
+			continue
+		}
+		if err != nil {
+			return moved, fmt.Errorf("%s: %w", p, err)
+		}
+		if err := to.Set(p, key); err != nil {
+			return moved, fmt.Errorf("%s: %w", p, err)
+		}
+		if err := from.Delete(p); err != nil {
+			return moved, fmt.Errorf("%s: copied, but not removed from %s: %w", p, from.Name(), err)
+		}
+		moved = append(moved, p)
+	}
+	return moved, nil
 }
 
 // KeyInfo holds the availability and source of a key.
+// CmdErr is set when a configured key_cmd failed.
 type KeyInfo struct {
 	Found  bool
 	Source KeySource
+	Slot   string
+	CmdErr error
 }
 
 // Status returns key availability and source for the given providers.
-// customEnvs maps provider names to custom env var names (for custom providers).
-func Status(providers []string, customEnvs map[string]string) map[string]KeyInfo {
+// lookups maps provider names to their env var and key_cmd.
+func Status(providers []string, lookups map[string]Lookup) map[string]KeyInfo {
 	status := make(map[string]KeyInfo, len(providers))
 	for _, p := range providers {
-		key, source := Resolve(p, customEnvs[p])
-		status[p] = KeyInfo{
-			Found:  key != "",
-			Source: source,
+		l := lookups[p]
+		o := Locate(p, l)
+		info := KeyInfo{
+			Found:  o.Key != "",
+			Source: o.Source,
+			Slot:   o.Slot,
 		}
-	}
-	return status
-}
-
-// openCodeAuth represents an entry in OpenCode's auth.json.
-type openCodeAuth struct {
-	Type string `json:"type"`
-	Key  string `json:"key"`
-}
-
-func openCodeAuthPath() string {
-	dir, err := xdg.DataDir()
-	if err != nil {
-		return ""
-	}
-	return filepath.Join(dir, "opencode", "auth.json")
-}
-
-func loadOpenCodeAuth() map[string]openCodeAuth {
-	path := openCodeAuthPath()
-	if path == "" {
-		return nil
-	}
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil
-	}
-	var auth map[string]openCodeAuth
-	if err := json.Unmarshal(data, &auth); err != nil {
-		return nil
-	}
-	return auth
-}
-
-// readOpenCodeKey reads a key from ~/.local/share/opencode/auth.json.
-// Only returns keys with type:"api", ignoring oauth tokens.
-func readOpenCodeKey(provider string) string {
-	auth := loadOpenCodeAuth()
-	entry, ok := auth[provider]
-	if !ok {
-		return ""
-	}
-	if entry.Type != "api" {
-		return ""
-	}
-	return entry.Key
-}
-
-// OpenCodeProviders returns provider names that have type:"api" keys
-// in OpenCode's auth.json.
-func OpenCodeProviders() []string {
-	auth := loadOpenCodeAuth()
-	var providers []string
-	for name, entry := range auth {
-		if entry.Type == "api" && entry.Key != "" {
-			providers = append(providers, name)
+		if l.Cmd != "" && o.Source != SourceCommand {
+			_, info.CmdErr = RunKeyCmd(l.Cmd)
 		}
+		status[p] = info
 	}
-	return providers
+	return status
 }
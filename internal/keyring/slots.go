@@ -0,0 +1,212 @@
+package keyring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rasalas/yeet/internal/xdg"
+)
+
+// DefaultSlot names the unnamed key of a provider, stored under the plain
+// provider name for compatibility with keys set before slots existed.
+const DefaultSlot = "default"
+
+var slotNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidSlotName reports whether name can be used for a key slot.
+func ValidSlotName(name string) error {
+	if !slotNameRe.MatchString(name) {
+		return fmt.Errorf("invalid key name %q (use lowercase letters, digits, - and _)", name)
+	}
+	return nil
+}
+
+// account returns the keyring account of a provider's slot:
+// "anthropic" for the default slot, "anthropic:work" for a named one.
+func account(provider, slot string) string {
+	if slot == "" || slot == DefaultSlot {
+		return provider
+	}
+	return provider + ":" + slot
+}
+
+// SlotInfo describes a stored key slot. The OS keyring cannot list its
+// entries, so slots are tracked in a small state file next to the database.
+type SlotInfo struct {
+	Name     string    `json:"-"`
+	Added    time.Time `json:"added"`
+	LastUsed time.Time `json:"last_used,omitzero"`
+}
+
+var slotsMu sync.Mutex
+
+func slotsPath() string {
+	dir, err := xdg.DataDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "yeet", "key-slots.json")
+}
+
+func loadSlots() map[string]map[string]SlotInfo {
+	state := map[string]map[string]SlotInfo{}
+	if data, err := os.ReadFile(slotsPath()); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+func saveSlots(state map[string]map[string]SlotInfo) error {
+	path := slotsPath()
+	if path == "" {
+		return fmt.Errorf("cannot determine data directory")
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivate(path, data)
+}
+
+// updateSlots applies fn to the slot state and saves it.
+func updateSlots(fn func(state map[string]map[string]SlotInfo)) error {
+	slotsMu.Lock()
+	defer slotsMu.Unlock()
+	state := loadSlots()
+	fn(state)
+	return saveSlots(state)
+}
+
+// Slots returns the known slots of a provider, default first, then by name.
+func Slots(provider string) []SlotInfo {
+	slotsMu.Lock()
+	defer slotsMu.Unlock()
+	var slots []SlotInfo
+	for name, info := range loadSlots()[provider] {
+		info.Name = name
+		slots = append(slots, info)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		if (slots[i].Name == DefaultSlot) != (slots[j].Name == DefaultSlot) {
+			return slots[i].Name == DefaultSlot
+		}
+		return slots[i].Name < slots[j].Name
+	})
+	return slots
+}
+
+// Stored returns the names with keys recorded in the state file, sorted.
+// Besides AI providers these include forge hosts (see yeet auth forge).
+func Stored() []string {
+	slotsMu.Lock()
+	defer slotsMu.Unlock()
+	var names []string
+	for name := range loadSlots() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Accounts expands providers into all their keyring accounts, including
+// named slots, e.g. for migrating or resetting every stored key.
+func Accounts(providers []string) []string {
+	state := loadSlots()
+	var accounts []string
+	for _, p := range providers {
+		accounts = append(accounts, p)
+		var names []string
+		for name := range state[p] {
+			if name != DefaultSlot {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			accounts = append(accounts, account(p, name))
+		}
+	}
+	return accounts
+}
+
+// SetSlot stores a key in a named slot of the active backend.
+func SetSlot(provider, slot, apiKey string) error {
+	if slot == "" {
+		slot = DefaultSlot
+	}
+	b, err := Active()
+	if err != nil {
+		return err
+	}
+	if err := b.Set(account(provider, slot), apiKey); err != nil {
+		return err
+	}
+	return updateSlots(func(state map[string]map[string]SlotInfo) {
+		if state[provider] == nil {
+			state[provider] = map[string]SlotInfo{}
+		}
+		state[provider][slot] = SlotInfo{Added: time.Now().UTC()}
+	})
+}
+
+// DeleteSlot removes a named slot from the active backend.
+func DeleteSlot(provider, slot string) error {
+	if slot == "" {
+		slot = DefaultSlot
+	}
+	b, err := Active()
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(account(provider, slot)); err != nil {
+		return err
+	}
+	return updateSlots(func(state map[string]map[string]SlotInfo) {
+		delete(state[provider], slot)
+		if len(state[provider]) == 0 {
+			delete(state, provider)
+		}
+	})
+}
+
+// DeleteAll removes the default key and every named slot of a provider and
+// returns the names of the removed slots.
+func DeleteAll(provider string) []string {
+	names := []string{DefaultSlot}
+	for _, s := range Slots(provider) {
+		if s.Name != DefaultSlot {
+			names = append(names, s.Name)
+		}
+	}
+	var removed []string
+	for _, name := range names {
+		if err := DeleteSlot(provider, name); err == nil {
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
+
+// MarkUsed records that a provider's slot paid for a request.
+func MarkUsed(provider, slot string) {
+	if slot == "" {
+		return
+	}
+	updateSlots(func(state map[string]map[string]SlotInfo) {
+		if state[provider] == nil {
+			state[provider] = map[string]SlotInfo{}
+		}
+		info := state[provider][slot]
+		if info.Added.IsZero() {
+			info.Added = time.Now().UTC()
+		}
+		info.LastUsed = time.Now().UTC()
+		state[provider][slot] = info
+	})
+}
@@ -0,0 +1,64 @@
+package keyring
+
+import "testing"
+
+func TestSlots(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("YEET_KEYRING_BACKEND", BackendFile)
+	t.Setenv("YEET_KEYRING_PASSPHRASE", "")
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	if err := Set("anthropic", "sk-personal"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetSlot("anthropic", "work", "sk-work"); err != nil {
+		t.Fatal(err)
+	}
+
+	if o := Locate("anthropic", Lookup{}); o.Key != "sk-personal" || o.Slot != DefaultSlot {
+		t.Errorf("default lookup = %+v", o)
+	}
+	if o := Locate("anthropic", Lookup{Slot: "work"}); o.Key != "sk-work" || o.Slot != "work" {
+		t.Errorf("work lookup = %+v", o)
+	}
+	// A selected slot that is missing must not fall back to the default key.
+	if o := Locate("anthropic", Lookup{Slot: "other"}); o.Source != SourceNone {
+		t.Errorf("missing slot lookup = %+v, want none", o)
+	}
+
+	slots := Slots("anthropic")
+	if len(slots) != 2 || slots[0].Name != DefaultSlot || slots[1].Name != "work" {
+		t.Fatalf("Slots = %+v", slots)
+	}
+	if !slots[1].LastUsed.IsZero() {
+		t.Error("new slot has a last-used time")
+	}
+	MarkUsed("anthropic", "work")
+	if s := Slots("anthropic")[1]; s.LastUsed.IsZero() || s.Added.IsZero() {
+		t.Errorf("after MarkUsed = %+v", s)
+	}
+
+	if got := Accounts([]string{"anthropic", "groq"}); len(got) != 3 || got[1] != "anthropic:work" {
+		t.Errorf("Accounts = %v", got)
+	}
+
+	if removed := DeleteAll("anthropic"); len(removed) != 2 {
+		t.Errorf("DeleteAll removed %v", removed)
+	}
+	if len(Slots("anthropic")) != 0 {
+		t.Error("slots left after DeleteAll")
+	}
+}
+
+func TestValidSlotName(t *testing.T) {
+	for _, name := range []string{"work", "team-2", "a_b"} {
+		if err := ValidSlotName(name); err != nil {
+			t.Errorf("ValidSlotName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "Work", "a:b", "-x", "a b"} {
+		if ValidSlotName(name) == nil {
+			t.Errorf("ValidSlotName(%q) = nil, want error", name)
+		}
+	}
+}
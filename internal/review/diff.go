@@ -0,0 +1,93 @@
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkRe matches a hunk header and captures the old and new files' start
+// lines.
+var hunkRe = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// Annotate prefixes the added and context lines of a unified diff with
+// their line numbers in the new file, so the model doesn't have to count.
+// Removed lines get a blank prefix of the same width.
+func Annotate(diff string) string {
+	var b strings.Builder
+	line, inHunk := 0, false
+	for _, l := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(l, "diff --git "):
+			inHunk = false
+		case hunkRe.MatchString(l):
+			line, _ = strconv.Atoi(hunkRe.FindStringSubmatch(l)[2])
+			inHunk = true
+		case inHunk && (strings.HasPrefix(l, "+") || strings.HasPrefix(l, " ")):
+			fmt.Fprintf(&b, "%5d %s\n", line, l)
+			line++
+			continue
+		case inHunk && strings.HasPrefix(l, "-"):
+			fmt.Fprintf(&b, "%5s %s\n", "", l)
+			continue
+		}
+		b.WriteString(l + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// DiffLines maps each file of a unified diff to the new-file lines shown
+// in its hunks — the lines forges accept review comments on — and those
+// to their line in the old file, or 0 for added lines.
+type DiffLines map[string]map[int]int
+
+// ParseDiff collects the commentable lines of a unified diff. Deleted
+// files have none.
+func ParseDiff(diff string) DiffLines {
+	lines := DiffLines{}
+	var file string
+	oldLine, line, inHunk := 0, 0, false
+	for _, l := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(l, "diff --git "):
+			file, inHunk = "", false
+		case !inHunk && strings.HasPrefix(l, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(l, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case hunkRe.MatchString(l):
+			m := hunkRe.FindStringSubmatch(l)
+			oldLine, _ = strconv.Atoi(m[1])
+			line, _ = strconv.Atoi(m[2])
+			inHunk = true
+		case inHunk && file != "" && strings.HasPrefix(l, "-"):
+			oldLine++
+		case inHunk && file != "" && (strings.HasPrefix(l, "+") || strings.HasPrefix(l, " ")):
+			if lines[file] == nil {
+				lines[file] = map[int]int{}
+			}
+			if strings.HasPrefix(l, " ") {
+				lines[file][line] = oldLine
+				oldLine++
+			} else {
+				lines[file][line] = 0
+			}
+			line++
+		}
+	}
+	return lines
+}
+
+// Contains reports whether a comment can be anchored at file:line.
+func (d DiffLines) Contains(file string, line int) bool {
+	_, ok := d[file][line]
+	return ok
+}
+
+// OldLine returns the old-file line of an unchanged line, or 0 for added
+// lines and lines outside the diff.
+func (d DiffLines) OldLine(file string, line int) int {
+	return d[file][line]
+}
@@ -0,0 +1,110 @@
+// Package review parses and exports AI code review findings.
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Severities, most severe first.
+const (
+	High   = "high"
+	Medium = "medium"
+	Low    = "low"
+)
+
+// Severities lists the severities, most severe first.
+var Severities = []string{High, Medium, Low}
+
+// Finding is one review comment on a line of the diff.
+type Finding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"` // line in the new version of File; 0 for the whole file
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Parse reads the findings from a model reply: a JSON array, possibly
+// wrapped in a code fence or surrounded by prose. Severities are
+// normalized; unknown ones become Low.
+func Parse(reply string) ([]Finding, error) {
+	start := strings.Index(reply, "[")
+	end := strings.LastIndex(reply, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON array in review reply")
+	}
+	var findings []Finding
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &findings); err != nil {
+		return nil, fmt.Errorf("cannot parse review reply: %w", err)
+	}
+	findings = slices.DeleteFunc(findings, func(f Finding) bool { return strings.TrimSpace(f.Message) == "" })
+	for i := range findings {
+		f := &findings[i]
+		f.Severity = normalize(f.Severity)
+		f.Message = strings.TrimSpace(f.Message)
+	}
+	Sort(findings)
+	return findings, nil
+}
+
+// normalize maps the severity names models like to use onto Severities.
+func normalize(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "high", "critical", "blocker", "error", "major":
+		return High
+	case "medium", "moderate", "warning":
+		return Medium
+	}
+	return Low
+}
+
+// rank orders severities: 0 for High, larger for less severe.
+func rank(severity string) int {
+	if i := slices.Index(Severities, severity); i >= 0 {
+		return i
+	}
+	return len(Severities)
+}
+
+// AtLeast reports whether severity is as severe as min or more.
+func AtLeast(severity, min string) bool {
+	return rank(severity) <= rank(min)
+}
+
+// Count returns how many findings are at least as severe as min.
+func Count(findings []Finding, min string) int {
+	n := 0
+	for _, f := range findings {
+		if AtLeast(f.Severity, min) {
+			n++
+		}
+	}
+	return n
+}
+
+// Sort orders findings by file, then line, then severity.
+func Sort(findings []Finding) {
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return rank(a.Severity) - rank(b.Severity)
+	})
+}
+
+// ByFile groups sorted findings by file, keeping the file order.
+func ByFile(findings []Finding) (files []string, groups map[string][]Finding) {
+	groups = map[string][]Finding{}
+	for _, f := range findings {
+		if _, ok := groups[f.File]; !ok {
+			files = append(files, f.File)
+		}
+		groups[f.File] = append(groups[f.File], f)
+	}
+	return files, groups
+}
@@ -0,0 +1,162 @@
+package review
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	reply := "Here is the review:\n```json\n[\n" +
+		`{"file": "b.go", "line": 3, "severity": "nit", "message": "naming"},` +
+		`{"file": "a.go", "line": 10, "severity": "warning", "message": " unchecked error "},` +
+		`{"file": "a.go", "line": 2, "severity": "Critical", "message": "nil dereference"},` +
+		`{"file": "a.go", "line": 5, "severity": "high", "message": ""}` +
+		"\n]\n```"
+	got, err := Parse(reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Finding{
+		{File: "a.go", Line: 2, Severity: High, Message: "nil dereference"},
+		{File: "a.go", Line: 10, Severity: Medium, Message: "unchecked error"},
+		{File: "b.go", Line: 3, Severity: Low, Message: "naming"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse = %+v\nwant %+v", got, want)
+	}
+
+	if got, err := Parse("No issues found.\n[]"); err != nil || len(got) != 0 {
+		t.Errorf("Parse(empty) = %+v, %v", got, err)
+	}
+	if _, err := Parse("Looks good to me!"); err == nil {
+		t.Error("Parse(prose) succeeded, want error")
+	}
+}
+
+func TestCount(t *testing.T) {
+	findings := []Finding{{Severity: High}, {Severity: Medium}, {Severity: Low}, {Severity: Medium}}
+	for min, want := range map[string]int{High: 1, Medium: 3, Low: 4} {
+		if got := Count(findings, min); got != want {
+			t.Errorf("Count(%s) = %d, want %d", min, got, want)
+		}
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	data, err := SARIF([]Finding{
+		{File: "a.go", Line: 2, Severity: High, Message: "nil dereference"},
+		{File: "go.mod", Severity: Low, Message: "outdated"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 2 {
+		t.Fatalf("SARIF = %s", data)
+	}
+	first, second := log.Runs[0].Results[0], log.Runs[0].Results[1]
+	if first.Level != "error" || first.Locations[0].PhysicalLocation.Region.StartLine != 2 {
+		t.Errorf("first result = %+v", first)
+	}
+	if second.Level != "note" || second.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("file-level result = %+v", second)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	diff := `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -10,3 +10,4 @@ func f() {
+ 	x := 1
+-	y := 2
++	y := 3
++	z := 4
+ 	return
+diff --git a/b.go b/b.go
+@@ -1 +1 @@
+--- old comment
++++ new comment`
+	want := `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -10,3 +10,4 @@ func f() {
+   10  	x := 1
+      -	y := 2
+   11 +	y := 3
+   12 +	z := 4
+   13  	return
+diff --git a/b.go b/b.go
+@@ -1 +1 @@
+      --- old comment
+    1 +++ new comment`
+	if got := Annotate(diff); got != want {
+		t.Errorf("Annotate =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseDiff(t *testing.T) {
+	diff := `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -10,3 +10,4 @@ func f() {
+ 	x := 1
+-	y := 2
++	y := 3
++	z := 4
+@@ -40,2 +41,2 @@
+-	old()
++	new()
+diff --git a/gone.go b/gone.go
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package gone
+diff --git a/b.go b/b.go
+--- a/b.go
++++ b/b.go
+@@ -1 +1 @@
+--- old comment
++++ new comment`
+	d := ParseDiff(diff)
+	for _, tc := range []struct {
+		file string
+		line int
+		want bool
+	}{
+		{"a.go", 10, true}, {"a.go", 12, true}, {"a.go", 13, false},
+		{"a.go", 41, true}, {"a.go", 42, false},
+		{"b.go", 1, true}, {"gone.go", 1, false}, {"new comment", 1, false},
+	} {
+		if got := d.Contains(tc.file, tc.line); got != tc.want {
+			t.Errorf("Contains(%s, %d) = %v, want %v", tc.file, tc.line, got, tc.want)
+		}
+	}
+	if old := d.OldLine("a.go", 10); old != 10 {
+		t.Errorf("OldLine(a.go, 10) = %d, want 10", old)
+	}
+	if old := d.OldLine("a.go", 11); old != 0 {
+		t.Errorf("OldLine(a.go, 11) = %d, want 0 for an added line", old)
+	}
+}
@@ -0,0 +1,68 @@
+package review
+
+import "encoding/json"
+
+// JSON encodes findings as an indented JSON array.
+func JSON(findings []Finding) ([]byte, error) {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	return json.MarshalIndent(findings, "", "  ")
+}
+
+// sarifLevels maps severities to SARIF result levels.
+var sarifLevels = map[string]string{High: "error", Medium: "warning", Low: "note"}
+
+// SARIF encodes findings as a SARIF 2.1.0 log, which editors and code
+// scanning tools can import.
+func SARIF(findings []Finding) ([]byte, error) {
+	type region struct {
+		StartLine int `json:"startLine"`
+	}
+	type location struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region *region `json:"region,omitempty"`
+		} `json:"physicalLocation"`
+	}
+	type result struct {
+		RuleID  string `json:"ruleId"`
+		Level   string `json:"level"`
+		Message struct {
+			Text string `json:"text"`
+		} `json:"message"`
+		Locations []location `json:"locations"`
+	}
+
+	results := []result{}
+	for _, f := range findings {
+		r := result{RuleID: "yeet-review", Level: sarifLevels[f.Severity]}
+		r.Message.Text = f.Message
+		var loc location
+		loc.PhysicalLocation.ArtifactLocation.URI = f.File
+		if f.Line > 0 {
+			loc.PhysicalLocation.Region = &region{StartLine: f.Line}
+		}
+		r.Locations = []location{loc}
+		results = append(results, r)
+	}
+
+	log := map[string]any{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []any{map[string]any{
+			"tool": map[string]any{"driver": map[string]any{
+				"name":           "yeet",
+				"informationUri": "https://github.com/rasalas/yeet",
+				"rules": []any{map[string]any{
+					"id":               "yeet-review",
+					"shortDescription": map[string]string{"text": "AI code review finding"},
+				}},
+			}},
+			"results": results,
+		}},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
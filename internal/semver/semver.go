@@ -0,0 +1,110 @@
+// Package semver parses and bumps semantic version tags.
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Bump levels, largest first.
+const (
+	Major = "major"
+	Minor = "minor"
+	Patch = "patch"
+)
+
+// Levels lists the bump levels, largest first.
+var Levels = []string{Major, Minor, Patch}
+
+// Version is a semantic version as used in a tag.
+type Version struct {
+	Prefix              string // "v" or ""
+	Major, Minor, Patch int
+	Pre                 string // pre-release, e.g. "rc.1"; "" for a release
+}
+
+var versionRe = regexp.MustCompile(`^(v?)(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// Parse reads a version tag like "v1.2.3" or "1.2.3-rc.1". Build metadata
+// is accepted and dropped.
+func Parse(tag string) (Version, error) {
+	m := versionRe.FindStringSubmatch(strings.TrimSpace(tag))
+	if m == nil {
+		return Version{}, fmt.Errorf("%q is not a semantic version", tag)
+	}
+	v := Version{Prefix: m[1], Pre: m[5]}
+	v.Major, _ = strconv.Atoi(m[2])
+	v.Minor, _ = strconv.Atoi(m[3])
+	v.Patch, _ = strconv.Atoi(m[4])
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Number returns the version without its prefix, e.g. "1.2.3".
+func (v Version) Number() string {
+	return strings.TrimPrefix(v.String(), v.Prefix)
+}
+
+// Bump returns the next version for a change of the given level. Before
+// 1.0.0 breaking changes bump the minor version, as the API is not stable
+// yet; an explicit major bump (explicit is true) still makes 1.0.0.
+//
+// With a pre-release id (e.g. "rc") the result is the first pre-release
+// of the next version, or the next one in a series: v1.2.3 → v1.3.0-rc.1
+// → v1.3.0-rc.2. Without one, a pre-release becomes its release:
+// v1.3.0-rc.2 → v1.3.0.
+func (v Version) Bump(level, pre string, explicit bool) Version {
+	if v.Pre != "" {
+		next := v
+		next.Pre = ""
+		if pre == "" {
+			return next
+		}
+		id, n := splitPre(v.Pre)
+		if id != pre {
+			n = 0
+		}
+		next.Pre = fmt.Sprintf("%s.%d", pre, n+1)
+		return next
+	}
+
+	next := Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	if level == Major && v.Major == 0 && !explicit {
+		level = Minor
+	}
+	switch level {
+	case Major:
+		next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+	case Minor:
+		next.Minor, next.Patch = v.Minor+1, 0
+	default:
+		next.Patch++
+	}
+	if pre != "" {
+		next.Pre = pre + ".1"
+	}
+	return next
+}
+
+// splitPre splits "rc.2" into "rc" and 2. Pre-releases without a number
+// count as 0.
+func splitPre(pre string) (string, int) {
+	i := strings.LastIndex(pre, ".")
+	if i < 0 {
+		return pre, 0
+	}
+	n, err := strconv.Atoi(pre[i+1:])
+	if err != nil {
+		return pre, 0
+	}
+	return pre[:i], n
+}
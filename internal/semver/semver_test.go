@@ -0,0 +1,45 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	v, err := Parse("v1.2.3-rc.1+build.5")
+	if err != nil || v != (Version{Prefix: "v", Major: 1, Minor: 2, Patch: 3, Pre: "rc.1"}) {
+		t.Fatalf("Parse = %+v, %v", v, err)
+	}
+	if v.String() != "v1.2.3-rc.1" || v.Number() != "1.2.3-rc.1" {
+		t.Errorf("String = %q, Number = %q", v.String(), v.Number())
+	}
+	for _, bad := range []string{"v1.2", "release-1", "1.2.3.4"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestBump(t *testing.T) {
+	for _, tc := range []struct {
+		from, level, pre string
+		explicit         bool
+		want             string
+	}{
+		{"v1.2.3", Major, "", false, "v2.0.0"},
+		{"v1.2.3", Minor, "", false, "v1.3.0"},
+		{"v1.2.3", Patch, "", false, "v1.2.4"},
+		{"0.4.1", Major, "", false, "0.5.0"}, // breaking changes before 1.0.0
+		{"0.9.0", Major, "", true, "1.0.0"},  // --bump major
+		{"v1.2.3", Minor, "rc", false, "v1.3.0-rc.1"},
+		{"v1.3.0-rc.1", Minor, "rc", false, "v1.3.0-rc.2"},
+		{"v1.3.0-rc.2", Patch, "", false, "v1.3.0"},
+		{"v1.3.0-beta.3", Minor, "rc", false, "v1.3.0-rc.1"},
+		{"v1.3.0-rc", Minor, "rc", false, "v1.3.0-rc.1"},
+	} {
+		v, err := Parse(tc.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := v.Bump(tc.level, tc.pre, tc.explicit).String(); got != tc.want {
+			t.Errorf("%s.Bump(%s, %q, %t) = %s, want %s", tc.from, tc.level, tc.pre, tc.explicit, got, tc.want)
+		}
+	}
+}
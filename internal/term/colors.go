@@ -24,6 +24,7 @@ var (
 	Dim     = "\033[2m"
 	Red     = "\033[31m"
 	Green   = "\033[32m"
+	Yellow  = "\033[33m"
 	Primary = "\033[38;2;255;140;66m"
 	Reset   = "\033[0m"
 
@@ -41,6 +42,7 @@ func init() {
 		Dim = ""
 		Red = ""
 		Green = ""
+		Yellow = ""
 		Primary = ""
 		Reset = ""
 		MsgBar = ""
@@ -78,6 +78,35 @@ func WaitForYesNo() (bool, error) {
 	}
 }
 
+// WaitForKey waits until one of keys is pressed and returns it. Enter
+// returns '\r' and Escape or Ctrl+C return 'q', whether listed or not.
+func WaitForKey(keys string) (byte, error) {
+	fd := int(os.Stdin.Fd())
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return 'q', fmt.Errorf("failed to set raw terminal: %w", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	buf := make([]byte, 3)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return 'q', err
+		}
+		for i := 0; i < n; i++ {
+			switch c := buf[i]; {
+			case c == 27 || c == 3:
+				return 'q', nil
+			case c == 13 || c == 10:
+				return '\r', nil
+			case strings.IndexByte(keys, c) >= 0:
+				return c, nil
+			}
+		}
+	}
+}
+
 // EditLine runs an inline editor with cursor movement support.
 func EditLine(initial string) (string, error) {
 	fd := int(os.Stdin.Fd())
@@ -0,0 +1,167 @@
+// Package textdiff computes line diffs for previews like `yeet config migrate --dry-run`.
+package textdiff
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Kind marks a diff line as unchanged, removed or added.
+type Kind byte
+
+const (
+	Equal  Kind = ' '
+	Delete Kind = '-'
+	Insert Kind = '+'
+)
+
+// Line is a single line of a diff.
+type Line struct {
+	Kind Kind
+	Text string
+}
+
+// Hunk is a run of changes with surrounding context.
+type Hunk struct {
+	OldStart, NewStart int // 1-based line numbers
+	Lines              []Line
+}
+
+// Lines returns the line-by-line diff between a and b (longest common subsequence).
+func Lines(a, b string) []Line {
+	x, y := split(a), split(b)
+
+	// lcs[i][j] = length of the LCS of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []Line
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			out = append(out, Line{Equal, x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, Line{Delete, x[i]})
+			i++
+		default:
+			out = append(out, Line{Insert, y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		out = append(out, Line{Delete, x[i]})
+	}
+	for ; j < len(y); j++ {
+		out = append(out, Line{Insert, y[j]})
+	}
+	return out
+}
+
+// Hunks groups the changes between a and b, keeping context unchanged lines
+// around each change. It returns nil when a and b are equal.
+func Hunks(a, b string, context int) []Hunk {
+	lines := Lines(a, b)
+
+	var hunks []Hunk
+	var cur *Hunk
+	oldLine, newLine := 1, 1
+	lastChange := -1
+	for i, l := range lines {
+		if l.Kind != Equal {
+			if cur == nil || i-lastChange > 2*context {
+				start := max(i-context, lastChange+1, 0)
+				if cur != nil {
+					cur.Lines = append(cur.Lines, lines[lastChange+1:lastChange+1+context]...)
+					hunks = append(hunks, *cur)
+				}
+				cur = &Hunk{OldStart: oldLine, NewStart: newLine}
+				// Back up to include leading context.
+				for k := start; k < i; k++ {
+					cur.Lines = append(cur.Lines, lines[k])
+					cur.OldStart--
+					cur.NewStart--
+				}
+			} else {
+				cur.Lines = append(cur.Lines, lines[lastChange+1:i]...)
+			}
+			cur.Lines = append(cur.Lines, l)
+			lastChange = i
+		}
+		switch l.Kind {
+		case Equal:
+			oldLine++
+			newLine++
+		case Delete:
+			oldLine++
+		case Insert:
+			newLine++
+		}
+	}
+	if cur != nil {
+		end := min(lastChange+1+context, len(lines))
+		cur.Lines = append(cur.Lines, lines[lastChange+1:end]...)
+		hunks = append(hunks, *cur)
+	}
+	return hunks
+}
+
+// Unified renders a unified diff of a and b with three lines of context.
+func Unified(a, b, oldName, newName string) string {
+	hunks := Hunks(a, b, 3)
+	if len(hunks) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("--- " + oldName + "\n")
+	sb.WriteString("+++ " + newName + "\n")
+	for _, h := range hunks {
+		sb.WriteString(h.Header() + "\n")
+		for _, l := range h.Lines {
+			sb.WriteByte(byte(l.Kind))
+			sb.WriteString(l.Text + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// Header returns the "@@ -a,b +c,d @@" line of the hunk.
+func (h Hunk) Header() string {
+	var oldCount, newCount int
+	for _, l := range h.Lines {
+		if l.Kind != Insert {
+			oldCount++
+		}
+		if l.Kind != Delete {
+			newCount++
+		}
+	}
+	return "@@ -" + span(h.OldStart, oldCount) + " +" + span(h.NewStart, newCount) + " @@"
+}
+
+func span(start, count int) string {
+	if count == 0 {
+		start-- // unified diff convention for empty ranges
+	}
+	return strconv.Itoa(start) + "," + strconv.Itoa(count)
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,48 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	got := Lines("a\nb\nc\n", "a\nc\nd\n")
+	want := []Line{{Equal, "a"}, {Delete, "b"}, {Equal, "c"}, {Insert, "d"}}
+	if len(got) != len(want) {
+		t.Fatalf("Lines() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestHunks(t *testing.T) {
+	var a, b []string
+	for i := 1; i <= 20; i++ {
+		line := strings.Repeat("x", i)
+		a = append(a, line)
+		if i != 3 && i != 17 {
+			b = append(b, line)
+		}
+	}
+	b = append(b, "new")
+
+	hunks := Hunks(strings.Join(a, "\n"), strings.Join(b, "\n"), 2)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2: %+v", len(hunks), hunks)
+	}
+	if got := hunks[0].Header(); got != "@@ -1,5 +1,4 @@" {
+		t.Errorf("first header = %q", got)
+	}
+	if got := hunks[1].Header(); got != "@@ -15,6 +14,6 @@" {
+		t.Errorf("second header = %q", got)
+	}
+}
+
+func TestUnifiedEqual(t *testing.T) {
+	if got := Unified("same\n", "same\n", "a", "b"); got != "" {
+		t.Errorf("Unified() = %q, want empty", got)
+	}
+}
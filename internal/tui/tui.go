@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -43,6 +44,7 @@ type entry struct {
 	label string
 	model string
 	key   keyring.KeyInfo
+	check *ai.KeyCheck // nil until the background key check finishes
 }
 
 type model struct {
@@ -54,6 +56,10 @@ type model struct {
 	height   int
 	quitting bool
 
+	// Active profile after flag/env/match/default selection (see config.SelectProfile).
+	profile       string
+	profileSource string
+
 	// Model picker state
 	picking      bool
 	pickModels   []string // all models (from API or fallback)
@@ -70,6 +76,12 @@ type modelsLoadedMsg struct {
 	err    error
 }
 
+// keyCheckedMsg is sent when a background key check completes.
+type keyCheckedMsg struct {
+	provider string
+	check    ai.KeyCheck
+}
+
 var labels = map[string]string{
 	"auto":       "Auto (cheapest available)",
 	"anthropic":  "Anthropic",
@@ -82,9 +94,10 @@ var labels = map[string]string{
 }
 
 func initialModel() model {
-	cfg, _ := config.Load()
+	// Edit the file as written; profile overrides only apply at runtime.
+	cfg, _ := config.LoadFile()
 	providers := append([]string{"auto"}, cfg.AllProviders()...)
-	keyStatus := keyring.Status(cfg.AllProviders(), cfg.CustomEnvs())
+	keyStatus := keyring.Status(cfg.AllProviders(), cfg.KeyLookups())
 
 	var entries []entry
 	for _, p := range providers {
@@ -110,7 +123,26 @@ func initialModel() model {
 		}
 	}
 
-	return model{cfg: cfg, entries: entries, cursor: cursor}
+	m := model{cfg: cfg, entries: entries, cursor: cursor}
+	m.profile, m.profileSource = cfg.SelectProfile()
+	return m
+}
+
+// nextProfile cycles the default profile: none → each profile (sorted) → none.
+func nextProfile(cfg config.Config) string {
+	names := cfg.ProfileNames()
+	if cfg.Profile == "" {
+		if len(names) == 0 {
+			return ""
+		}
+		return names[0]
+	}
+	for i, name := range names {
+		if name == cfg.Profile && i+1 < len(names) {
+			return names[i+1]
+		}
+	}
+	return ""
 }
 
 func providerModel(cfg config.Config, p string) string {
@@ -120,7 +152,36 @@ func providerModel(cfg config.Config, p string) string {
 	return ""
 }
 
-func (m model) Init() tea.Cmd { return nil }
+// Init checks every found key in the background so the key column can show
+// invalid keys and missing model access.
+func (m model) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, e := range m.entries {
+		if e.key.Found {
+			cmds = append(cmds, m.checkKeyCmd(e.name))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *model) checkKeyCmd(provider string) tea.Cmd {
+	cfg := m.cfg
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return keyCheckedMsg{provider: provider, check: ai.CheckKey(ctx, provider, cfg)}
+	}
+}
+
+// recheckKey clears the entry's check result and checks its key again,
+// e.g. after its model changed.
+func (m *model) recheckKey(i int) tea.Cmd {
+	if !m.entries[i].key.Found {
+		return nil
+	}
+	m.entries[i].check = nil
+	return m.checkKeyCmd(m.entries[i].name)
+}
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -129,6 +190,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 	case modelsLoadedMsg:
 		return m.handleModelsLoaded(msg)
+	case keyCheckedMsg:
+		for i := range m.entries {
+			if m.entries[i].name == msg.provider {
+				m.entries[i].check = &msg.check
+			}
+		}
 	case tea.KeyMsg:
 		if m.picking {
 			return m.updatePicking(msg)
@@ -155,6 +222,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.message = styleSuccess.Render(fmt.Sprintf("  ✓ Provider set to %s", selected.label))
 			}
+		case "p":
+			if len(m.cfg.Profiles) == 0 {
+				m.message = styleHelp.Render("  No profiles defined — add [profiles.<name>] to config.toml")
+				break
+			}
+			m.cfg.Profile = nextProfile(m.cfg)
+			if err := config.Save(m.cfg); err != nil {
+				m.message = styleDanger.Render(fmt.Sprintf("  ✗ Failed to save config: %v", err))
+				break
+			}
+			m.profile, m.profileSource = m.cfg.SelectProfile()
+			switch {
+			case m.profileSource != config.ProfileFromDefault && m.profile != "":
+				m.message = styleWarning.Render(fmt.Sprintf("  Default profile saved, but %s is active via %s", m.profile, m.profileSource))
+			case m.cfg.Profile == "":
+				m.message = styleSuccess.Render("  ✓ Default profile cleared")
+			default:
+				m.message = styleSuccess.Render(fmt.Sprintf("  ✓ Default profile set to %s", m.cfg.Profile))
+			}
 		case "m":
 			e := m.entries[m.cursor]
 			if e.name == "auto" {
@@ -180,6 +266,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.entries[m.cursor].model = def
 				m.message = styleSuccess.Render(fmt.Sprintf("  ✓ %s reset to %s", e.label, def))
+				return m, m.recheckKey(m.cursor)
 			}
 		}
 	}
@@ -304,16 +391,17 @@ func (m *model) updatePicking(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.picking = false
+		m.pickModels = nil
+		m.pickFiltered = nil
+		m.pickFilter = ""
 		e := m.entries[m.cursor]
 		if err := m.saveModel(e.name, chosen); err != nil {
 			m.message = styleDanger.Render(fmt.Sprintf("  ✗ Failed to save config: %v", err))
 		} else {
 			m.entries[m.cursor].model = chosen
 			m.message = styleSuccess.Render(fmt.Sprintf("  ✓ Model for %s set to %s", e.label, chosen))
+			return m, m.recheckKey(m.cursor)
 		}
-		m.pickModels = nil
-		m.pickFiltered = nil
-		m.pickFilter = ""
 	case "backspace":
 		if len(m.pickFilter) > 0 {
 			m.pickFilter = m.pickFilter[:len(m.pickFilter)-1]
@@ -334,6 +422,26 @@ func (m *model) saveModel(provider, newModel string) error {
 	return config.Save(m.cfg)
 }
 
+// keyBadge renders an entry's key column.
+func keyBadge(e entry) string {
+	if !e.key.Found {
+		return styleDanger.Render("✗")
+	}
+	if e.check == nil {
+		return styleSuccess.Render("✓")
+	}
+	switch e.check.Status {
+	case ai.KeyValid:
+		return styleSuccess.Render("✓")
+	case ai.KeyInvalid:
+		return styleDanger.Render("✗ " + string(e.check.Status))
+	case ai.KeyNetwork:
+		return styleHelp.Render("✓ ? " + string(e.check.Status))
+	default:
+		return styleWarning.Render("! " + string(e.check.Status))
+	}
+}
+
 func (m model) View() string {
 	if m.quitting {
 		return ""
@@ -347,6 +455,19 @@ func (m model) View() string {
 	var b strings.Builder
 
 	b.WriteString("\n")
+	if len(m.cfg.Profiles) > 0 || m.profile != "" {
+		b.WriteString(styleTitle.Render("  Profile"))
+		b.WriteString("\n\n")
+		if m.profile == "" {
+			b.WriteString(styleHelp.Render("  (none)"))
+		} else {
+			b.WriteString("  " + styleSelected.Render(m.profile) + styleHelp.Render("  ("+m.profileSource+")"))
+			if p, ok := m.cfg.Profiles[m.profile]; ok && p.Provider != "" {
+				b.WriteString(styleHelp.Render("  → provider " + p.Provider))
+			}
+		}
+		b.WriteString("\n\n")
+	}
 	b.WriteString(styleTitle.Render("  Provider"))
 	b.WriteString("\n\n")
 
@@ -372,15 +493,11 @@ func (m model) View() string {
 			b.WriteString(radioStyle.Render("  "+radio+" ") + styleNormal.Render(e.label))
 		}
 
-		// Key status (simplified: just ✓ or ✗)
+		// Key status: ✓ or ✗, plus the background check result when it failed
 		if e.name != "auto" {
 			entry, inReg := config.Registry[e.name]
 			if !inReg || entry.NeedsAuth {
-				if e.key.Found {
-					b.WriteString("  " + styleSuccess.Render("✓"))
-				} else {
-					b.WriteString("  " + styleDanger.Render("✗"))
-				}
+				b.WriteString("  " + keyBadge(e))
 			}
 		}
 
@@ -424,8 +541,11 @@ func (m model) View() string {
 	b.WriteString("\n")
 	help := helpEntry("↑/↓", "navigate") + styleHelp.Render("  ·  ") +
 		helpEntry("enter", "select") + styleHelp.Render("  ·  ") +
-		helpEntry("m", "model") + styleHelp.Render("  ·  ") +
-		helpEntry("q", "quit")
+		helpEntry("m", "model") + styleHelp.Render("  ·  ")
+	if len(m.cfg.Profiles) > 0 {
+		help += helpEntry("p", "profile") + styleHelp.Render("  ·  ")
+	}
+	help += helpEntry("q", "quit")
 	b.WriteString("  " + help)
 	b.WriteString("\n")
 
@@ -73,11 +73,9 @@ func TestPickIsUseCustom(t *testing.T) {
 
 func TestProviderModel(t *testing.T) {
 	cfg := config.Config{
-		Anthropic: config.ProviderConfig{Model: "claude-haiku-4-5-20251001"},
-		OpenAI:    config.ProviderConfig{Model: "gpt-4o-mini"},
-		Ollama:    config.ProviderConfig{Model: "llama3"},
-		Custom: map[string]config.ProviderConfig{
-			"groq": {Model: "llama-3.3-70b-versatile", URL: "https://api.groq.com/openai/v1", Env: "GROQ_API_KEY"},
+		Providers: map[string]config.ProviderConfig{
+			"openai": {Model: "gpt-4.1"},
+			"groq":   {Model: "llama-3.1-8b-instant"},
 		},
 	}
 
@@ -86,9 +84,9 @@ func TestProviderModel(t *testing.T) {
 		want     string
 	}{
 		{"anthropic", "claude-haiku-4-5-20251001"},
-		{"openai", "gpt-4o-mini"},
+		{"openai", "gpt-4.1"},
 		{"ollama", "llama3"},
-		{"groq", "llama-3.3-70b-versatile"},
+		{"groq", "llama-3.1-8b-instant"},
 	}
 
 	for _, tt := range tests {
@@ -106,3 +104,21 @@ func TestProviderModelUnknown(t *testing.T) {
 		t.Errorf("providerModel(nonexistent) = %q, want empty", got)
 	}
 }
+
+func TestNextProfile(t *testing.T) {
+	cfg := config.Config{
+		Profiles: map[string]config.Profile{"home": {}, "work": {}},
+	}
+
+	want := []string{"home", "work", ""}
+	for _, w := range want {
+		cfg.Profile = nextProfile(cfg)
+		if cfg.Profile != w {
+			t.Fatalf("nextProfile() = %q, want %q", cfg.Profile, w)
+		}
+	}
+
+	if got := nextProfile(config.Config{}); got != "" {
+		t.Errorf("nextProfile() without profiles = %q, want empty", got)
+	}
+}